@@ -0,0 +1,109 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// defaultAccountCount sizes the account universe when a workload leaves AccountCount unset
+const defaultAccountCount = 100000
+
+// AccountUniverse is the fixed set of accounts shared by the account-based
+// workloads. Account i has the same address in every workload built from the
+// same seed, and the hot set is always the lowest indexes, so workloads with
+// different hot counts agree on which accounts are hot.
+type AccountUniverse struct {
+	seed     int64
+	count    int
+	hotCount int
+	hot      [][]byte // precomputed addresses of the hot accounts
+}
+
+// NewAccountUniverse creates a universe of count accounts whose first hotCount
+// accounts are hot. hotCount is clamped to count.
+func NewAccountUniverse(seed int64, count, hotCount int) *AccountUniverse {
+	if count <= 0 {
+		count = defaultAccountCount
+	}
+	hotCount = max(0, minInt(hotCount, count))
+
+	u := &AccountUniverse{seed: seed, count: count, hotCount: hotCount}
+	u.hot = make([][]byte, hotCount)
+	for i := range u.hot {
+		u.hot[i] = u.Address(i)
+	}
+	return u
+}
+
+// newStateAccountUniverse builds the universe the state workloads draw account
+// hashes from, sized and heated the same way as the account workload's so that
+// a blend or mixed run touches the same accounts in both
+func newStateAccountUniverse(cfg WorkloadConfig) *AccountUniverse {
+	hotRatio := cfg.HotAccountRatio
+	if hotRatio == 0 {
+		hotRatio = 0.2
+	}
+	count := cfg.AccountCount
+	if count <= 0 {
+		count = defaultAccountCount
+	}
+	return NewAccountUniverse(cfg.Seed, count, hotAccountCount(cfg, count, hotRatio))
+}
+
+// hotAccountCount sizes the hot set as hotRatio of count accounts unless
+// --hot-account-count fixes it
+func hotAccountCount(cfg WorkloadConfig, count int, hotRatio float64) int {
+	if cfg.HotAccountCount > 0 {
+		return cfg.HotAccountCount
+	}
+	return int(float64(count) * hotRatio)
+}
+
+// Address returns the 20-byte address of account i
+func (u *AccountUniverse) Address(i int) []byte {
+	var raw [16]byte
+	binary.BigEndian.PutUint64(raw[:8], uint64(u.seed))
+	binary.BigEndian.PutUint64(raw[8:], uint64(i))
+	return crypto.Keccak256(raw[:])[12:]
+}
+
+// IsHot reports whether account i belongs to the hot set
+func (u *AccountUniverse) IsHot(i int) bool {
+	return i >= 0 && i < u.hotCount
+}
+
+// Count returns the number of accounts in the universe
+func (u *AccountUniverse) Count() int {
+	return u.count
+}
+
+// HotCount returns the number of hot accounts
+func (u *AccountUniverse) HotCount() int {
+	return u.hotCount
+}
+
+// HotAddresses returns the addresses of the hot accounts, indexed like the universe
+func (u *AccountUniverse) HotAddresses() [][]byte {
+	return u.hot
+}
+
+// Pick returns a hot account with probability hotProb and otherwise a uniformly
+// chosen cold one, falling back to the other set when one is empty
+func (u *AccountUniverse) Pick(rng *rand.Rand, hotProb float64) []byte {
+	cold := u.count - u.hotCount
+	if u.hotCount > 0 && (cold == 0 || rng.Float64() < hotProb) {
+		return u.hot[rng.Intn(u.hotCount)]
+	}
+	return u.Address(u.hotCount + rng.Intn(cold))
+}
+
+// Any returns a uniformly chosen account, hot or cold
+func (u *AccountUniverse) Any(rng *rand.Rand) []byte {
+	i := rng.Intn(u.count)
+	if u.IsHot(i) {
+		return u.hot[i]
+	}
+	return u.Address(i)
+}
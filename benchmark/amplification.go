@@ -0,0 +1,128 @@
+package benchmark
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+// amplificationDivergence is the relative difference between predicted and
+// measured amplification above which the key generation is flagged as not
+// reflecting the transaction model
+const amplificationDivergence = 0.25
+
+// Categories of the database operations a transaction emits
+const (
+	opCategoryAccount = iota
+	opCategoryStorage
+	opCategoryTrie
+	opCategoryPersistence
+	opCategoryBlockCommit
+	opCategoryCount
+)
+
+// amplificationStats compares the operations the transaction model predicts
+// for each transaction (a logical operation) with the writes the write phase
+// actually issued to the database, so operations lost between the model and
+// the database show up in the run
+type amplificationStats struct {
+	txs       uint64
+	predicted [opCategoryCount]uint64 // block commits are not modelled and stay zero
+	executed  [opCategoryCount]atomic.Uint64
+
+	predictedFactorSum float64 // sum of per-transaction TrieAmplificationFactor
+}
+
+// beginTx records the model's prediction for a transaction about to be emitted
+func (a *amplificationStats) beginTx(breakdown DatabaseOperationBreakdown) {
+	a.txs++
+	a.predicted[opCategoryAccount] += uint64(breakdown.AccountOperations)
+	a.predicted[opCategoryStorage] += uint64(breakdown.StorageOperations)
+	a.predicted[opCategoryTrie] += uint64(breakdown.TrieOperations)
+	a.predicted[opCategoryPersistence] += uint64(breakdown.PersistenceOperations)
+	a.predictedFactorSum += breakdown.TrieAmplificationFactor
+}
+
+// execute records one write of category issued to the database
+func (a *amplificationStats) execute(category int) {
+	a.executed[category].Add(1)
+}
+
+// executedOps returns the writes issued per category
+func (a *amplificationStats) executedOps() [opCategoryCount]uint64 {
+	var ops [opCategoryCount]uint64
+	for i := range ops {
+		ops[i] = a.executed[i].Load()
+	}
+	return ops
+}
+
+// trieFactor returns trie operations per account and storage operation
+func trieFactor(ops [opCategoryCount]uint64) float64 {
+	return float64(ops[opCategoryTrie]) / float64(max(ops[opCategoryAccount]+ops[opCategoryStorage], 1))
+}
+
+// txOps sums the operations of every category a transaction emits, leaving
+// out the block commits
+func txOps(ops [opCategoryCount]uint64) uint64 {
+	var total uint64
+	for _, n := range ops[:opCategoryBlockCommit] {
+		total += n
+	}
+	return total
+}
+
+// divergence returns the relative difference of measured from predicted
+func divergence(predicted, measured float64) float64 {
+	if predicted == 0 {
+		if measured == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(measured-predicted) / predicted
+}
+
+// stats reports predicted against measured amplification and warns when they
+// diverge by more than amplificationDivergence
+func (a *amplificationStats) stats() map[string]interface{} {
+	stats := map[string]interface{}{
+		"transactions": a.txs,
+	}
+	if a.txs == 0 {
+		return stats
+	}
+
+	txs := float64(a.txs)
+	executed := a.executedOps()
+	predictedTrie := trieFactor(a.predicted)
+	measuredTrie := trieFactor(executed)
+	predictedPerTx := float64(txOps(a.predicted)) / txs
+	measuredPerTx := float64(txOps(executed)) / txs
+
+	trieDivergence := divergence(predictedTrie, measuredTrie)
+	perTxDivergence := divergence(predictedPerTx, measuredPerTx)
+	matches := trieDivergence <= amplificationDivergence && perTxDivergence <= amplificationDivergence
+
+	stats["predicted_trie_amplification"] = predictedTrie
+	stats["predicted_trie_amplification_per_tx_mean"] = a.predictedFactorSum / txs
+	stats["measured_trie_amplification"] = measuredTrie
+	stats["predicted_ops_per_tx"] = predictedPerTx
+	stats["measured_ops_per_tx"] = measuredPerTx
+	stats["predicted_storage_ops_per_tx"] = float64(a.predicted[opCategoryStorage]) / txs
+	stats["measured_storage_ops_per_tx"] = float64(executed[opCategoryStorage]) / txs
+	stats["block_commit_ops_per_tx"] = float64(executed[opCategoryBlockCommit]) / txs
+	stats["amplification_matches_model"] = matches
+
+	if !matches {
+		log.Warn().
+			Float64("predicted_trie_amplification", predictedTrie).
+			Float64("measured_trie_amplification", measuredTrie).
+			Float64("predicted_ops_per_tx", predictedPerTx).
+			Float64("measured_ops_per_tx", measuredPerTx).
+			Float64("threshold", amplificationDivergence).
+			Msg("Measured operation amplification diverges from the transaction model")
+	}
+	return stats
+}
@@ -0,0 +1,154 @@
+package benchmark
+
+import (
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// BlockBoundaryReporter is implemented by workloads whose keys come in blocks
+// that a node persists atomically, so --apply-batch can commit at the same
+// boundaries
+type BlockBoundaryReporter interface {
+	// BlocksEnded counts the blocks whose last key GenerateKeys has yielded.
+	// It is read right after each key, while generation is paused.
+	BlocksEnded() uint64
+}
+
+// keysToJobs wraps a key sequence into write jobs whose values are generated
+// by the workers, marking the last key of every block when blocks is not nil
+// and the keys to delete when deletes is not nil
+func keysToJobs(keys iter.Seq[[]byte], blocks BlockBoundaryReporter, deletes DeletionReporter) iter.Seq[writeJob] {
+	return func(yield func(writeJob) bool) {
+		var ended uint64
+		if blocks != nil {
+			ended = blocks.BlocksEnded()
+		}
+		for key := range keys {
+			job := writeJob{key: key}
+			if deletes != nil {
+				job.delete = deletes.LastKeyDeleted()
+			}
+			if blocks != nil {
+				if n := blocks.BlocksEnded(); n != ended {
+					job.endsBlock, ended = true, n
+				}
+			}
+			if !yield(job) {
+				return
+			}
+		}
+	}
+}
+
+// batchedWrite is what the write phase counts for a write once it settles
+type batchedWrite struct {
+	key      []byte
+	size     int    // value length
+	checksum uint64 // pairChecksum of the write under --verify-checksums
+}
+
+// applyBatcher funnels the writes of every worker into one shared batch. The
+// worker whose write closes the batch, at a block boundary or once it holds
+// limit writes, applies it, so that write's latency includes the apply just
+// as a node's last write of a block waits for the block to commit.
+type applyBatcher struct {
+	db    Batcher
+	limit int
+
+	mu      sync.Mutex
+	batch   Batch
+	pending []batchedWrite // writes in batch
+	applied uint64         // batches applied
+	writes  uint64         // writes in the applied batches
+	bytes   uint64         // encoded size of the applied batches
+
+	latencies chan time.Duration
+	collector *latencyCollector
+}
+
+// newApplyBatcher returns a batcher closing batches after limit writes, or nil
+// when limit is 0
+func newApplyBatcher(db Database, limit int) *applyBatcher {
+	batcher, ok := db.(Batcher)
+	if limit <= 0 || !ok {
+		return nil
+	}
+	latencies := make(chan time.Duration, 64)
+	return &applyBatcher{
+		db:        batcher,
+		limit:     limit,
+		batch:     batcher.NewBatch(),
+		latencies: latencies,
+		collector: startLatencyCollector("apply", latencies),
+	}
+}
+
+// set adds a write to the shared batch and applies the batch if the write
+// closes it. It returns the writes that settled with err: none while the
+// write waits in the batch, every write of the batch once it is applied, or
+// the write alone when the batch rejects it.
+func (b *applyBatcher) set(write batchedWrite, value []byte, endsBlock bool) ([]batchedWrite, error) {
+	b.mu.Lock()
+	if err := b.batch.Set(write.key, value); err != nil {
+		b.mu.Unlock()
+		return []batchedWrite{write}, err
+	}
+	b.pending = append(b.pending, write)
+	if !endsBlock && b.batch.Count() < b.limit {
+		b.mu.Unlock()
+		return nil, nil
+	}
+	full, settled := b.batch, b.pending
+	b.batch, b.pending = b.db.NewBatch(), nil
+	b.mu.Unlock()
+	return settled, b.apply(full)
+}
+
+// apply persists a closed batch and records its latency and size
+func (b *applyBatcher) apply(batch Batch) error {
+	defer batch.Close()
+	count, size := batch.Count(), batch.Size()
+
+	start := time.Now()
+	if err := batch.Apply(); err != nil {
+		return err
+	}
+	b.latencies <- time.Since(start)
+
+	b.mu.Lock()
+	b.applied++
+	b.writes += uint64(count)
+	b.bytes += uint64(size)
+	b.mu.Unlock()
+	return nil
+}
+
+// close applies the writes left in the shared batch once every worker is
+// done, returning them like set, and reports the applies
+func (b *applyBatcher) close() ([]batchedWrite, error) {
+	var err error
+	if b.batch.Count() > 0 {
+		err = b.apply(b.batch)
+	} else {
+		b.batch.Close()
+	}
+	settled := b.pending
+	b.pending = nil
+	close(b.latencies)
+	b.collector.wait()
+
+	event := log.Info().
+		Uint64("batches", b.applied).
+		Float64("apply_p50_latency_ms", durationMs(b.collector.percentile(50))).
+		Float64("apply_p99_latency_ms", durationMs(b.collector.percentile(99)))
+	if b.applied > 0 {
+		event = event.
+			Float64("batch_writes_mean", float64(b.writes)/float64(b.applied)).
+			Float64("batch_bytes_mean", float64(b.bytes)/float64(b.applied))
+	}
+	event.Msg("Batch apply")
+	return settled, err
+}
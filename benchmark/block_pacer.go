@@ -0,0 +1,90 @@
+package benchmark
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// blockPacer paces the write phase to a fixed block time: the feeder hands out
+// a block's jobs as a burst, waits for the workers to complete them, then
+// sleeps out the rest of the block interval. Block processing time runs from
+// the block's first job to the completion of its last.
+type blockPacer struct {
+	blockTime time.Duration
+
+	inFlight   sync.WaitGroup // jobs of the current block not yet completed
+	blockStart time.Time
+	blocks     uint64
+	overBudget uint64 // blocks that took longer than blockTime
+	total      time.Duration
+	slowest    time.Duration
+}
+
+// newBlockPacer returns a pacer to blockTime, nil when blockTime <= 0
+func newBlockPacer(blockTime time.Duration) *blockPacer {
+	if blockTime <= 0 {
+		return nil
+	}
+	return &blockPacer{blockTime: blockTime}
+}
+
+// begin marks the start of the first block
+func (p *blockPacer) begin() {
+	if p == nil {
+		return
+	}
+	p.blockStart = phaseClock.Now()
+}
+
+// track counts job as part of the current block until the worker running it
+// calls job.done
+func (p *blockPacer) track(job *writeJob) {
+	if p == nil {
+		return
+	}
+	p.inFlight.Add(1)
+	job.block = &p.inFlight
+}
+
+// endBlock waits for the jobs of the block that just ended, records its
+// processing time and sleeps until the next block is due
+func (p *blockPacer) endBlock() {
+	if p == nil {
+		return
+	}
+	p.inFlight.Wait()
+	elapsed := phaseClock.Since(p.blockStart)
+	p.blocks++
+	p.total += elapsed
+	p.slowest = max(p.slowest, elapsed)
+
+	log.Debug().
+		Uint64("block", p.blocks).
+		Dur("processing", elapsed).
+		Dur("budget", p.blockTime).
+		Msg("Block processed")
+
+	if elapsed < p.blockTime {
+		time.Sleep(p.blockTime - elapsed)
+	} else {
+		p.overBudget++
+	}
+	p.blockStart = phaseClock.Now()
+}
+
+// report logs per-block processing time against the block time budget
+func (p *blockPacer) report() {
+	if p == nil || p.blocks == 0 {
+		return
+	}
+	log.Info().
+		Uint64("blocks", p.blocks).
+		Float64("avg_block_ms", durationMs(p.total/time.Duration(p.blocks))).
+		Float64("max_block_ms", durationMs(p.slowest)).
+		Float64("block_time_ms", durationMs(p.blockTime)).
+		Uint64("blocks_over_budget", p.overBudget).
+		Bool("keeps_up", p.overBudget == 0).
+		Msg("Block pacing")
+}
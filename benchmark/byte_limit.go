@@ -0,0 +1,66 @@
+package benchmark
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// byteLimit stops a phase once the value bytes it moved reach a limit, for
+// comparisons in bytes rather than keys when value sizes differ between
+// workloads. The feeder stops at the limit, so the jobs already queued
+// overshoot it by at most a queue of values.
+type byteLimit struct {
+	phase   string
+	limit   uint64
+	moved   atomic.Uint64
+	reached atomic.Bool
+}
+
+// newByteLimit returns the limit of phase, nil when limit <= 0
+func newByteLimit(phase string, limit int64) *byteLimit {
+	if limit <= 0 {
+		return nil
+	}
+	return &byteLimit{phase: phase, limit: uint64(limit)}
+}
+
+// add counts n value bytes moved
+func (b *byteLimit) add(n int) {
+	if b == nil {
+		return
+	}
+	if b.moved.Add(uint64(n)) >= b.limit && !b.reached.Swap(true) {
+		log.Info().Str("phase", b.phase).Str("limit", formatBytes(int64(b.limit))).Msg("Byte limit reached, stopping the phase")
+	}
+}
+
+// reachedLimit reports whether the bytes moved have reached the limit
+func (b *byteLimit) reachedLimit() bool {
+	return b != nil && b.reached.Load()
+}
+
+// report logs the bytes the phase moved and its throughput in MB/s, warning
+// when the phase ran out of keys first
+func (b *byteLimit) report(elapsed time.Duration) {
+	if b == nil {
+		return
+	}
+	moved := b.moved.Load()
+	mbPerSec := float64(0)
+	if elapsed > 0 {
+		mbPerSec = float64(moved) / (1 << 20) / elapsed.Seconds()
+	}
+	event, msg := log.Info(), "Bytes moved under byte limit"
+	if !b.reachedLimit() {
+		event, msg = log.Warn(), "Phase ran out of keys before the byte limit, raise --key-count"
+	}
+	event.
+		Str("phase", b.phase).
+		Uint64("bytes", moved).
+		Uint64("limit_bytes", b.limit).
+		Str("moved", formatBytes(int64(moved))).
+		Float64("mb_per_sec", mbPerSec).
+		Msg(msg)
+}
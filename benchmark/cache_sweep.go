@@ -0,0 +1,137 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CacheSweepConfig defines a sweep of populated keyspace sizes at a fixed
+// block cache size from the cache-sweep subcommand
+type CacheSweepConfig struct {
+	Sizes          []int  // populated keyspace sizes, ascending
+	Reads          int    // reads measured at every size
+	DBPath         string // directory holding one database per size
+	Keep           bool   // keep the databases instead of removing them after each size
+	Output         string // CSV file the results are written to
+	DatabaseType   string
+	BlockCacheSize int64
+	WorkloadType   string // workload whose read pattern is mapped onto the populated keys
+	ValueSize      int
+	Concurrency    int
+	Seed           int64
+	LogFormat      string
+}
+
+// cacheSweepHeader names the columns of the sweep CSV
+var cacheSweepHeader = []string{
+	"keys", "db_size_bytes", "block_cache_bytes", "read_ops_per_sec", "read_p50_ns", "read_p99_ns", "p99_vs_smallest",
+}
+
+// RunCacheSweep populates each keyspace size in turn, measures reads over it
+// with the same block cache and writes one CSV row per size, so the size at
+// which the working set stops fitting in cache shows up as a p99 cliff
+func RunCacheSweep(cfg CacheSweepConfig) error {
+	setupLog(Config{LogFormat: cfg.LogFormat})
+	if len(cfg.Sizes) == 0 {
+		return fmt.Errorf("cache sweep needs at least one size")
+	}
+	for _, size := range cfg.Sizes {
+		if size <= 0 {
+			return fmt.Errorf("invalid sweep size %d: sizes must be positive", size)
+		}
+	}
+
+	f, err := os.Create(cfg.Output)
+	if err != nil {
+		return fmt.Errorf("failed to create sweep output: %w", err)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.Write(cacheSweepHeader); err != nil {
+		return fmt.Errorf("failed to write sweep output: %w", err)
+	}
+
+	var baselineP99 float64
+	for i, size := range cfg.Sizes {
+		path := filepath.Join(cfg.DBPath, fmt.Sprintf("populate-%d", size))
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", path, err)
+		}
+
+		log.Info().Int("keys", size).Int("step", i+1).Int("steps", len(cfg.Sizes)).Msg("Cache sweep step")
+		result, err := runBenchmark(Config{
+			KeyCount:           cfg.Reads,
+			ValueSize:          cfg.ValueSize,
+			Seed:               cfg.Seed,
+			DBPath:             path,
+			BenchmarkID:        fmt.Sprintf("cache-sweep-%d", size),
+			WriteEnabled:       true,
+			Concurrency:        cfg.Concurrency,
+			LogFormat:          cfg.LogFormat,
+			BlockCacheSize:     cfg.BlockCacheSize,
+			DatabaseType:       cfg.DatabaseType,
+			WorkloadType:       cfg.WorkloadType,
+			Populate:           size,
+			FlushBetweenPhases: true,
+		})
+		if err != nil {
+			return fmt.Errorf("sweep size %d: %w", size, err)
+		}
+
+		diskSize, err := directorySize(path)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to measure database disk size")
+		}
+		if !cfg.Keep {
+			if err := os.RemoveAll(path); err != nil {
+				log.Warn().Err(err).Str("path", path).Msg("Failed to remove sweep database")
+			}
+		}
+
+		p99 := float64(result.ReadP99)
+		if i == 0 {
+			baselineP99 = p99
+		}
+		ratio := 0.0
+		if baselineP99 > 0 {
+			ratio = p99 / baselineP99
+		}
+
+		row := []string{
+			strconv.Itoa(size),
+			strconv.FormatInt(diskSize, 10),
+			strconv.FormatInt(cfg.BlockCacheSize, 10),
+			strconv.FormatFloat(result.ReadOpsPerSec, 'f', 1, 64),
+			strconv.FormatInt(int64(result.ReadP50), 10),
+			strconv.FormatInt(int64(result.ReadP99), 10),
+			strconv.FormatFloat(ratio, 'f', 2, 64),
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to write sweep output: %w", err)
+		}
+		// Flush every row so a long sweep leaves usable results if interrupted
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to write sweep output: %w", err)
+		}
+
+		log.Info().
+			Int("keys", size).
+			Str("db_size", formatBytes(diskSize)).
+			Float64("read_ops_per_sec", result.ReadOpsPerSec).
+			Float64("read_p99_latency_ms", durationMs(result.ReadP99)).
+			Float64("p99_vs_smallest", ratio).
+			Msg("Cache sweep step complete")
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write sweep output: %w", err)
+	}
+	log.Info().Str("path", cfg.Output).Int("sizes", len(cfg.Sizes)).Msg("Wrote cache sweep results")
+	return nil
+}
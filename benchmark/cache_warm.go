@@ -0,0 +1,73 @@
+package benchmark
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// HotKeyReporter is implemented by workloads with a hot set, the accounts and
+// contracts most of their accesses go to. --warm-hot-keys reads every key under
+// the returned prefixes before the read phase, the state a long-running node
+// keeps cached.
+type HotKeyReporter interface {
+	// HotKeyPrefixes returns the prefixes under which the hot set's keys live
+	HotKeyPrefixes() [][]byte
+}
+
+// hotAccountPrefixes returns, for every hot account of accounts, its address
+// hash appended to each of prefixes
+func hotAccountPrefixes(accounts *AccountUniverse, prefixes ...[]byte) [][]byte {
+	out := make([][]byte, 0, accounts.HotCount()*len(prefixes))
+	for _, address := range accounts.HotAddresses() {
+		accountHash := crypto.Keccak256(address)
+		for _, prefix := range prefixes {
+			out = append(out, append(append([]byte{}, prefix...), accountHash...))
+		}
+	}
+	return out
+}
+
+// warmHotKeys reads every key under the hot prefixes of reporter through
+// iterators, pulling the hot set into the database's cache, and returns the
+// time taken
+func warmHotKeys(db Database, reporter HotKeyReporter) (time.Duration, error) {
+	prefixes := reporter.HotKeyPrefixes()
+	log.Info().Int("prefixes", len(prefixes)).Msg("Warming hot keys")
+
+	before := db.GetMetrics()
+	start := time.Now()
+	var keys, bytes uint64
+	for _, prefix := range prefixes {
+		it, err := db.NewIterator(prefix, prefixUpperBound(prefix))
+		if err != nil {
+			return 0, fmt.Errorf("failed to open warm-up iterator: %w", err)
+		}
+		for valid := it.First(); valid; valid = it.Next() {
+			keys++
+			bytes += uint64(len(it.Key()) + len(it.Value()))
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read hot keys: %w", err)
+		}
+	}
+	elapsed := time.Since(start)
+	after := db.GetMetrics()
+
+	log.Info().
+		Uint64("keys", keys).
+		Uint64("bytes", bytes).
+		Dur("elapsed", elapsed).
+		Int64("cache_bytes_before", before.CacheSize).
+		Int64("cache_bytes", after.CacheSize).
+		Int64("cache_misses", after.CacheMisses-before.CacheMisses).
+		Msg("Warmed hot keys")
+	if keys == 0 {
+		log.Warn().Msg("No hot keys found, the database holds none of the workload's hot set")
+	}
+	return elapsed, nil
+}
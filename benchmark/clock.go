@@ -0,0 +1,21 @@
+package benchmark
+
+import "time"
+
+// Clock tells the time the phase runners measure latencies and elapsed time
+// with. Tests replace phaseClock with a clock they advance themselves to check
+// the reported percentiles and throughput against known durations.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+// realClock reads the system clock
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// phaseClock is the clock of runWritePhase and runReadPhase
+var phaseClock Clock = realClock{}
@@ -0,0 +1,141 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// contentionPrefix namespaces the contention keyspace away from workload keys
+const contentionPrefix = "contend/"
+
+// contentionSharedKey returns hot key i, shared by every writer
+func contentionSharedKey(i int) []byte {
+	key := make([]byte, len(contentionPrefix)+1+8)
+	copy(key, contentionPrefix)
+	key[len(contentionPrefix)] = 's'
+	binary.BigEndian.PutUint64(key[len(contentionPrefix)+1:], uint64(i))
+	return key
+}
+
+// contentionPrivateKey returns the i-th key owned by a single writer
+func contentionPrivateKey(worker, i int) []byte {
+	key := make([]byte, len(contentionPrefix)+1+4+8)
+	copy(key, contentionPrefix)
+	key[len(contentionPrefix)] = 'p'
+	binary.BigEndian.PutUint32(key[len(contentionPrefix)+1:], uint32(worker))
+	binary.BigEndian.PutUint64(key[len(contentionPrefix)+5:], uint64(i))
+	return key
+}
+
+// contentionWorkerStats is what one writer achieved during the contention phase
+type contentionWorkerStats struct {
+	ops        uint64
+	collisions uint64 // writes that went to the shared hot set
+	failed     uint64
+	elapsed    time.Duration
+}
+
+// runContentionPhase runs cfg.Concurrency writers that each write
+// cfg.ContentionOps keys. With probability cfg.CollisionRate a write targets
+// one of cfg.ContentionHotKeys keys shared by every writer, otherwise a key
+// private to that writer, so concurrent writers overwrite the same keys.
+func runContentionPhase(db Database, cfg Config) (*PhaseResult, error) {
+	writers := max(cfg.Concurrency, 1)
+
+	log.Info().
+		Int("writers", writers).
+		Int("ops_per_writer", cfg.ContentionOps).
+		Float64("collision_rate", cfg.CollisionRate).
+		Int("hot_keys", cfg.ContentionHotKeys).
+		Msg("Beginning contention phase")
+
+	latencies := make(chan time.Duration, queueDepth(cfg))
+	collector := startLatencyCollector("contention", latencies)
+	stats := make([]contentionWorkerStats, writers)
+	shape := newValueShape(cfg)
+	var errs errorSampler
+	var wg sync.WaitGroup
+
+	phaseStart := time.Now()
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			rng := rand.New(rand.NewSource(cfg.Seed + int64(worker)))
+			s := &stats[worker]
+			workerStart := time.Now()
+			for i := 0; i < cfg.ContentionOps; i++ {
+				var key []byte
+				if rng.Float64() < cfg.CollisionRate {
+					key = contentionSharedKey(rng.Intn(cfg.ContentionHotKeys))
+					s.collisions++
+				} else {
+					key = contentionPrivateKey(worker, i)
+				}
+				value := shape.apply(rng, shape.generate(rng, cfg.ValueSize))
+
+				writeStart := time.Now()
+				err := db.Set(key, value)
+				latencies <- time.Since(writeStart)
+
+				s.ops++
+				if err != nil {
+					s.failed++
+					errs.record(err)
+				}
+			}
+			s.elapsed = time.Since(workerStart)
+		}(w)
+	}
+
+	wg.Wait()
+	elapsed := time.Since(phaseStart)
+	close(latencies)
+	collector.wait()
+
+	result := newPhaseResult("contention", collector, elapsed)
+	for _, s := range stats {
+		result.Failed += s.failed
+	}
+	result.Successful = result.Ops - result.Failed
+	result.FirstError = errs.first
+	result.Errors = errs.samples()
+	result.OtherErrors = errs.other
+
+	for worker, s := range stats {
+		rate := float64(0)
+		if s.elapsed > 0 {
+			rate = float64(s.ops) / s.elapsed.Seconds()
+		}
+		log.Info().
+			Int("writer", worker).
+			Uint64("ops", s.ops).
+			Uint64("collisions", s.collisions).
+			Uint64("failed", s.failed).
+			Float64("ops_per_sec", rate).
+			Dur("elapsed", s.elapsed).
+			Msg("Contention writer complete")
+	}
+
+	log.Info().
+		Int("writers", writers).
+		Uint64("ops", result.Ops).
+		Uint64("failed", result.Failed).
+		Float64("ops_per_sec", result.OpsPerSec()).
+		Float64("avg_latency_ms", durationMs(result.AvgLatency())).
+		Float64("p50_latency_ms", durationMs(result.P50)).
+		Float64("p99_latency_ms", durationMs(result.P99)).
+		Dur("elapsed", result.Elapsed).
+		Msg("Contention benchmark complete")
+
+	if err := db.Flush(); err != nil {
+		return nil, fmt.Errorf("contention phase flush failed: %w", err)
+	}
+	return result, nil
+}
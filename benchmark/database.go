@@ -17,8 +17,23 @@ type Database interface {
 	// Get retrieves a value for the given key
 	// Returns the value, a closer (if needed), and error
 	// Returns ErrKeyNotFound if key doesn't exist
+	// Pebble returns a zero-copy view into its block cache pinned until the
+	// closer is closed, while the other backends return a copy, so Pebble reads
+	// skip a copy the others pay unless DatabaseConfig.PebbleCopyValues is set
 	Get(key []byte) ([]byte, io.Closer, error)
 
+	// Delete removes a key from the database
+	// Deleting a missing key is not an error
+	Delete(key []byte) error
+
+	// NewIterator returns an iterator over keys in [start, end)
+	// A nil bound means the range is unbounded on that side
+	NewIterator(start, end []byte) (Iterator, error)
+
+	// Compact forces a compaction of the key range [start, end)
+	// Backends that reclaim space in place may treat this as a no-op
+	Compact(start, end []byte) error
+
 	// Flush ensures all pending writes are persisted to storage
 	// This is crucial for measuring write performance accurately
 	Flush() error
@@ -29,6 +44,100 @@ type Database interface {
 	// GetMetrics returns database-specific performance metrics
 	// This allows comparison of internal stats between backends
 	GetMetrics() DatabaseMetrics
+
+	// Capabilities reports which optional features the backend supports
+	// The runner uses it to skip or reject modes a backend cannot run
+	Capabilities() DatabaseCapabilities
+}
+
+// DatabaseCapabilities lists the optional features of a backend
+type DatabaseCapabilities struct {
+	SupportsBatch    bool // several writes can be applied atomically
+	SupportsSnapshot bool // reads can be served from a point-in-time view
+	SupportsIterator bool // NewIterator walks key ranges
+	SupportsDelete   bool // Delete removes keys
+}
+
+// Quiescer is implemented by backends whose background work, such as LSM
+// compactions, keeps running after Flush returns
+type Quiescer interface {
+	// WaitForQuiesce blocks until no background work is pending or timeout
+	// elapses, returning whether the database settled
+	WaitForQuiesce(timeout time.Duration) (bool, error)
+}
+
+// ValueCopyReporter is implemented by backends that can copy read values out
+// on request, reporting what the copies cost
+type ValueCopyReporter interface {
+	// ValueCopyStats returns how many values Get copied and the time spent copying
+	ValueCopyStats() (copies uint64, elapsed time.Duration)
+}
+
+// ConfigReporter is implemented by backends that can report the configuration
+// they run with once defaults are resolved, so results carry the settings
+// behind them
+type ConfigReporter interface {
+	// EffectiveConfig returns the resolved settings by name
+	EffectiveConfig() map[string]interface{}
+}
+
+// effectiveConfig returns the resolved configuration of db, nil when the
+// backend cannot report it
+func effectiveConfig(db Database) map[string]interface{} {
+	if r, ok := db.(ConfigReporter); ok {
+		return r.EffectiveConfig()
+	}
+	return nil
+}
+
+// Batcher is implemented by backends with SupportsBatch
+type Batcher interface {
+	// NewBatch returns an empty batch of writes
+	NewBatch() Batch
+}
+
+// Batch accumulates writes that Apply persists atomically. A batch is not safe
+// for concurrent use and cannot be reused after Apply.
+type Batch interface {
+	Set(key, value []byte) error
+	// Count returns the number of writes in the batch
+	Count() int
+	// Size returns the encoded size of the batch in bytes
+	Size() int
+	// Apply persists every write in the batch at once
+	Apply() error
+	// Close releases the batch, applied or not
+	Close() error
+}
+
+// Snapshotter is implemented by backends with SupportsSnapshot
+type Snapshotter interface {
+	// NewSnapshot opens a point-in-time view of the database
+	NewSnapshot() (Snapshot, error)
+}
+
+// Snapshot serves reads from the point in time it was opened at, unaffected by
+// later writes. A snapshot is not safe for concurrent use and holds resources,
+// an LSM's snapshot list entry or a B-tree's reader slot, until closed.
+type Snapshot interface {
+	// Get reads key as of the snapshot, like Database.Get
+	Get(key []byte) ([]byte, io.Closer, error)
+	Close() error
+}
+
+// Iterator walks keys within the bounds it was created with, forward from First
+// or backward from Last
+// Key and Value are only valid until the next call that moves the iterator
+type Iterator interface {
+	First() bool
+	Next() bool
+	Last() bool
+	Prev() bool
+	Valid() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Close() error
 }
 
 // DatabaseMetrics provides common metrics across different database backends
@@ -42,11 +151,26 @@ type DatabaseMetrics struct {
 	// I/O statistics  
 	BytesRead     int64 // total bytes read from storage
 	BytesWritten  int64 // total bytes written to storage
+	DiskUsage     uint64 // bytes of live and obsolete files on disk, 0 if unknown
 	
 	// Operation counts
 	CacheHits     int64 // cache hit count
 	CacheMisses   int64 // cache miss count
 	CompactionOps int64 // compaction operations (LSM-specific)
+	L0FileCount   int64 // sstables in L0 (LSM-specific)
+	
+	// Write-ahead log statistics (LSM-specific)
+	WALSize         uint64        // live WAL bytes
+	WALPhysicalSize uint64        // live WAL bytes on disk, including preallocation
+	WALBytesIn      uint64        // logical bytes appended to the WAL
+	WALBytesWritten uint64        // bytes written to the WAL, including record overhead
+	WALFsyncCount   uint64        // number of WAL fsyncs
+	WALFsyncLatency time.Duration // mean WAL fsync latency
+
+	// Write stalls (LSM-specific): writes held back while memtables flush or
+	// L0 compacts
+	WriteStallCount    uint64        // stalls begun
+	WriteStallDuration time.Duration // total time writes were stalled, an ongoing stall included
 	
 	// Performance metrics
 	ReadCount     uint64
@@ -70,6 +194,7 @@ const (
 	DatabaseTypePebble DatabaseType = "pebble"
 	DatabaseTypeQMDB   DatabaseType = "qmdb"
 	DatabaseTypeMDBX   DatabaseType = "mdbx"
+	DatabaseTypeMemory DatabaseType = "memory"
 )
 
 // DatabaseConfig holds configuration for database creation
@@ -78,8 +203,30 @@ type DatabaseConfig struct {
 	Path     string
 	ReadOnly bool
 	
+	// SyncWrites makes every write durable before returning (fsync the WAL)
+	SyncWrites bool
+	
 	// Pebble-specific options
-	BlockCacheSize int64 // bytes, negative means disabled
+	BlockCacheSize int64  // bytes, negative means disabled
+	PebbleComparer string // key ordering: "default" or "blocknum"
+
+	// PebbleDisableWAL skips the write-ahead log, so writes not yet flushed to
+	// sstables are lost on a crash
+	PebbleDisableWAL bool
+
+	// PebbleCopyValues makes Get copy each value out of the block cache and
+	// release it before returning, as the other backends always do
+	PebbleCopyValues bool
+
+	// PebbleMaxOpenFiles and PebbleTableCacheSize override Pebble's limits on
+	// open files and on sstables kept open by the table cache, 0 keeps the default
+	PebbleMaxOpenFiles   int
+	PebbleTableCacheSize int
+
+	// PebbleFlushWaitCompaction makes Flush also wait, up to FlushSettleTimeout,
+	// for the compactions it triggers to drain, so a flushed database is settled
+	PebbleFlushWaitCompaction bool
+	FlushSettleTimeout        time.Duration
 	
 	// QMDB-specific options
 	QMDBConfig QMDBConfig
@@ -126,6 +273,8 @@ func NewDatabase(cfg DatabaseConfig) (Database, error) {
 		return NewQMDBDatabase(cfg)
 	case DatabaseTypeMDBX:
 		return NewMDBXDatabase(cfg)
+	case DatabaseTypeMemory:
+		return NewMemoryDatabase(cfg)
 	default:
 		return nil, ErrBackendNotFound
 	}
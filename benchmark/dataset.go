@@ -0,0 +1,155 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// datasetMagic starts a dataset file written by gen-dataset. Each record after
+// it is [uvarint key length][key bytes][uvarint value length][value bytes].
+var datasetMagic = []byte("\x00pebble-bench-dataset\x00")
+
+// RunGenDataset generates the key/value pairs the run command would write with
+// cfg and saves them to cfg.GenDataset, opening no database
+func RunGenDataset(cfg Config) error {
+	if cfg.GenDataset == "" {
+		return fmt.Errorf("gen-dataset needs an output file")
+	}
+	_, err := runBenchmark(cfg)
+	return err
+}
+
+// genDataset writes cfg.KeyCount pairs of workload to cfg.GenDataset. Values
+// come from one generator seeded with cfg.Seed, so the file is the same on
+// every run with the same flags.
+func genDataset(cfg Config, workload Workload) error {
+	f, err := os.Create(cfg.GenDataset)
+	if err != nil {
+		return fmt.Errorf("failed to create dataset file: %w", err)
+	}
+	defer f.Close()
+
+	start := time.Now()
+	w := bufio.NewWriterSize(f, readerBufferSize)
+	if _, err := w.Write(datasetMagic); err != nil {
+		return fmt.Errorf("failed to write dataset: %w", err)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	shape := newValueShape(cfg)
+	var pairs, keyBytes, valueBytes uint64
+	var lenBuf [binary.MaxVarintLen64]byte
+	for key := range workload.GenerateKeys(cfg.Seed, cfg.KeyCount) {
+		value := generateWorkloadValue(rng, workload, key, shape)
+		for _, field := range [][]byte{key, value} {
+			n := binary.PutUvarint(lenBuf[:], uint64(len(field)))
+			if _, err := w.Write(lenBuf[:n]); err != nil {
+				return fmt.Errorf("failed to write dataset: %w", err)
+			}
+			if _, err := w.Write(field); err != nil {
+				return fmt.Errorf("failed to write dataset: %w", err)
+			}
+		}
+		pairs++
+		keyBytes += uint64(len(key))
+		valueBytes += uint64(len(value))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write dataset: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write dataset: %w", err)
+	}
+
+	log.Info().
+		Str("path", cfg.GenDataset).
+		Str("workload", workload.Name()).
+		Uint64("pairs", pairs).
+		Uint64("key_bytes", keyBytes).
+		Uint64("value_bytes", valueBytes).
+		Dur("elapsed", time.Since(start)).
+		Msg("Generated dataset")
+	return nil
+}
+
+// checkDataset reports an error unless path is a dataset file
+func checkDataset(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer f.Close()
+
+	head := make([]byte, len(datasetMagic))
+	if _, err := io.ReadFull(f, head); err != nil || !bytes.Equal(head, datasetMagic) {
+		return fmt.Errorf("%s is not a dataset written by gen-dataset", path)
+	}
+	return nil
+}
+
+// loadDataset reads the pairs of a dataset file as write jobs carrying their
+// values. checkDataset validates the file up front.
+func loadDataset(path string) iter.Seq[writeJob] {
+	return func(yield func(writeJob) bool) {
+		f, err := os.Open(path)
+		if err != nil {
+			panic(fmt.Errorf("failed to open dataset: %w", err))
+		}
+		defer f.Close()
+
+		r := bufio.NewReaderSize(f, readerBufferSize)
+		if _, err := r.Discard(len(datasetMagic)); err != nil {
+			panic(fmt.Errorf("failed to read dataset: %w", err))
+		}
+		for {
+			key, err := readDatasetField(r)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				panic(fmt.Errorf("failed to read dataset key: %w", err))
+			}
+			value, err := readDatasetField(r)
+			if err != nil {
+				panic(fmt.Errorf("failed to read dataset value: %w", err))
+			}
+			if !yield(writeJob{key: key, value: value}) {
+				return
+			}
+		}
+	}
+}
+
+// datasetKeys yields the keys of a dataset file in order
+func datasetKeys(path string) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for job := range loadDataset(path) {
+			if !yield(job.key) {
+				return
+			}
+		}
+	}
+}
+
+// readDatasetField reads one length-prefixed field, returning io.EOF only at
+// a record boundary
+func readDatasetField(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	field := make([]byte, n)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return field, nil
+}
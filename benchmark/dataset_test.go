@@ -0,0 +1,50 @@
+package benchmark
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDatasetRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dataset.bin")
+	cfg := Config{KeyCount: 100, ValueSize: 64, Seed: 7, GenDataset: path}
+	workloadCfg := WorkloadConfig{ValueSize: cfg.ValueSize, Seed: cfg.Seed}
+	if err := genDataset(cfg, NewGenericWorkload(workloadCfg)); err != nil {
+		t.Fatalf("genDataset: %v", err)
+	}
+	if err := checkDataset(path); err != nil {
+		t.Fatalf("checkDataset: %v", err)
+	}
+
+	workload := NewGenericWorkload(workloadCfg)
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	shape := newValueShape(cfg)
+	var want []writeJob
+	for key := range workload.GenerateKeys(cfg.Seed, cfg.KeyCount) {
+		want = append(want, writeJob{key: key, value: generateWorkloadValue(rng, workload, key, shape)})
+	}
+
+	got := slices.Collect(loadDataset(path))
+	if !slices.EqualFunc(got, want, func(a, b writeJob) bool {
+		return bytes.Equal(a.key, b.key) && bytes.Equal(a.value, b.value)
+	}) {
+		t.Fatalf("loaded %d pairs that differ from the %d generated", len(got), len(want))
+	}
+	if keys := slices.Collect(datasetKeys(path)); len(keys) != len(want) {
+		t.Fatalf("datasetKeys yielded %d keys, want %d", len(keys), len(want))
+	}
+}
+
+func TestCheckDatasetRejectsKeysFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.bin")
+	if err := os.WriteFile(path, []byte{1, 'a'}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkDataset(path); err == nil {
+		t.Fatal("checkDataset accepted a keys file")
+	}
+}
@@ -0,0 +1,118 @@
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+// ANSI colors marking regressions and improvements in the diff
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+// DiffConfig selects the two results files the diff subcommand compares
+type DiffConfig struct {
+	Base      string  // results file of the baseline run
+	Head      string  // results file of the run compared against the baseline
+	Threshold float64 // percentage change below which a difference is not significant
+	NoColor   bool
+}
+
+// diffMetric is one compared metric
+type diffMetric struct {
+	name         string
+	base, head   float64
+	format       func(float64) string
+	higherBetter bool
+}
+
+// diffMetrics lists the metrics compared between two results
+func diffMetrics(base, head *BenchmarkResult) []diffMetric {
+	ops := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	latency := func(v float64) string { return fmt.Sprintf("%.3fms", v/1e6) }
+	size := func(v float64) string { return formatBytes(int64(v)) }
+	ratio := func(v float64) string { return fmt.Sprintf("%.2f%%", v*100) }
+
+	return []diffMetric{
+		{"write ops/s", base.WriteOpsPerSec, head.WriteOpsPerSec, ops, true},
+		{"write p50", float64(base.WriteP50), float64(head.WriteP50), latency, false},
+		{"write p99", float64(base.WriteP99), float64(head.WriteP99), latency, false},
+		{"read ops/s", base.ReadOpsPerSec, head.ReadOpsPerSec, ops, true},
+		{"read p50", float64(base.ReadP50), float64(head.ReadP50), latency, false},
+		{"read p99", float64(base.ReadP99), float64(head.ReadP99), latency, false},
+		{"disk size", float64(base.DiskSizeBytes), float64(head.DiskSizeBytes), size, false},
+		{"cache hit ratio", base.CacheHitRatio, head.CacheHitRatio, ratio, true},
+		{"write stall time", float64(base.WriteStallTime), float64(head.WriteStallTime), latency, false},
+	}
+}
+
+// RunDiff prints the percentage change of every metric from the base results
+// file to the head one, marking significant regressions and improvements
+func RunDiff(cfg DiffConfig) error {
+	base, err := readResultsFile(cfg.Base)
+	if err != nil {
+		return err
+	}
+	head, err := readResultsFile(cfg.Head)
+	if err != nil {
+		return err
+	}
+	return printDiff(os.Stdout, cfg, base, head)
+}
+
+// printDiff renders the comparison of base and head as a table
+func printDiff(out io.Writer, cfg DiffConfig, base, head *BenchmarkResult) error {
+	if base.Backend != head.Backend || base.Workload != head.Workload || base.KeyCount != head.KeyCount {
+		fmt.Fprintf(out, "warning: comparing %s/%s/%d keys against %s/%s/%d keys\n\n",
+			base.Backend, base.Workload, base.KeyCount, head.Backend, head.Workload, head.KeyCount)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "METRIC\tBASE (%s)\tHEAD (%s)\tCHANGE\n", base.BenchmarkID, head.BenchmarkID)
+	regressions := 0
+	for _, m := range diffMetrics(base, head) {
+		// Metrics a run did not measure, such as reads of a write-only run
+		if m.base == 0 && m.head == 0 {
+			continue
+		}
+
+		change, verdict := "n/a", ""
+		if m.base != 0 {
+			pct := (m.head - m.base) / m.base * 100
+			change = fmt.Sprintf("%+.1f%%", pct)
+
+			better := pct > 0 == m.higherBetter
+			switch {
+			case pct < cfg.Threshold && pct > -cfg.Threshold:
+			case better:
+				verdict = "improvement"
+			default:
+				verdict = "regression"
+				regressions++
+			}
+		}
+
+		// The change is the last column so color codes do not skew alignment
+		if verdict != "" {
+			change += " " + verdict
+			if !cfg.NoColor {
+				color := colorGreen
+				if verdict == "regression" {
+					color = colorRed
+				}
+				change = color + change + colorReset
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.name, m.format(m.base), m.format(m.head), change)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "\n%d significant regressions at a %.1f%% threshold\n", regressions, cfg.Threshold)
+	return err
+}
@@ -0,0 +1,161 @@
+package benchmark
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// KeyFormat is the encoding dump-keys writes keys in
+type KeyFormat string
+
+const (
+	// KeyFormatBinary is [uvarint length][key bytes] repeating, as read by --keys-file
+	KeyFormatBinary KeyFormat = "binary"
+	// KeyFormatHex writes one hex-encoded key per line
+	KeyFormatHex KeyFormat = "hex"
+	// KeyFormatBase64 writes one standard base64-encoded key per line
+	KeyFormatBase64 KeyFormat = "base64"
+)
+
+// validate rejects unknown key formats
+func (f KeyFormat) validate() error {
+	switch f {
+	case KeyFormatBinary, KeyFormatHex, KeyFormatBase64:
+		return nil
+	default:
+		return fmt.Errorf("invalid --format %q: expected binary, hex or base64", f)
+	}
+}
+
+// DumpKeysConfig defines a key dump of an existing database from the dump-keys subcommand
+type DumpKeysConfig struct {
+	DBPath         string
+	DatabaseType   string
+	BlockCacheSize int64
+	LogFormat      string
+	PebbleComparer string
+	Output         string // file to write the keys to, "-" for standard output
+	Format         KeyFormat
+	Limit          int // stop after this many keys, <= 0 to dump every key
+}
+
+// RunDumpKeys writes the keys of an existing database in key order
+func RunDumpKeys(cfg DumpKeysConfig) error {
+	setupLog(Config{LogFormat: cfg.LogFormat})
+	if err := cfg.Format.validate(); err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if cfg.Output == "-" {
+		// Keep the log out of the key stream
+		if cfg.LogFormat == "json" {
+			log.Logger = log.Output(os.Stderr)
+		} else {
+			log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"})
+		}
+	} else {
+		f, err := os.Create(cfg.Output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	db, err := createDatabase(Config{
+		DBPath:         cfg.DBPath,
+		DatabaseType:   cfg.DatabaseType,
+		BlockCacheSize: cfg.BlockCacheSize,
+		PebbleComparer: cfg.PebbleComparer,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+	if !db.Capabilities().SupportsIterator {
+		return fmt.Errorf("dump-keys walks the database with an iterator, which the %s backend does not support", cfg.DatabaseType)
+	}
+
+	start := time.Now()
+	count, err := dumpKeys(db, out, cfg.Format, cfg.Limit)
+	if err != nil {
+		return err
+	}
+	if f, ok := out.(*os.File); ok && f != os.Stdout {
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+
+	log.Info().
+		Str("output", cfg.Output).
+		Str("format", string(cfg.Format)).
+		Uint64("keys", count).
+		Dur("elapsed", time.Since(start)).
+		Msg("Dumped keys")
+	return nil
+}
+
+// dumpKeys writes at most limit keys of db to out, every key when limit <= 0
+func dumpKeys(db Database, out io.Writer, format KeyFormat, limit int) (uint64, error) {
+	it, err := db.NewIterator(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	w := bufio.NewWriterSize(out, readerBufferSize)
+	var count uint64
+	for valid := it.First(); valid; valid = it.Next() {
+		if limit > 0 && count >= uint64(limit) {
+			break
+		}
+		if err = writeKey(w, it.Key(), format); err != nil {
+			break
+		}
+		count++
+	}
+
+	if err == nil {
+		err = it.Error()
+	}
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return count, fmt.Errorf("dumping keys failed: %w", err)
+	}
+	return count, nil
+}
+
+// writeKey encodes one key in format
+func writeKey(w *bufio.Writer, key []byte, format KeyFormat) error {
+	var err error
+	switch format {
+	case KeyFormatHex:
+		_, err = w.WriteString(hex.EncodeToString(key))
+	case KeyFormatBase64:
+		_, err = w.WriteString(base64.StdEncoding.EncodeToString(key))
+	default:
+		if _, err = w.Write(binary.AppendUvarint(nil, uint64(len(key)))); err == nil {
+			_, err = w.Write(key)
+		}
+		return err
+	}
+	if err == nil {
+		err = w.WriteByte('\n')
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package benchmark
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDumpKeysBinaryRoundTrip(t *testing.T) {
+	db, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	keys := [][]byte{{}, {0x00}, []byte("a"), []byte("block/\x00\x01"), bytes.Repeat([]byte{0xff}, 300)}
+	for _, key := range keys {
+		if err := db.Set(key, []byte("v")); err != nil {
+			t.Fatalf("Set(%x): %v", key, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	count, err := dumpKeys(db, &buf, KeyFormatBinary, 0)
+	if err != nil {
+		t.Fatalf("dumpKeys: %v", err)
+	}
+	if count != uint64(len(keys)) {
+		t.Fatalf("dumped %d keys, want %d", count, len(keys))
+	}
+
+	got := slices.Collect(loadKeysFromReader(&buf))
+	slices.SortFunc(keys, bytes.Compare)
+	if !slices.EqualFunc(got, keys, bytes.Equal) {
+		t.Fatalf("loaded keys %x, want %x", got, keys)
+	}
+}
+
+func TestDumpKeysTextRoundTrip(t *testing.T) {
+	keys := [][]byte{{}, {0x00}, []byte("a"), []byte("block/\x00\x01"), bytes.Repeat([]byte{0xff}, 300)}
+	for _, format := range []KeyFormat{KeyFormatHex, KeyFormatBase64} {
+		t.Run(string(format), func(t *testing.T) {
+			db, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			for _, key := range keys {
+				if err := db.Set(key, []byte("v")); err != nil {
+					t.Fatalf("Set(%x): %v", key, err)
+				}
+			}
+
+			var buf bytes.Buffer
+			if _, err := dumpKeys(db, &buf, format, 0); err != nil {
+				t.Fatalf("dumpKeys: %v", err)
+			}
+
+			got := slices.Collect(loadTextKeys(&buf, format))
+			want := slices.Clone(keys)
+			slices.SortFunc(want, bytes.Compare)
+			if !slices.EqualFunc(got, want, bytes.Equal) {
+				t.Fatalf("loaded keys %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadTextKeysReadsCSVAndSkipsMalformedLines(t *testing.T) {
+	quietLogs(t)
+
+	csv := "key,value\n0xdead,1\n\"beef\",2\nnot-hex,3\n00ff\n"
+	got := slices.Collect(loadTextKeys(strings.NewReader(csv), KeyFormatHex))
+	want := [][]byte{{0xde, 0xad}, {0xbe, 0xef}, {0x00, 0xff}}
+	if !slices.EqualFunc(got, want, bytes.Equal) {
+		t.Fatalf("loaded keys %x, want %x", got, want)
+	}
+}
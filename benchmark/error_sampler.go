@@ -0,0 +1,60 @@
+package benchmark
+
+import (
+	"sync"
+)
+
+// maxErrorSamples bounds the distinct error messages kept per phase
+const maxErrorSamples = 10
+
+// ErrorSample is one distinct error message and how often it occurred
+type ErrorSample struct {
+	Message string
+	Count   uint64
+}
+
+// errorSampler collects the first maxErrorSamples distinct error messages seen
+// by concurrent workers, counting repeats of each
+type errorSampler struct {
+	mu       sync.Mutex
+	first    error
+	counts   map[string]uint64
+	messages []string // distinct messages in first-seen order
+	other    uint64   // errors whose message did not fit in the sample
+}
+
+func (s *errorSampler) record(err error) {
+	msg := err.Error()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.first == nil {
+		s.first = err
+	}
+	if _, ok := s.counts[msg]; ok {
+		s.counts[msg]++
+		return
+	}
+	if len(s.messages) >= maxErrorSamples {
+		s.other++
+		return
+	}
+	if s.counts == nil {
+		s.counts = make(map[string]uint64)
+	}
+	s.counts[msg] = 1
+	s.messages = append(s.messages, msg)
+}
+
+// samples returns the distinct errors in the order they were first seen
+func (s *errorSampler) samples() []ErrorSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := make([]ErrorSample, len(s.messages))
+	for i, msg := range s.messages {
+		samples[i] = ErrorSample{Message: msg, Count: s.counts[msg]}
+	}
+	return samples
+}
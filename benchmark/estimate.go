@@ -0,0 +1,105 @@
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// estimateCalibrationKeys is how many keys --estimate writes and reads back
+// to calibrate its extrapolation
+const estimateCalibrationKeys = 5000
+
+// runEstimate writes and reads back a few thousand of the workload's keys in
+// a scratch database next to cfg.DBPath and extrapolates the duration of the
+// write and read phases and the disk usage of the full cfg.KeyCount. A small
+// database fits in memtables and cache, so the duration is a lower bound for
+// runs that outgrow them. newWorkload must return a fresh workload so the
+// calibration does not disturb the state of the one used for the run.
+func runEstimate(cfg Config, newWorkload func() (Workload, error)) error {
+	parent := filepath.Dir(cfg.DBPath)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(parent, "estimate-")
+	if err != nil {
+		return fmt.Errorf("failed to create calibration database directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	calibration := cfg
+	calibration.DBPath = dir
+	calibration.KeyCount = minInt(cfg.KeyCount, estimateCalibrationKeys)
+	calibration.SpaceAmpInterval = 0
+	calibration.FillCurve = ""
+	calibration.L0FilesTarget = 0
+	calibration.MaxBytesWritten = 0
+	calibration.MaxBytesRead = 0
+	log.Info().Int("keys", calibration.KeyCount).Str("path", dir).Msg("Calibrating run estimate")
+
+	workload, err := newWorkload()
+	if err != nil {
+		return err
+	}
+	db, err := createDatabase(calibration)
+	if err != nil {
+		return fmt.Errorf("failed to create calibration database: %w", err)
+	}
+	defer db.Close()
+
+	keys := workload.GenerateKeys(calibration.Seed, calibration.KeyCount)
+	write, err := runWritePhase(db, calibration, keys, workload, nil)
+	if err != nil {
+		return err
+	}
+	dataSize := db.GetMetrics().DataSize
+	read, err := runReadPhase(db, calibration, keys, workload, nil)
+	if err != nil {
+		return err
+	}
+	if write.Successful == 0 || write.OpsPerSec() <= 0 || read.OpsPerSec() <= 0 {
+		return fmt.Errorf("--estimate calibration completed no operations")
+	}
+
+	// Pebble reports the bytes of its live tables after the write phase's
+	// flush. MDBX grows its data file in steps far larger than a few thousand
+	// keys and the other backends keep no files, so they are estimated from
+	// the key and value bytes written.
+	bytesPerKey := float64(dataSize) / float64(write.Successful)
+	if dbType := DatabaseType(cfg.DatabaseType); (dbType != "" && dbType != DatabaseTypePebble) || dataSize == 0 {
+		var keyBytes uint64
+		for key := range keys {
+			keyBytes += uint64(len(key))
+		}
+		bytesPerKey = float64(keyBytes+write.ValueBytes) / float64(write.Successful)
+		log.Info().Msg("Estimating disk usage from the key and value bytes written")
+	}
+	writeTime := time.Duration(float64(cfg.KeyCount) / write.OpsPerSec() * float64(time.Second))
+	readTime := time.Duration(float64(cfg.KeyCount) / read.OpsPerSec() * float64(time.Second))
+	diskBytes := int64(bytesPerKey * float64(cfg.KeyCount))
+
+	log.Info().
+		Int("key_count", cfg.KeyCount).
+		Int("calibration_keys", calibration.KeyCount).
+		Float64("write_ops_per_sec", write.OpsPerSec()).
+		Float64("read_ops_per_sec", read.OpsPerSec()).
+		Float64("bytes_per_key", bytesPerKey).
+		Str("write_time", writeTime.Round(time.Second).String()).
+		Str("read_time", readTime.Round(time.Second).String()).
+		Str("total_time", (writeTime+readTime).Round(time.Second).String()).
+		Str("disk_size", formatBytes(diskBytes)).
+		Msg("Estimated write and read phases")
+
+	if free, err := freeDiskBytes(cfg.DBPath); err != nil {
+		log.Warn().Err(err).Msg("Failed to measure free disk space")
+	} else if uint64(diskBytes) > free {
+		log.Warn().
+			Str("disk_size", formatBytes(diskBytes)).
+			Str("free", formatBytes(int64(free))).
+			Msg("Estimated disk usage exceeds the free disk space")
+	}
+	return nil
+}
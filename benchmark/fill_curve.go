@@ -0,0 +1,151 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultFillCurveBucket is the logical size of a fill curve bucket when
+// --fill-curve-bucket is not set
+const defaultFillCurveBucket = 1 << 30
+
+// fillCurveHeader names the columns of the fill curve CSV
+var fillCurveHeader = []string{"size_bucket", "write_ops_per_sec", "writes", "elapsed_ms"}
+
+// fillCurveRow is the write throughput of one bucket
+type fillCurveRow struct {
+	size    uint64 // logical bytes written when the bucket closed
+	writes  uint64
+	elapsed time.Duration
+}
+
+// fillCurve records write throughput per bucket of logical key and value
+// bytes written, so the degradation of writes as the database grows shows up
+// within one run. A nil *fillCurve records nothing.
+type fillCurve struct {
+	path   string
+	bucket uint64
+	next   atomic.Uint64 // logical size closing the current bucket
+
+	mu         sync.Mutex
+	lastWrites uint64
+	lastTime   time.Time
+	rows       []fillCurveRow
+}
+
+// newFillCurve starts a fill curve written to path, returning nil when path is
+// empty. bucket is the logical size of each bucket in bytes.
+func newFillCurve(path string, bucket int64) *fillCurve {
+	if path == "" {
+		return nil
+	}
+	if bucket <= 0 {
+		bucket = defaultFillCurveBucket
+	}
+	c := &fillCurve{
+		path:     path,
+		bucket:   uint64(bucket),
+		lastTime: time.Now(),
+	}
+	c.next.Store(c.bucket)
+	return c
+}
+
+// observe closes the current bucket once logical bytes have been written,
+// writes being the successful writes so far
+func (c *fillCurve) observe(logical, writes uint64) {
+	if c == nil || logical < c.next.Load() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	next := c.next.Load()
+	if logical < next {
+		return
+	}
+	c.close(next, writes)
+	// A write larger than a bucket skips the buckets it spans
+	c.next.Store((logical/c.bucket + 1) * c.bucket)
+}
+
+// close records the bucket ending at size, holding mu
+func (c *fillCurve) close(size, writes uint64) {
+	now := time.Now()
+	row := fillCurveRow{
+		size:    size,
+		writes:  writes - c.lastWrites,
+		elapsed: now.Sub(c.lastTime),
+	}
+	c.rows = append(c.rows, row)
+	c.lastWrites, c.lastTime = writes, now
+
+	log.Info().
+		Uint64("size_bucket", row.size).
+		Float64("write_ops_per_sec", row.opsPerSec()).
+		Uint64("writes", row.writes).
+		Msg("Fill curve bucket")
+}
+
+// opsPerSec returns the write throughput of the bucket
+func (r fillCurveRow) opsPerSec() float64 {
+	if r.elapsed <= 0 {
+		return 0
+	}
+	return float64(r.writes) / r.elapsed.Seconds()
+}
+
+// finish records the partly filled last bucket and writes the CSV. logical
+// and writes are the phase totals.
+func (c *fillCurve) finish(logical, writes uint64) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if writes > c.lastWrites {
+		c.close(logical, writes)
+	}
+
+	f, err := os.Create(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to create fill curve CSV: %w", err)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.Write(fillCurveHeader); err != nil {
+		return fmt.Errorf("failed to write fill curve CSV: %w", err)
+	}
+	for _, row := range c.rows {
+		record := []string{
+			strconv.FormatUint(row.size, 10),
+			strconv.FormatFloat(row.opsPerSec(), 'f', 2, 64),
+			strconv.FormatUint(row.writes, 10),
+			strconv.FormatFloat(durationMs(row.elapsed), 'f', 3, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("failed to write fill curve CSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write fill curve CSV: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write fill curve CSV: %w", err)
+	}
+
+	log.Info().
+		Str("path", c.path).
+		Int("buckets", len(c.rows)).
+		Uint64("bucket_bytes", c.bucket).
+		Msg("Wrote fill curve CSV")
+	return nil
+}
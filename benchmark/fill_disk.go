@@ -0,0 +1,115 @@
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+)
+
+// fillDiskSampleKeys is how many keys and values the dry run generates to
+// estimate the mean pair size
+const fillDiskSampleKeys = 10000
+
+// fillDiskEstimate records how --fill-disk sized the run
+type fillDiskEstimate struct {
+	freeBytes   uint64  // free space at the database path before writing
+	bytesPerKey float64 // mean key plus value size from the dry run
+	targetBytes uint64  // freeBytes * cfg.FillDisk
+	keyCount    int
+}
+
+// estimateFillDisk sizes the key count so the written pairs take cfg.FillDisk
+// of the free space at cfg.DBPath. newWorkload must return a fresh workload so
+// the dry run does not disturb the state of the one used for the run.
+func estimateFillDisk(cfg Config, newWorkload func() (Workload, error)) (*fillDiskEstimate, error) {
+	free, err := freeDiskBytes(cfg.DBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	workload, err := newWorkload()
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	shape := newValueShape(cfg)
+	var total, samples int
+	for key := range workload.GenerateKeys(cfg.Seed, fillDiskSampleKeys) {
+		total += len(key) + len(generateWorkloadValue(rng, workload, key, shape))
+		samples++
+	}
+	if samples == 0 || total == 0 {
+		return nil, fmt.Errorf("--fill-disk dry run generated no data")
+	}
+
+	est := &fillDiskEstimate{
+		freeBytes:   free,
+		bytesPerKey: float64(total) / float64(samples),
+		targetBytes: uint64(float64(free) * cfg.FillDisk),
+	}
+	est.keyCount = int(float64(est.targetBytes) / est.bytesPerKey)
+	if est.keyCount <= 0 {
+		return nil, fmt.Errorf("--fill-disk %.2f of %s free leaves no room for a %.0f byte pair",
+			cfg.FillDisk, formatBytes(int64(free)), est.bytesPerKey)
+	}
+
+	log.Info().
+		Str("free", formatBytes(int64(est.freeBytes))).
+		Float64("fraction", cfg.FillDisk).
+		Str("target", formatBytes(int64(est.targetBytes))).
+		Float64("bytes_per_key", est.bytesPerKey).
+		Int("key_count", est.keyCount).
+		Msg("Sized key count to fill disk")
+	return est, nil
+}
+
+// report compares the estimate with what the write phase left on disk
+func (est *fillDiskEstimate) report(dbPath string) {
+	size, err := directorySize(dbPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to measure database disk size")
+		return
+	}
+	free, err := freeDiskBytes(dbPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to measure free disk space")
+		return
+	}
+
+	log.Info().
+		Str("estimated", formatBytes(int64(est.targetBytes))).
+		Str("actual_on_disk", formatBytes(size)).
+		Float64("actual_to_estimate", float64(size)/float64(est.targetBytes)).
+		Str("free_before", formatBytes(int64(est.freeBytes))).
+		Str("free_after", formatBytes(int64(free))).
+		Msg("Fill disk result")
+}
+
+// freeDiskBytes returns the space available to unprivileged users on the file
+// system holding path, using the closest existing parent when path does not exist
+func freeDiskBytes(path string) (uint64, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, fmt.Errorf("failed to stat file system at %s: %w", dir, err)
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
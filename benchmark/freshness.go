@@ -0,0 +1,164 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// freshnessPrefix namespaces the freshness keyspace away from workload keys
+	freshnessPrefix = "fresh/"
+
+	// freshnessProbeStride probes one in this many written keys
+	freshnessProbeStride = 100
+)
+
+// freshnessKey returns the key for index i in the freshness keyspace
+func freshnessKey(i int) []byte {
+	key := make([]byte, len(freshnessPrefix)+8)
+	copy(key, freshnessPrefix)
+	binary.BigEndian.PutUint64(key[len(freshnessPrefix):], uint64(i))
+	return key
+}
+
+// parseFreshnessLags parses a comma-separated list of intervening write counts
+func parseFreshnessLags(spec string) ([]int, error) {
+	var lags []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lag, err := strconv.Atoi(part)
+		if err != nil || lag < 0 {
+			return nil, fmt.Errorf("invalid freshness lag %q", part)
+		}
+		lags = append(lags, lag)
+	}
+	if len(lags) == 0 {
+		return nil, fmt.Errorf("no freshness lags in %q", spec)
+	}
+	return lags, nil
+}
+
+// runFreshnessPhase writes a dedicated keyspace sequentially and reads probe
+// keys back after each configured number of intervening writes, and once more
+// after cfg.FreshnessDelay, showing how read latency evolves as a key ages
+// from the memtable down the LSM
+func runFreshnessPhase(db Database, cfg Config) error {
+	lags, err := parseFreshnessLags(cfg.FreshnessLags)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("keys", cfg.FreshnessKeys).
+		Ints("lags", lags).
+		Dur("delay", cfg.FreshnessDelay).
+		Msg("Beginning read-after-write freshness phase")
+
+	collectors := make([]*latencyCollector, len(lags))
+	channels := make([]chan time.Duration, len(lags))
+	for i, lag := range lags {
+		channels[i] = make(chan time.Duration, queueDepth(cfg))
+		collectors[i] = startLatencyCollector(fmt.Sprintf("fresh_lag_%d", lag), channels[i])
+	}
+
+	var misses uint64
+	probe := func(key []byte, latencies chan<- time.Duration) error {
+		readStart := time.Now()
+		_, closer, err := db.Get(key)
+		latencies <- time.Since(readStart)
+		if err != nil {
+			if IsKeyNotFound(err) {
+				misses++
+				return nil
+			}
+			return err
+		}
+		if closer != nil {
+			closer.Close()
+		}
+		return nil
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	var phaseErr error
+	for i := 0; i < cfg.FreshnessKeys && phaseErr == nil; i++ {
+		if err := db.Set(freshnessKey(i), generateValue(rng, cfg.ValueSize)); err != nil {
+			phaseErr = fmt.Errorf("freshness write failed: %w", err)
+			break
+		}
+
+		// Read back the probe keys that have now seen exactly lag later writes
+		for l, lag := range lags {
+			j := i - lag
+			if j < 0 || j%freshnessProbeStride != 0 {
+				continue
+			}
+			if err := probe(freshnessKey(j), channels[l]); err != nil {
+				phaseErr = fmt.Errorf("freshness read failed: %w", err)
+				break
+			}
+		}
+	}
+
+	for l := range lags {
+		close(channels[l])
+		collectors[l].wait()
+	}
+	if phaseErr != nil {
+		return phaseErr
+	}
+
+	for l, lag := range lags {
+		logFreshness(fmt.Sprintf("%d_writes", lag), collectors[l])
+	}
+
+	if cfg.FreshnessDelay > 0 {
+		time.Sleep(cfg.FreshnessDelay)
+
+		latencies := make(chan time.Duration, queueDepth(cfg))
+		collector := startLatencyCollector("fresh_delayed", latencies)
+		for j := 0; j < cfg.FreshnessKeys; j += freshnessProbeStride {
+			if err := probe(freshnessKey(j), latencies); err != nil {
+				phaseErr = fmt.Errorf("freshness read failed: %w", err)
+				break
+			}
+		}
+		close(latencies)
+		collector.wait()
+		if phaseErr != nil {
+			return phaseErr
+		}
+		logFreshness(cfg.FreshnessDelay.String(), collector)
+	}
+
+	if misses > 0 {
+		log.Warn().Uint64("misses", misses).Msg("Freshness probes did not find keys that were written")
+	}
+	return nil
+}
+
+// logFreshness reports read latency for keys of one age
+func logFreshness(age string, c *latencyCollector) {
+	reads := c.hist.TotalCount()
+	avg := float64(0)
+	if reads > 0 {
+		avg = float64(c.total.Microseconds()) / 1000.0 / float64(reads)
+	}
+
+	log.Info().
+		Str("age", age).
+		Int64("reads", reads).
+		Float64("avg_latency_ms", avg).
+		Float64("p50_latency_ms", c.percentileMs(50)).
+		Float64("p99_latency_ms", c.percentileMs(99)).
+		Msg("Read-after-write latency")
+}
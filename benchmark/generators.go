@@ -0,0 +1,63 @@
+package benchmark
+
+import (
+	"iter"
+	"sync"
+)
+
+// generatorSeedStride separates the key seeds of parallel generators far
+// enough that they never meet the small seed offsets the other phases use
+const generatorSeedStride = 1 << 32
+
+// SeedIndependentKeys is implemented by workloads whose key sequence does not
+// depend on the GenerateKeys seed. Parallel generators would each write the
+// first keys of that sequence, so --generator-workers rejects them.
+type SeedIndependentKeys interface {
+	KeysIgnoreSeed() bool
+}
+
+// parallelKeys splits count keys across one generator goroutine per workload,
+// each generating its share from its own seed, and yields them as they arrive.
+// Every workload must be a separate instance, since key generation mutates
+// workload state. The set of keys is deterministic but their order is not.
+func parallelKeys(workloads []Workload, seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		out := make(chan []byte, 1024)
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+
+		for i, w := range workloads {
+			share := count / len(workloads)
+			if i < count%len(workloads) {
+				share++
+			}
+			wg.Add(1)
+			go func(w Workload, seed int64, share int) {
+				defer wg.Done()
+				for key := range w.GenerateKeys(seed, share) {
+					select {
+					case out <- key:
+					case <-done:
+						return
+					}
+				}
+			}(w, seed+int64(i)*generatorSeedStride, share)
+		}
+		go func() {
+			wg.Wait()
+			close(out)
+		}()
+
+		// Stop the generators when the consumer breaks out early
+		defer func() {
+			close(done)
+			for range out {
+			}
+		}()
+		for key := range out {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package benchmark
+
+import "testing"
+
+func TestParallelGeneratorSeedsWriteDistinctKeys(t *testing.T) {
+	quietLogs(t)
+
+	types := append([]WorkloadType{WorkloadReorg, WorkloadAccountExistence}, blendableWorkloads...)
+	for _, workloadType := range types {
+		cfg := blendTestConfig("")
+		cfg.Type = workloadType
+		if fixed, ok := CreateWorkload(cfg).(SeedIndependentKeys); ok && fixed.KeysIgnoreSeed() {
+			continue
+		}
+
+		// Two generators of a --generator-workers run
+		first := make(map[string]bool)
+		for key := range CreateWorkload(cfg).GenerateKeys(42, 200) {
+			first[string(key)] = true
+		}
+		shared := 0
+		for key := range CreateWorkload(cfg).GenerateKeys(42+generatorSeedStride, 200) {
+			if first[string(key)] {
+				shared++
+			}
+		}
+		// Hot accounts are shared between generators, the rest must differ
+		if shared > 50 {
+			t.Errorf("%s: %d of 200 keys repeat across generator seeds, want it to report KeysIgnoreSeed", workloadType, shared)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+const (
+	// Latencies are recorded in nanoseconds between 1ns and one minute;
+	// slower operations are clamped to the maximum
+	histogramMinLatency = 1
+	histogramMaxLatency = int64(time.Minute)
+	histogramSigFigs    = 3
+)
+
+// latencyCollector drains per-operation latencies into an HdrHistogram
+type latencyCollector struct {
+	hist  *hdrhistogram.Histogram
+	total time.Duration
+	done  chan struct{}
+}
+
+// startLatencyCollector records every latency sent on latencies into a histogram
+// tagged with tag. Call wait after closing latencies before reading the results.
+func startLatencyCollector(tag string, latencies <-chan time.Duration) *latencyCollector {
+	c := &latencyCollector{
+		hist: hdrhistogram.New(histogramMinLatency, histogramMaxLatency, histogramSigFigs),
+		done: make(chan struct{}),
+	}
+	c.hist.SetTag(tag)
+	c.hist.SetStartTimeMs(time.Now().UnixMilli())
+
+	go func() {
+		defer close(c.done)
+		for latency := range latencies {
+			c.total += latency
+			value := int64(latency)
+			if value > histogramMaxLatency {
+				value = histogramMaxLatency
+			}
+			c.hist.RecordValue(value)
+		}
+		c.hist.SetEndTimeMs(time.Now().UnixMilli())
+	}()
+
+	return c
+}
+
+// wait blocks until the latencies channel is closed and fully drained
+func (c *latencyCollector) wait() {
+	<-c.done
+}
+
+// percentile returns the latency at the given percentile (0-100)
+func (c *latencyCollector) percentile(percentile float64) time.Duration {
+	return time.Duration(c.hist.ValueAtQuantile(percentile))
+}
+
+// workerHistogram merges histograms that workers record into without locking
+type workerHistogram struct {
+	mu   sync.Mutex
+	hist *hdrhistogram.Histogram
+}
+
+// newGenerationHistogram tracks per-call generation latencies with the same
+// bounds as the operation latency histograms
+func newGenerationHistogram() *workerHistogram {
+	return &workerHistogram{hist: hdrhistogram.New(histogramMinLatency, histogramMaxLatency, histogramSigFigs)}
+}
+
+// worker returns a histogram with the same bounds for one worker to record into
+func (h *workerHistogram) worker() *hdrhistogram.Histogram {
+	return hdrhistogram.New(h.hist.LowestTrackableValue(), h.hist.HighestTrackableValue(), int(h.hist.SignificantFigures()))
+}
+
+// merge folds a worker's histogram into the total
+func (h *workerHistogram) merge(worker *hdrhistogram.Histogram) {
+	h.mu.Lock()
+	h.hist.Merge(worker)
+	h.mu.Unlock()
+}
+
+// percentileMs returns the latency at the given percentile (0-100) in milliseconds
+func (c *latencyCollector) percentileMs(percentile float64) float64 {
+	return float64(c.percentile(percentile)) / float64(time.Millisecond)
+}
+
+// writeHDRLog writes the histograms to path in HdrHistogram log format (.hlog),
+// one tagged interval line per histogram
+func writeHDRLog(path string, histograms []*hdrhistogram.Histogram) error {
+	if len(histograms) == 0 {
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create HDR log: %w", err)
+	}
+	defer f.Close()
+
+	baseTime := histograms[0].StartTimeMs()
+	w := hdrhistogram.NewHistogramLogWriter(f)
+	w.SetBaseTime(baseTime)
+
+	if err := w.OutputLogFormatVersion(); err != nil {
+		return fmt.Errorf("failed to write HDR log header: %w", err)
+	}
+	if err := w.OutputComment("[Latency values in nanoseconds]"); err != nil {
+		return fmt.Errorf("failed to write HDR log header: %w", err)
+	}
+	if err := w.OutputStartTime(baseTime); err != nil {
+		return fmt.Errorf("failed to write HDR log header: %w", err)
+	}
+	if err := w.OutputBaseTime(baseTime); err != nil {
+		return fmt.Errorf("failed to write HDR log header: %w", err)
+	}
+	if err := w.OutputLegend(); err != nil {
+		return fmt.Errorf("failed to write HDR log header: %w", err)
+	}
+
+	for _, h := range histograms {
+		if err := w.OutputIntervalHistogram(h); err != nil {
+			return fmt.Errorf("failed to write %s histogram: %w", h.Tag(), err)
+		}
+	}
+
+	return f.Close()
+}
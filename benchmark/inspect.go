@@ -0,0 +1,143 @@
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// InspectConfig defines a walk over an existing database from the inspect subcommand
+type InspectConfig struct {
+	DBPath         string
+	DatabaseType   string
+	BlockCacheSize int64
+	LogFormat      string
+	PebbleComparer string
+	Limit          int // stop after this many keys, <= 0 to walk the whole database
+}
+
+// prefixStats aggregates the keys sharing one first byte
+type prefixStats struct {
+	keys       uint64
+	keyBytes   uint64
+	valueBytes uint64
+}
+
+// InspectResult summarizes the contents of a database
+type InspectResult struct {
+	Keys       uint64
+	KeyBytes   uint64
+	ValueBytes uint64
+	Truncated  bool // the walk stopped at the configured limit
+	Prefixes   [256]prefixStats
+}
+
+// RunInspect opens an existing database read-only and reports its key count,
+// sizes and the distribution of keys by first-byte prefix
+func RunInspect(cfg InspectConfig) error {
+	setupLog(Config{LogFormat: cfg.LogFormat})
+
+	db, err := createDatabase(Config{
+		DBPath:         cfg.DBPath,
+		DatabaseType:   cfg.DatabaseType,
+		BlockCacheSize: cfg.BlockCacheSize,
+		PebbleComparer: cfg.PebbleComparer,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+	if !db.Capabilities().SupportsIterator {
+		return fmt.Errorf("inspect walks the database with an iterator, which the %s backend does not support", cfg.DatabaseType)
+	}
+
+	log.Info().Str("path", cfg.DBPath).Int("limit", cfg.Limit).Msg("Inspecting database")
+
+	start := time.Now()
+	result, err := inspectDatabase(db, cfg.Limit)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Uint64("keys", result.Keys).
+		Uint64("key_bytes", result.KeyBytes).
+		Uint64("value_bytes", result.ValueBytes).
+		Bool("truncated", result.Truncated).
+		Dur("elapsed", time.Since(start)).
+		Msg("Inspection complete")
+
+	return printPrefixHistogram(os.Stdout, result)
+}
+
+// inspectDatabase iterates db from the first key, visiting at most limit keys
+// when limit is positive
+func inspectDatabase(db Database, limit int) (*InspectResult, error) {
+	it, err := db.NewIterator(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InspectResult{}
+	for valid := it.First(); valid; valid = it.Next() {
+		if limit > 0 && result.Keys >= uint64(limit) {
+			result.Truncated = true
+			break
+		}
+
+		key, value := it.Key(), it.Value()
+		result.Keys++
+		result.KeyBytes += uint64(len(key))
+		result.ValueBytes += uint64(len(value))
+
+		if len(key) > 0 {
+			p := &result.Prefixes[key[0]]
+			p.keys++
+			p.keyBytes += uint64(len(key))
+			p.valueBytes += uint64(len(value))
+		}
+	}
+
+	err = it.Error()
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("iteration failed: %w", err)
+	}
+	return result, nil
+}
+
+// printPrefixHistogram renders one row per first-byte prefix present in the database
+func printPrefixHistogram(out io.Writer, r *InspectResult) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "PREFIX\tKEYS\tSHARE\tAVG KEY\tAVG VALUE\tVALUE BYTES")
+	for b, p := range r.Prefixes {
+		if p.keys == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.2f%%\t%.1f\t%.1f\t%s\n",
+			formatPrefix(byte(b)),
+			p.keys,
+			float64(p.keys)/float64(r.Keys)*100,
+			float64(p.keyBytes)/float64(p.keys),
+			float64(p.valueBytes)/float64(p.keys),
+			formatBytes(int64(p.valueBytes)),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// formatPrefix shows printable prefixes as characters and the rest as hex
+func formatPrefix(b byte) string {
+	if b >= 0x21 && b <= 0x7E {
+		return fmt.Sprintf("%q (0x%02x)", b, b)
+	}
+	return fmt.Sprintf("0x%02x", b)
+}
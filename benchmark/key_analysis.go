@@ -0,0 +1,170 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+)
+
+// keyAnalysisBuckets is how many equal slices --analyze-keys divides the span
+// between the smallest and largest key into to measure coverage
+const keyAnalysisBuckets = 1024
+
+// keyAnalysisPrefixLens are the prefix lengths, in bytes, whose distinct
+// values --analyze-keys counts
+var keyAnalysisPrefixLens = []int{1, 2, 4, 8}
+
+// Thresholds --analyze-keys classifies a key sequence by
+const (
+	// sequentialAscendingRatio is the share of keys that must sort after their
+	// predecessor for the sequence to count as sequential
+	sequentialAscendingRatio = 0.9
+
+	// clusteredCoverage is the coverage below which keys are clustered in a
+	// few regions of the key space rather than scattered across it
+	clusteredCoverage = 0.1
+)
+
+// keyLocality describes the locality of a key sequence
+type keyLocality struct {
+	keys     int
+	distinct int
+
+	sharedPrefixMean       float64 // bytes shared with the previous key, in generation order
+	sortedSharedPrefixMean float64 // bytes shared with the previous distinct key, in key order
+	ascendingRatio         float64 // share of keys sorting after the previous key
+
+	distinctPrefixes []int // distinct prefixes of each keyAnalysisPrefixLens length
+
+	commonPrefix int     // bytes every key starts with
+	coverage     float64 // share of the keyAnalysisBuckets between the smallest and largest key holding a key
+}
+
+// sharedPrefixLen returns the length of the common prefix of a and b
+func sharedPrefixLen(a, b []byte) int {
+	n := minInt(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// analyzeKeyLocality measures the locality of keys in the order they are
+// generated and in the sorted key space. It holds every key in memory.
+func analyzeKeyLocality(keys iter.Seq[[]byte]) keyLocality {
+	var loc keyLocality
+	var all [][]byte
+	var prev []byte
+	var shared, ascending int
+	for key := range keys {
+		if loc.keys > 0 {
+			shared += sharedPrefixLen(prev, key)
+			if bytes.Compare(key, prev) > 0 {
+				ascending++
+			}
+		}
+		all = append(all, key)
+		prev = key
+		loc.keys++
+	}
+	if loc.keys == 0 {
+		return loc
+	}
+	if loc.keys > 1 {
+		loc.sharedPrefixMean = float64(shared) / float64(loc.keys-1)
+		loc.ascendingRatio = float64(ascending) / float64(loc.keys-1)
+	}
+
+	slices.SortFunc(all, bytes.Compare)
+	all = slices.CompactFunc(all, bytes.Equal)
+	loc.distinct = len(all)
+
+	loc.distinctPrefixes = make([]int, len(keyAnalysisPrefixLens))
+	for i, n := range keyAnalysisPrefixLens {
+		// Sorted keys sharing a prefix are adjacent
+		var last []byte
+		for j, key := range all {
+			prefix := key[:minInt(n, len(key))]
+			if j == 0 || !bytes.Equal(prefix, last) {
+				loc.distinctPrefixes[i]++
+			}
+			last = prefix
+		}
+	}
+
+	if loc.distinct == 1 {
+		loc.commonPrefix = len(all[0])
+		loc.coverage = 1
+		return loc
+	}
+	shared = 0
+	for i := 1; i < len(all); i++ {
+		shared += sharedPrefixLen(all[i-1], all[i])
+	}
+	loc.sortedSharedPrefixMean = float64(shared) / float64(len(all)-1)
+
+	// The smallest and largest key first differ right after the common prefix,
+	// so the next 8 bytes place every key within their span
+	first, last := all[0], all[len(all)-1]
+	loc.commonPrefix = sharedPrefixLen(first, last)
+	position := func(key []byte) uint64 {
+		var word [8]byte
+		copy(word[:], key[loc.commonPrefix:])
+		return binary.BigEndian.Uint64(word[:])
+	}
+	lo, hi := position(first), position(last)
+	touched := make([]bool, keyAnalysisBuckets)
+	covered := 0
+	for _, key := range all {
+		bucket := int(float64(position(key)-lo) / float64(hi-lo) * keyAnalysisBuckets)
+		bucket = minInt(bucket, keyAnalysisBuckets-1)
+		if !touched[bucket] {
+			touched[bucket] = true
+			covered++
+		}
+	}
+	loc.coverage = float64(covered) / keyAnalysisBuckets
+	return loc
+}
+
+// pattern names the access pattern the locality suggests
+func (loc keyLocality) pattern() string {
+	switch {
+	case loc.ascendingRatio >= sequentialAscendingRatio:
+		return "sequential"
+	case loc.coverage < clusteredCoverage:
+		return "clustered"
+	default:
+		return "scattered"
+	}
+}
+
+// analyzeKeys generates cfg.KeyCount keys from workload without a database
+// and reports their locality
+func analyzeKeys(cfg Config, workload Workload) error {
+	log.Info().Int("keys", cfg.KeyCount).Msg("Analyzing generated keys")
+	loc := analyzeKeyLocality(workload.GenerateKeys(cfg.Seed, cfg.KeyCount))
+	if loc.keys == 0 {
+		return fmt.Errorf("--analyze-keys: the workload generated no keys")
+	}
+
+	event := log.Info().
+		Int("keys", loc.keys).
+		Int("distinct_keys", loc.distinct).
+		Float64("shared_prefix_mean", loc.sharedPrefixMean).
+		Float64("sorted_shared_prefix_mean", loc.sortedSharedPrefixMean).
+		Float64("ascending_ratio", loc.ascendingRatio).
+		Int("common_prefix_bytes", loc.commonPrefix).
+		Float64("keyspace_coverage", loc.coverage)
+	for i, n := range keyAnalysisPrefixLens {
+		event = event.Int(fmt.Sprintf("distinct_prefixes_%db", n), loc.distinctPrefixes[i])
+	}
+	event.Str("pattern", loc.pattern()).Msg("Key locality")
+	return nil
+}
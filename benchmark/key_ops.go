@@ -0,0 +1,222 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// keyOpsMagic starts a keys file written by --capture-keys. Each record after
+// it is [op byte][uvarint key length][key bytes][uvarint value length]. Plain
+// keys files start with a key length instead, so both load as --keys-file.
+var keyOpsMagic = []byte("\x00pebble-bench-key-ops\x00")
+
+// Operation bytes of a captured keys file
+const (
+	keyOpWrite byte = 'w'
+	keyOpRead  byte = 'r' // value length is the length read, 0 when not found
+)
+
+// keyOp is one captured operation, opSet or opGet
+type keyOp struct {
+	op       string
+	key      []byte
+	valueLen int
+}
+
+// readKeyOps reads the records that follow keyOpsMagic
+func readKeyOps(buf *bufio.Reader) iter.Seq[keyOp] {
+	return func(yield func(keyOp) bool) {
+		for {
+			op, err := buf.ReadByte()
+			if err != nil {
+				if err == io.EOF {
+					return
+				}
+				panic(fmt.Errorf("failed to read operation from reader: %w", err))
+			}
+			name := opSet
+			switch op {
+			case keyOpWrite:
+			case keyOpRead:
+				name = opGet
+			default:
+				panic(fmt.Errorf("unknown captured operation %q", op))
+			}
+
+			n, err := binary.ReadUvarint(buf)
+			if err != nil {
+				panic(fmt.Errorf("failed to read key length from reader: %w", err))
+			}
+			key := make([]byte, n)
+			if _, err := io.ReadFull(buf, key); err != nil {
+				panic(fmt.Errorf("failed to read key bytes from reader: %w", err))
+			}
+			valueLen, err := binary.ReadUvarint(buf)
+			if err != nil {
+				panic(fmt.Errorf("failed to read value length from reader: %w", err))
+			}
+
+			if !yield(keyOp{op: name, key: key, valueLen: int(valueLen)}) {
+				return
+			}
+		}
+	}
+}
+
+// hasKeyOpsMagic consumes keyOpsMagic from buf if the stream starts with it
+func hasKeyOpsMagic(buf *bufio.Reader) bool {
+	head, _ := buf.Peek(len(keyOpsMagic))
+	if !bytes.Equal(head, keyOpsMagic) {
+		return false
+	}
+	buf.Discard(len(keyOpsMagic))
+	return true
+}
+
+// isKeyOpsFile reports whether path holds captured operations rather than plain keys
+func isKeyOpsFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open keys file: %w", err)
+	}
+	defer f.Close()
+	return hasKeyOpsMagic(bufio.NewReaderSize(f, len(keyOpsMagic))), nil
+}
+
+// NewKeyCaptureDatabase wraps db, capturing every key it writes or reads,
+// with the value length, to a new keys file at path. Running with that file
+// as --keys-file replays the same writes and reads in the same order, so any
+// backend can be driven with exactly the operations of the captured run.
+func NewKeyCaptureDatabase(db Database, path string) (*RecordingDatabase, error) {
+	return newRecordingDatabase(db, path, "key capture", keyOpsMagic, encodeKeyOp)
+}
+
+// encodeKeyOp writes the successful writes and reads as captured records,
+// leaving out every other operation
+func encodeKeyOp(op string, keys [][]byte, size int, err error) []byte {
+	var record []byte
+	switch {
+	case op == opSet && err == nil:
+		record = []byte{keyOpWrite}
+	case op == opGet && err == nil:
+		record = []byte{keyOpRead}
+	case op == opGet && IsKeyNotFound(err):
+		record, size = []byte{keyOpRead}, 0
+	default:
+		return nil
+	}
+	record = binary.AppendUvarint(record, uint64(len(keys[0])))
+	record = append(record, keys[0]...)
+	return binary.AppendUvarint(record, uint64(size))
+}
+
+// replayKeyOps runs the operations captured in cfg.KeysFile against db,
+// dispatching them in capture order to cfg.Concurrency workers. Writes get
+// generated values of the captured length. With one worker the replay is
+// exact; with more, operations keep their order of dispatch but may complete
+// out of order, as they did in the captured run. Operations run through the
+// same applyOp as --replay-ops.
+func replayKeyOps(db Database, cfg Config) (writeResult, readResult *PhaseResult, err error) {
+	f, err := os.Open(cfg.KeysFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open keys file: %w", err)
+	}
+	defer f.Close()
+	buf := bufio.NewReaderSize(f, readerBufferSize)
+	if !hasKeyOpsMagic(buf) {
+		return nil, nil, fmt.Errorf("%s does not hold captured operations", cfg.KeysFile)
+	}
+
+	log.Info().Str("path", cfg.KeysFile).Int("workers", cfg.Concurrency).Msg("Replaying captured keys")
+
+	depth := queueDepth(cfg)
+	jobs := make(chan keyOp, depth)
+	writeLatencies := make(chan time.Duration, depth)
+	readLatencies := make(chan time.Duration, depth)
+	writeCollector := startLatencyCollector("write", writeLatencies)
+	readCollector := startLatencyCollector("read", readLatencies)
+	var writesFailed, readsFailed, notFound, valueBytes uint64
+	var writeErrs, readErrs errorSampler
+	var backpressure feederBackpressure
+	var wg sync.WaitGroup
+
+	go func() {
+		for op := range readKeyOps(buf) {
+			sendJob(jobs, op, &backpressure)
+		}
+		close(jobs)
+	}()
+
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(cfg.Seed + int64(workerID)))
+
+			for job := range jobs {
+				var value []byte
+				latencies, failed, errs := readLatencies, &readsFailed, &readErrs
+				if job.op == opSet {
+					value = generateValue(rng, job.valueLen)
+					latencies, failed, errs = writeLatencies, &writesFailed, &writeErrs
+				}
+
+				start := time.Now()
+				_, err := applyOp(db, job.op, [][]byte{job.key}, value)
+				latencies <- time.Since(start)
+				switch {
+				case job.op == opGet && IsKeyNotFound(err):
+					atomic.AddUint64(&notFound, 1)
+				case err != nil:
+					atomic.AddUint64(failed, 1)
+					errs.record(err)
+				case job.op == opSet:
+					atomic.AddUint64(&valueBytes, uint64(len(value)))
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	close(writeLatencies)
+	close(readLatencies)
+	writeCollector.wait()
+	readCollector.wait()
+	backpressure.log("replay", depth)
+
+	// Writes and reads interleave, so each is timed by the time the workers
+	// spent on it rather than by the wall clock of the whole replay
+	writeResult = newPhaseResult("write", writeCollector, writeCollector.total/time.Duration(cfg.Concurrency))
+	writeResult.Failed = atomic.LoadUint64(&writesFailed)
+	writeResult.Successful = writeResult.Ops - writeResult.Failed
+	writeResult.FirstError = writeErrs.first
+	writeResult.Errors = writeErrs.samples()
+	writeResult.OtherErrors = writeErrs.other
+	writeResult.ValueBytes = atomic.LoadUint64(&valueBytes)
+
+	readResult = newPhaseResult("read", readCollector, readCollector.total/time.Duration(cfg.Concurrency))
+	readResult.Failed = atomic.LoadUint64(&readsFailed)
+	readResult.NotFound = atomic.LoadUint64(&notFound)
+	readResult.Successful = readResult.Ops - readResult.Failed - readResult.NotFound
+	readResult.FirstError = readErrs.first
+	readResult.Errors = readErrs.samples()
+	readResult.OtherErrors = readErrs.other
+
+	flushStart := time.Now()
+	if err := db.Flush(); err != nil {
+		return nil, nil, fmt.Errorf("flush after replay failed: %w", err)
+	}
+	writeResult.FlushTime = time.Since(flushStart)
+	return writeResult, readResult, nil
+}
@@ -0,0 +1,57 @@
+package benchmark
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyCaptureReplaysWritesAndReads(t *testing.T) {
+	quietLogs(t)
+
+	source, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.bin")
+	capture, err := NewKeyCaptureDatabase(source, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		if err := capture.Set([]byte(key), make([]byte, 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range []string{"a", "missing"} {
+		if _, closer, err := capture.Get([]byte(key)); err == nil && closer != nil {
+			closer.Close()
+		}
+	}
+	// Deletes are not captured
+	if err := capture.Delete([]byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if err := capture.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	write, read, err := replayKeyOps(target, Config{KeysFile: path, Concurrency: 1, Seed: 1})
+	if err != nil {
+		t.Fatalf("replayKeyOps: %v", err)
+	}
+	if write.Ops != 3 || write.ValueBytes != 30 {
+		t.Errorf("replayed %d writes of %d bytes, want 3 of 30", write.Ops, write.ValueBytes)
+	}
+	if read.Ops != 2 || read.NotFound != 1 {
+		t.Errorf("replayed %d reads with %d not found, want 2 with 1", read.Ops, read.NotFound)
+	}
+	if write.Elapsed != write.TotalLatency || read.Elapsed != read.TotalLatency {
+		t.Errorf("one worker must time writes and reads by their own latencies, got %v/%v and %v/%v",
+			write.Elapsed, write.TotalLatency, read.Elapsed, read.TotalLatency)
+	}
+}
@@ -0,0 +1,76 @@
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// keyCategoryUnknown is the category of keys matching no registered prefix
+const keyCategoryUnknown = -1
+
+// keyPrefix registers the prefix that keys of one category start with
+type keyPrefix struct {
+	category int
+	prefix   []byte
+}
+
+// keyPrefixTable maps key categories to their prefixes. No prefix may be a
+// prefix of another, so every key belongs to at most one category and its
+// payload is whatever follows the prefix.
+type keyPrefixTable []keyPrefix
+
+// newKeyPrefixTable builds a table from prefixes, rejecting empty prefixes and
+// prefixes that would make a key ambiguous
+func newKeyPrefixTable(prefixes ...keyPrefix) (keyPrefixTable, error) {
+	for i, p := range prefixes {
+		if len(p.prefix) == 0 {
+			return nil, fmt.Errorf("key prefix of category %d is empty", p.category)
+		}
+		for _, other := range prefixes[:i] {
+			if bytes.HasPrefix(p.prefix, other.prefix) || bytes.HasPrefix(other.prefix, p.prefix) {
+				return nil, fmt.Errorf("key prefixes %q and %q collide", other.prefix, p.prefix)
+			}
+		}
+	}
+	return keyPrefixTable(prefixes), nil
+}
+
+// classifyKey returns the category of key and the payload after its prefix,
+// or keyCategoryUnknown and the whole key when no prefix matches
+func (t keyPrefixTable) classifyKey(key []byte) (category int, payload []byte) {
+	for _, p := range t {
+		if bytes.HasPrefix(key, p.prefix) {
+			return p.category, key[len(p.prefix):]
+		}
+	}
+	return keyCategoryUnknown, key
+}
+
+// key builds a key of category from the concatenated payload parts
+func (t keyPrefixTable) key(category int, payload ...[]byte) []byte {
+	for _, p := range t {
+		if p.category != category {
+			continue
+		}
+		n := len(p.prefix)
+		for _, part := range payload {
+			n += len(part)
+		}
+		key := make([]byte, 0, n)
+		key = append(key, p.prefix...)
+		for _, part := range payload {
+			key = append(key, part...)
+		}
+		return key
+	}
+	panic(fmt.Sprintf("key category %d is not registered", category))
+}
+
+// prefixes returns the registered prefixes in registration order
+func (t keyPrefixTable) prefixes() [][]byte {
+	prefixes := make([][]byte, len(t))
+	for i, p := range t {
+		prefixes[i] = p.prefix
+	}
+	return prefixes
+}
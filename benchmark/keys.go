@@ -2,18 +2,27 @@ package benchmark
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"iter"
+	"math/rand"
 	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
 )
 
 const readerBufferSize = 1024 * 1024
 
-// loadKeysFromFile loads a binary file containing keys in the format:
-// [uvarint length][key bytes] repeating.
-func loadKeysFromFile(path string) iter.Seq[[]byte] {
+// loadKeysFromFile loads a file of keys in format, by default the binary
+// format: [uvarint length][key bytes] repeating.
+func loadKeysFromFile(path string, format KeyFormat) iter.Seq[[]byte] {
 	return func(yield func([]byte) bool) {
 		file, err := os.Open(path)
 		if err != nil {
@@ -22,7 +31,7 @@ func loadKeysFromFile(path string) iter.Seq[[]byte] {
 		defer file.Close()
 
 		r := bufio.NewReader(file)
-		for key := range loadKeysFromReader(r) {
+		for key := range loadKeysInFormat(r, format) {
 			if !yield(key) {
 				return
 			}
@@ -30,24 +39,93 @@ func loadKeysFromFile(path string) iter.Seq[[]byte] {
 	}
 }
 
-// loadKeysFromStdin loads keys from standard input in the same binary format:
-// [uvarint length][key bytes] repeating.
-func loadKeysFromStdin() iter.Seq[[]byte] {
+// loadKeysFromStdin loads keys from standard input in format, by default the
+// same binary format: [uvarint length][key bytes] repeating.
+func loadKeysFromStdin(format KeyFormat) iter.Seq[[]byte] {
 	return func(yield func([]byte) bool) {
 		r := bufio.NewReader(os.Stdin)
-		for key := range loadKeysFromReader(r) {
+		for key := range loadKeysInFormat(r, format) {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// loadKeysInFormat reads keys from r in format, the binary format when empty
+func loadKeysInFormat(r io.Reader, format KeyFormat) iter.Seq[[]byte] {
+	if format == KeyFormatHex || format == KeyFormatBase64 {
+		return loadTextKeys(r, format)
+	}
+	return loadKeysFromReader(r)
+}
+
+// loadTextKeys reads one hex or base64 encoded key per line, as dump-keys
+// writes them. Only the first comma-separated field of a line is decoded, so
+// the first column of a CSV export works too, and hex keys may carry a 0x
+// prefix. A blank line is the empty key. Lines that fail to decode, such as a
+// CSV header, are skipped and counted.
+func loadTextKeys(r io.Reader, format KeyFormat) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), readerBufferSize)
+
+		var line, malformed, firstMalformed int
+		defer func() {
+			if malformed > 0 {
+				log.Warn().
+					Int("malformed_lines", malformed).
+					Int("first_malformed_line", firstMalformed).
+					Str("format", string(format)).
+					Msg("Skipped keys file lines that failed to decode")
+			}
+		}()
+
+		for scanner.Scan() {
+			line++
+			field, _, _ := strings.Cut(scanner.Text(), ",")
+			field = strings.Trim(strings.TrimSpace(field), `"`)
+
+			var key []byte
+			var err error
+			if format == KeyFormatHex {
+				field = strings.TrimPrefix(strings.TrimPrefix(field, "0x"), "0X")
+				key, err = hex.DecodeString(field)
+			} else {
+				key, err = base64.StdEncoding.DecodeString(field)
+			}
+			if err != nil {
+				if malformed == 0 {
+					firstMalformed = line
+				}
+				malformed++
+				continue
+			}
 			if !yield(key) {
 				return
 			}
 		}
+		if err := scanner.Err(); err != nil {
+			panic(fmt.Errorf("failed to read keys at line %d: %w", line+1, err))
+		}
 	}
 }
 
 // loadKeysFromReader reads keys from an io.Reader in the binary format:
-// [uvarint length][key bytes] repeating.
+// [uvarint length][key bytes] repeating. A stream of operations captured by
+// --capture-keys yields the key of every operation in capture order.
 func loadKeysFromReader(r io.Reader) iter.Seq[[]byte] {
 	return func(yield func([]byte) bool) {
 		buf := bufio.NewReaderSize(r, readerBufferSize)
+		if hasKeyOpsMagic(buf) {
+			for op := range readKeyOps(buf) {
+				if !yield(op.key) {
+					return
+				}
+			}
+			return
+		}
+
 		for {
 			n, err := binary.ReadUvarint(buf)
 			if err != nil {
@@ -69,3 +147,121 @@ func loadKeysFromReader(r io.Reader) iter.Seq[[]byte] {
 		}
 	}
 }
+
+// sampleKeysFromDatabase iterates every key in db and reservoir-samples up to
+// count of them, so reads can target the real keys of an existing database
+func sampleKeysFromDatabase(db Database, count int, seed int64) ([][]byte, error) {
+	it, err := db.NewIterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open iterator: %w", err)
+	}
+	defer it.Close()
+
+	rng := rand.New(rand.NewSource(seed))
+	sample := make([][]byte, 0, count)
+	var seen int
+	for valid := it.First(); valid; valid = it.Next() {
+		seen++
+		if len(sample) < count {
+			sample = append(sample, slices.Clone(it.Key()))
+			continue
+		}
+		if j := rng.Intn(seen); j < count {
+			sample[j] = slices.Clone(it.Key())
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate keys: %w", err)
+	}
+
+	// The reservoir keeps the first keys in iteration order; shuffle so reads
+	// do not walk the keyspace sequentially
+	rng.Shuffle(len(sample), func(i, j int) {
+		sample[i], sample[j] = sample[j], sample[i]
+	})
+
+	log.Info().
+		Int("keys_scanned", seen).
+		Int("keys_sampled", len(sample)).
+		Msg("Sampled keys from existing database")
+
+	return sample, nil
+}
+
+// ReadOrder selects the order the read phase visits its keys in
+type ReadOrder string
+
+const (
+	ReadOrderSequential ReadOrder = "sequential" // keys in the order they were produced
+	ReadOrderRandom     ReadOrder = "random"     // as many reads, sampled with replacement
+	ReadOrderShuffled   ReadOrder = "shuffled"   // every key once, in random order
+)
+
+// validate reports whether o is a known read order; empty means sequential
+func (o ReadOrder) validate() error {
+	switch o {
+	case "", ReadOrderSequential, ReadOrderRandom, ReadOrderShuffled:
+		return nil
+	default:
+		return fmt.Errorf("invalid read order %q: expected sequential, random or shuffled", o)
+	}
+}
+
+// orderReadKeys applies order to keys. Sequential streams keys through
+// unchanged; random and shuffled buffer the whole keyset first.
+func orderReadKeys(keys iter.Seq[[]byte], order ReadOrder, seed int64) iter.Seq[[]byte] {
+	if order == "" || order == ReadOrderSequential {
+		return keys
+	}
+
+	buffered := slices.Collect(keys)
+	rng := rand.New(rand.NewSource(seed))
+
+	log.Info().Str("order", string(order)).Int64("seed", seed).Int("keys", len(buffered)).Msg("Buffered read keys for reordering")
+
+	if order == ReadOrderShuffled {
+		rng.Shuffle(len(buffered), func(i, j int) {
+			buffered[i], buffered[j] = buffered[j], buffered[i]
+		})
+		return slices.Values(buffered)
+	}
+
+	return func(yield func([]byte) bool) {
+		if len(buffered) == 0 {
+			return
+		}
+		for range buffered {
+			if !yield(buffered[rng.Intn(len(buffered))]) {
+				return
+			}
+		}
+	}
+}
+
+// sortKeysWarnCount is the key count above which --sort-keys warns that it
+// holds the whole keyset in memory
+const sortKeysWarnCount = 10_000_000
+
+// sortWriteKeys buffers the whole keyset and yields it in ascending key order,
+// the best case ingestion order for LSM compaction and B-tree page fills.
+// Repeated keys stay adjacent, so overwrites are written back to back.
+func sortWriteKeys(keys iter.Seq[[]byte], count int) iter.Seq[[]byte] {
+	if count > sortKeysWarnCount {
+		log.Warn().Int("keys", count).Msg("--sort-keys buffers every key in memory before the write phase")
+	}
+
+	start := time.Now()
+	buffered := slices.Collect(keys)
+	slices.SortFunc(buffered, bytes.Compare)
+
+	var keyBytes int
+	for _, key := range buffered {
+		keyBytes += len(key)
+	}
+	log.Info().
+		Int("keys", len(buffered)).
+		Int("key_bytes", keyBytes).
+		Float64("sort_ms", durationMs(time.Since(start))).
+		Msg("Buffered and sorted write keys")
+	return slices.Values(buffered)
+}
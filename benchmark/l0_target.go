@@ -0,0 +1,67 @@
+package benchmark
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// l0PollInterval is how often the write phase samples the L0 file count
+// while --l0-files-target is set
+const l0PollInterval = 10 * time.Millisecond
+
+// l0Watcher polls the L0 file count during the write phase and flags when it
+// reaches the target, so writing stops with compaction debt still pending
+type l0Watcher struct {
+	target  int64
+	reached atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// startL0Watcher starts polling db, returning nil when target <= 0
+func startL0Watcher(db Database, target int) *l0Watcher {
+	if target <= 0 {
+		return nil
+	}
+
+	w := &l0Watcher{
+		target: int64(target),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(l0PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if l0 := db.GetMetrics().L0FileCount; l0 >= w.target {
+					log.Info().Int64("l0_files", l0).Int64("target", w.target).Msg("L0 file target reached, stopping writes")
+					w.reached.Store(true)
+					return
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// reachedTarget reports whether the L0 file count has reached the target
+func (w *l0Watcher) reachedTarget() bool {
+	return w != nil && w.reached.Load()
+}
+
+// close stops polling and reports whether the target was reached
+func (w *l0Watcher) close() bool {
+	if w == nil {
+		return false
+	}
+	close(w.stop)
+	<-w.done
+	return w.reached.Load()
+}
@@ -0,0 +1,118 @@
+package benchmark
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// latencyCSVHeader names the columns of the per-operation latency export
+var latencyCSVHeader = []string{"phase", "latency_ns", "key_len", "value_len", "result"}
+
+// Operation results in the latency export
+const (
+	opResultOK       = "ok"
+	opResultNotFound = "not_found"
+	opResultError    = "error"
+)
+
+// latencyRow is one operation of the latency export
+type latencyRow struct {
+	phase    string
+	latency  time.Duration
+	keyLen   int
+	valueLen int // bytes written, or bytes returned by a read
+	result   string
+}
+
+// latencyCSV streams per-operation latencies with their key and value sizes
+// to a CSV file, so tail latency can be correlated with payload size offline.
+// A nil *latencyCSV records nothing.
+type latencyCSV struct {
+	f    *os.File
+	w    *csv.Writer
+	bw   *bufio.Writer
+	rows chan latencyRow
+	done chan struct{}
+	err  error
+}
+
+// startLatencyCSV creates the export at path and starts its writer
+func startLatencyCSV(path string) (*latencyCSV, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create latency CSV: %w", err)
+	}
+	bw := bufio.NewWriterSize(f, 1<<20)
+	c := &latencyCSV{
+		f:    f,
+		bw:   bw,
+		w:    csv.NewWriter(bw),
+		rows: make(chan latencyRow, 4096),
+		done: make(chan struct{}),
+	}
+	if err := c.w.Write(latencyCSVHeader); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write latency CSV: %w", err)
+	}
+
+	go func() {
+		defer close(c.done)
+		record := make([]string, len(latencyCSVHeader))
+		for row := range c.rows {
+			if c.err != nil {
+				continue
+			}
+			record[0] = row.phase
+			record[1] = strconv.FormatInt(int64(row.latency), 10)
+			record[2] = strconv.Itoa(row.keyLen)
+			record[3] = strconv.Itoa(row.valueLen)
+			record[4] = row.result
+			c.err = c.w.Write(record)
+		}
+	}()
+	return c, nil
+}
+
+// record queues one operation for the export
+func (c *latencyCSV) record(phase string, latency time.Duration, keyLen, valueLen int, err error) {
+	if c == nil {
+		return
+	}
+	result := opResultOK
+	if err != nil {
+		result = opResultError
+		if IsKeyNotFound(err) {
+			result = opResultNotFound
+		}
+	}
+	c.rows <- latencyRow{phase: phase, latency: latency, keyLen: keyLen, valueLen: valueLen, result: result}
+}
+
+// close drains the queued rows and closes the file
+func (c *latencyCSV) close() error {
+	if c == nil {
+		return nil
+	}
+	close(c.rows)
+	<-c.done
+
+	err := c.err
+	c.w.Flush()
+	if err == nil {
+		err = c.w.Error()
+	}
+	if flushErr := c.bw.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := c.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write latency CSV: %w", err)
+	}
+	return nil
+}
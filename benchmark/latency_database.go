@@ -0,0 +1,139 @@
+package benchmark
+
+import (
+	"io"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LatencyInjectingDatabase wraps a Database and sleeps before delegating each
+// operation, modeling slower storage (network-attached volumes, spinning disks)
+// on fast local hardware. Gets and iterator creation pay the read latency;
+// sets, deletes and batch applies pay the write latency. Each delay gets a
+// uniform jitter in [0, jitter) on top. Sleeping blocks the calling worker
+// like waiting on I/O does without holding a CPU, so concurrency hides the
+// latency as it would with real storage. Timer granularity can make short
+// delays overshoot, so Close reports the requested and the actual sleep time.
+type LatencyInjectingDatabase struct {
+	Database
+
+	read, write, jitter time.Duration
+
+	reads, writes atomic.Uint64
+	requested     atomic.Int64 // nanoseconds of delay requested
+	slept         atomic.Int64 // nanoseconds actually slept
+}
+
+// NewLatencyInjectingDatabase wraps db, delaying reads by read and writes by
+// write, each plus up to jitter
+func NewLatencyInjectingDatabase(db Database, read, write, jitter time.Duration) *LatencyInjectingDatabase {
+	return &LatencyInjectingDatabase{Database: db, read: read, write: write, jitter: jitter}
+}
+
+// delay sleeps for base plus jitter, skipping operations without a base latency
+func (l *LatencyInjectingDatabase) delay(base time.Duration, count *atomic.Uint64) {
+	if base <= 0 {
+		return
+	}
+	d := base
+	if l.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(l.jitter)))
+	}
+	start := time.Now()
+	time.Sleep(d)
+	l.slept.Add(int64(time.Since(start)))
+	l.requested.Add(int64(d))
+	count.Add(1)
+}
+
+func (l *LatencyInjectingDatabase) Set(key, value []byte) error {
+	l.delay(l.write, &l.writes)
+	return l.Database.Set(key, value)
+}
+
+func (l *LatencyInjectingDatabase) Get(key []byte) ([]byte, io.Closer, error) {
+	l.delay(l.read, &l.reads)
+	return l.Database.Get(key)
+}
+
+func (l *LatencyInjectingDatabase) Delete(key []byte) error {
+	l.delay(l.write, &l.writes)
+	return l.Database.Delete(key)
+}
+
+// NewIterator pays the read latency once, for the seek; stepping through the
+// range is assumed to hit blocks the seek already brought in
+func (l *LatencyInjectingDatabase) NewIterator(start, end []byte) (Iterator, error) {
+	l.delay(l.read, &l.reads)
+	return l.Database.NewIterator(start, end)
+}
+
+// NewBatch returns a batch whose Apply pays the write latency once, as one
+// write to storage. The wrapped database must be a Batcher, which
+// Capabilities.SupportsBatch promises.
+func (l *LatencyInjectingDatabase) NewBatch() Batch {
+	return &latencyInjectingBatch{Batch: l.Database.(Batcher).NewBatch(), db: l}
+}
+
+// latencyInjectingBatch delays the Apply of a wrapped batch
+type latencyInjectingBatch struct {
+	Batch
+	db *LatencyInjectingDatabase
+}
+
+func (b *latencyInjectingBatch) Apply() error {
+	b.db.delay(b.db.write, &b.db.writes)
+	return b.Batch.Apply()
+}
+
+// NewSnapshot returns a snapshot whose reads each pay the read latency. The
+// wrapped database must be a Snapshotter, which Capabilities.SupportsSnapshot
+// promises.
+func (l *LatencyInjectingDatabase) NewSnapshot() (Snapshot, error) {
+	snap, err := l.Database.(Snapshotter).NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &latencyInjectingSnapshot{Snapshot: snap, db: l}, nil
+}
+
+// latencyInjectingSnapshot delays the reads of a wrapped snapshot
+type latencyInjectingSnapshot struct {
+	Snapshot
+	db *LatencyInjectingDatabase
+}
+
+func (s *latencyInjectingSnapshot) Get(key []byte) ([]byte, io.Closer, error) {
+	s.db.delay(s.db.read, &s.db.reads)
+	return s.Snapshot.Get(key)
+}
+
+// WaitForQuiesce forwards to the wrapped database when it is a Quiescer
+func (l *LatencyInjectingDatabase) WaitForQuiesce(timeout time.Duration) (bool, error) {
+	if q, ok := l.Database.(Quiescer); ok {
+		return q.WaitForQuiesce(timeout)
+	}
+	return true, nil
+}
+
+// ValueCopyStats forwards to the wrapped database when it is a ValueCopyReporter
+func (l *LatencyInjectingDatabase) ValueCopyStats() (uint64, time.Duration) {
+	if r, ok := l.Database.(ValueCopyReporter); ok {
+		return r.ValueCopyStats()
+	}
+	return 0, 0
+}
+
+// Close closes the wrapped database and reports the latency injected
+func (l *LatencyInjectingDatabase) Close() error {
+	log.Info().
+		Uint64("delayed_reads", l.reads.Load()).
+		Uint64("delayed_writes", l.writes.Load()).
+		Dur("requested", time.Duration(l.requested.Load())).
+		Dur("slept", time.Duration(l.slept.Load())).
+		Msg("Injected storage latency")
+	return l.Database.Close()
+}
@@ -0,0 +1,151 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LoadCurveConfig defines a series of target rates from the load-curve subcommand
+type LoadCurveConfig struct {
+	Rates          []float64 // target operations per second, ascending
+	Ops            int       // writes, then reads, measured at every rate
+	MaxP99Factor   float64   // stop once a phase's p99 exceeds this multiple of its p99 at the first rate
+	Saturation     float64   // stop once a phase achieves less than this fraction of the target rate
+	DBPath         string    // directory holding one database per rate
+	Keep           bool      // keep the databases instead of removing them after each rate
+	Output         string    // CSV file the results are written to
+	DatabaseType   string
+	BlockCacheSize int64
+	WorkloadType   string
+	ValueSize      int
+	Concurrency    int
+	Seed           int64
+	LogFormat      string
+}
+
+// loadCurveHeader names the columns of the load curve CSV
+var loadCurveHeader = []string{
+	"target_ops_per_sec",
+	"write_ops_per_sec", "write_p50_ns", "write_p99_ns",
+	"read_ops_per_sec", "read_p50_ns", "read_p99_ns",
+}
+
+// RunLoadCurve runs the workload at each target rate in turn against a fresh
+// database and writes the achieved throughput and latency per rate as CSV. It
+// stops at the first rate the backend cannot sustain or at which p99 latency
+// explodes, so the last rows mark the saturation point.
+func RunLoadCurve(cfg LoadCurveConfig) error {
+	setupLog(Config{LogFormat: cfg.LogFormat})
+	if len(cfg.Rates) == 0 {
+		return fmt.Errorf("load curve needs at least one rate")
+	}
+	for _, rate := range cfg.Rates {
+		if rate <= 0 {
+			return fmt.Errorf("invalid rate %v: rates must be positive", rate)
+		}
+	}
+
+	f, err := os.Create(cfg.Output)
+	if err != nil {
+		return fmt.Errorf("failed to create load curve output: %w", err)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	if err := w.Write(loadCurveHeader); err != nil {
+		return fmt.Errorf("failed to write load curve output: %w", err)
+	}
+
+	var baseWriteP99, baseReadP99 time.Duration
+	for i, rate := range cfg.Rates {
+		path := filepath.Join(cfg.DBPath, fmt.Sprintf("rate-%.0f", rate))
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", path, err)
+		}
+
+		log.Info().Float64("target_ops_per_sec", rate).Int("step", i+1).Int("steps", len(cfg.Rates)).Msg("Load curve step")
+		result, err := runBenchmark(Config{
+			KeyCount:          cfg.Ops,
+			ValueSize:         cfg.ValueSize,
+			Seed:              cfg.Seed,
+			DBPath:            path,
+			BenchmarkID:       fmt.Sprintf("load-curve-%.0f", rate),
+			WriteEnabled:      true,
+			Concurrency:       cfg.Concurrency,
+			LogFormat:         cfg.LogFormat,
+			BlockCacheSize:    cfg.BlockCacheSize,
+			DatabaseType:      cfg.DatabaseType,
+			WorkloadType:      cfg.WorkloadType,
+			TargetOpsPerSec:   rate,
+		})
+		if err != nil {
+			return fmt.Errorf("rate %.0f: %w", rate, err)
+		}
+		if !cfg.Keep {
+			if err := os.RemoveAll(path); err != nil {
+				log.Warn().Err(err).Str("path", path).Msg("Failed to remove load curve database")
+			}
+		}
+
+		row := []string{
+			strconv.FormatFloat(rate, 'f', 0, 64),
+			strconv.FormatFloat(result.WriteOpsPerSec, 'f', 1, 64),
+			strconv.FormatInt(int64(result.WriteP50), 10),
+			strconv.FormatInt(int64(result.WriteP99), 10),
+			strconv.FormatFloat(result.ReadOpsPerSec, 'f', 1, 64),
+			strconv.FormatInt(int64(result.ReadP50), 10),
+			strconv.FormatInt(int64(result.ReadP99), 10),
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to write load curve output: %w", err)
+		}
+		// Flush every row so a long curve leaves usable results if interrupted
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to write load curve output: %w", err)
+		}
+
+		log.Info().
+			Float64("target_ops_per_sec", rate).
+			Float64("write_ops_per_sec", result.WriteOpsPerSec).
+			Float64("write_p99_latency_ms", durationMs(result.WriteP99)).
+			Float64("read_ops_per_sec", result.ReadOpsPerSec).
+			Float64("read_p99_latency_ms", durationMs(result.ReadP99)).
+			Msg("Load curve step complete")
+
+		if i == 0 {
+			baseWriteP99, baseReadP99 = result.WriteP99, result.ReadP99
+		}
+		if reason := loadCurveStop(cfg, rate, result, baseWriteP99, baseReadP99); reason != "" {
+			log.Info().Float64("target_ops_per_sec", rate).Str("reason", reason).Msg("Load curve saturated")
+			break
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write load curve output: %w", err)
+	}
+	log.Info().Str("path", cfg.Output).Msg("Wrote load curve results")
+	return nil
+}
+
+// loadCurveStop returns why the curve should stop after result, or "" to go on
+func loadCurveStop(cfg LoadCurveConfig, rate float64, result *BenchmarkResult, baseWriteP99, baseReadP99 time.Duration) string {
+	floor := rate * cfg.Saturation
+	switch {
+	case result.WriteOpsPerSec < floor:
+		return "write throughput fell below the target rate"
+	case result.ReadOpsPerSec < floor:
+		return "read throughput fell below the target rate"
+	case cfg.MaxP99Factor > 0 && baseWriteP99 > 0 && float64(result.WriteP99) > cfg.MaxP99Factor*float64(baseWriteP99):
+		return "write p99 latency exploded"
+	case cfg.MaxP99Factor > 0 && baseReadP99 > 0 && float64(result.ReadP99) > cfg.MaxP99Factor*float64(baseReadP99):
+		return "read p99 latency exploded"
+	}
+	return ""
+}
@@ -1,9 +1,11 @@
 package benchmark
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"sync"
 	"time"
 
@@ -91,13 +93,23 @@ func NewMDBXDatabase(cfg DatabaseConfig) (Database, error) {
 		return nil, fmt.Errorf("failed to open MDBX environment: %w", err)
 	}
 
-	// Open main database
+	// Open main database; a read-only environment cannot begin the write
+	// transaction creating it, so it must already exist
 	var db mdbx.DBI
-	err = env.Update(func(txn *mdbx.Txn) error {
+	openRoot := func(txn *mdbx.Txn) error {
 		var err error
-		db, err = txn.OpenRoot(mdbx.Create)
+		if cfg.ReadOnly {
+			db, err = txn.OpenRoot(0)
+		} else {
+			db, err = txn.OpenRoot(mdbx.Create)
+		}
 		return err
-	})
+	}
+	if cfg.ReadOnly {
+		err = env.View(openRoot)
+	} else {
+		err = env.Update(openRoot)
+	}
 	if err != nil {
 		env.Close()
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -167,7 +179,7 @@ func (d *MDBXDatabase) Get(key []byte) ([]byte, io.Closer, error) {
 	if err != nil {
 		d.metrics.ReadErrors++
 		if mdbx.IsNotFound(err) {
-			return nil, nil, fmt.Errorf("key not found")
+			return nil, nil, ErrKeyNotFound
 		}
 		return nil, nil, fmt.Errorf("failed to get key: %w", err)
 	}
@@ -176,6 +188,68 @@ func (d *MDBXDatabase) Get(key []byte) ([]byte, io.Closer, error) {
 	return value, &noopCloser{}, nil
 }
 
+// Delete removes a key from the database
+func (d *MDBXDatabase) Delete(key []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return fmt.Errorf("database is closed")
+	}
+
+	err := d.env.Update(func(txn *mdbx.Txn) error {
+		err := txn.Del(d.db, key, nil)
+		if mdbx.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		d.metrics.WriteErrors++
+		return fmt.Errorf("failed to delete key: %w", err)
+	}
+
+	return nil
+}
+
+// NewIterator returns a cursor-backed iterator over [start, end)
+// The iterator holds a read transaction until it is closed
+func (d *MDBXDatabase) NewIterator(start, end []byte) (Iterator, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	// Read transactions must be used and released on the same OS thread
+	runtime.LockOSThread()
+	txn, err := d.env.BeginTxn(nil, mdbx.Readonly)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("failed to begin read transaction: %w", err)
+	}
+
+	cursor, err := txn.OpenCursor(d.db)
+	if err != nil {
+		txn.Abort()
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("failed to open cursor: %w", err)
+	}
+
+	return &mdbxIterator{
+		txn:    txn,
+		cursor: cursor,
+		start:  start,
+		end:    end,
+	}, nil
+}
+
+// Compact is a no-op for MDBX, which reuses freed pages in place
+func (d *MDBXDatabase) Compact(start, end []byte) error {
+	return nil
+}
+
 // Flush ensures all data is written to disk
 func (d *MDBXDatabase) Flush() error {
 	d.mu.Lock()
@@ -218,6 +292,101 @@ func (d *MDBXDatabase) Close() error {
 	return nil
 }
 
+// Capabilities reports iteration and deletes through cursors and write transactions
+func (d *MDBXDatabase) Capabilities() DatabaseCapabilities {
+	return DatabaseCapabilities{SupportsSnapshot: true, SupportsIterator: true, SupportsDelete: true}
+}
+
+// EffectiveConfig implements ConfigReporter with the geometry, limits and
+// flags of the open environment, which MDBX resolves from its own defaults
+func (d *MDBXDatabase) EffectiveConfig() map[string]interface{} {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	config := map[string]interface{}{}
+	if d.closed {
+		return config
+	}
+
+	// Info reads through a read transaction, bound to the OS thread
+	runtime.LockOSThread()
+	info, err := d.env.Info(nil)
+	runtime.UnlockOSThread()
+	if err == nil {
+		config["geometry_lower"] = info.Geo.Lower
+		config["geometry_upper"] = info.Geo.Upper
+		config["geometry_current"] = info.Geo.Current
+		config["geometry_growth_step"] = info.Geo.Grow
+		config["geometry_shrink_threshold"] = info.Geo.Shrink
+		config["map_size"] = info.MapSize
+		config["page_size"] = info.PageSize
+		config["max_readers"] = info.MaxReaders
+	}
+	if maxDbs, err := d.env.GetOption(mdbx.OptMaxDB); err == nil {
+		config["max_dbs"] = maxDbs
+	}
+	if flags, err := d.env.Flags(); err == nil {
+		config["no_sync"] = flags&mdbx.UtterlyNoSync == mdbx.UtterlyNoSync
+		config["no_meta_sync"] = flags&mdbx.NoMetaSync != 0
+		config["write_map"] = flags&mdbx.WriteMap != 0
+		config["no_readahead"] = flags&mdbx.NoReadahead != 0
+		config["read_only"] = flags&mdbx.Readonly != 0
+	}
+	return config
+}
+
+// NewSnapshot implements Snapshotter with a read transaction, which occupies
+// one of the MaxReaders slots of the reader table until the snapshot is closed.
+// Like an iterator, the snapshot must be used and closed on the goroutine that
+// opened it.
+func (d *MDBXDatabase) NewSnapshot() (Snapshot, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	// Read transactions must be used and released on the same OS thread
+	runtime.LockOSThread()
+	txn, err := d.env.BeginTxn(nil, mdbx.Readonly)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("failed to begin read transaction: %w", err)
+	}
+	return &mdbxSnapshot{txn: txn, db: d.db}, nil
+}
+
+// mdbxSnapshot serves reads from a read transaction
+type mdbxSnapshot struct {
+	txn *mdbx.Txn
+	db  mdbx.DBI
+}
+
+func (s *mdbxSnapshot) Get(key []byte) ([]byte, io.Closer, error) {
+	val, err := s.txn.Get(s.db, key)
+	if err != nil {
+		if mdbx.IsNotFound(err) {
+			return nil, nil, ErrKeyNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get key: %w", err)
+	}
+	// Copy the value like Get, for the same work per read
+	value := make([]byte, len(val))
+	copy(value, val)
+	return value, &noopCloser{}, nil
+}
+
+func (s *mdbxSnapshot) Close() error {
+	if s.txn == nil {
+		return nil
+	}
+	s.txn.Abort()
+	s.txn = nil
+	runtime.UnlockOSThread()
+	return nil
+}
+
 // GetMetrics returns database performance metrics
 func (d *MDBXDatabase) GetMetrics() DatabaseMetrics {
 	d.mu.RLock()
@@ -239,6 +408,90 @@ func (d *MDBXDatabase) GetMetrics() DatabaseMetrics {
 	return metrics
 }
 
+// mdbxIterator adapts an MDBX cursor to the Iterator interface
+type mdbxIterator struct {
+	txn    *mdbx.Txn
+	cursor *mdbx.Cursor
+	start  []byte
+	end    []byte
+	key    []byte
+	value  []byte
+	valid  bool
+	err    error
+}
+
+func (it *mdbxIterator) First() bool {
+	if it.start != nil {
+		return it.move(it.start, mdbx.SetRange)
+	}
+	return it.move(nil, mdbx.First)
+}
+
+func (it *mdbxIterator) Next() bool {
+	if !it.valid {
+		return false
+	}
+	return it.move(nil, mdbx.Next)
+}
+
+func (it *mdbxIterator) Last() bool {
+	if it.end != nil {
+		// Position at the first key >= end and step back from it
+		_, _, err := it.cursor.Get(it.end, nil, mdbx.SetRange)
+		if err == nil {
+			return it.move(nil, mdbx.Prev)
+		}
+		if !mdbx.IsNotFound(err) {
+			it.err = err
+			it.valid = false
+			return false
+		}
+	}
+	return it.move(nil, mdbx.Last)
+}
+
+func (it *mdbxIterator) Prev() bool {
+	if !it.valid {
+		return false
+	}
+	return it.move(nil, mdbx.Prev)
+}
+
+func (it *mdbxIterator) move(setkey []byte, op uint) bool {
+	key, value, err := it.cursor.Get(setkey, nil, op)
+	if err != nil {
+		if !mdbx.IsNotFound(err) {
+			it.err = err
+		}
+		it.valid = false
+		return false
+	}
+	if (it.end != nil && bytes.Compare(key, it.end) >= 0) ||
+		(it.start != nil && bytes.Compare(key, it.start) < 0) {
+		it.valid = false
+		return false
+	}
+	it.key, it.value, it.valid = key, value, true
+	return true
+}
+
+func (it *mdbxIterator) Valid() bool   { return it.valid }
+func (it *mdbxIterator) Key() []byte   { return it.key }
+func (it *mdbxIterator) Value() []byte { return it.value }
+func (it *mdbxIterator) Error() error  { return it.err }
+
+func (it *mdbxIterator) Close() error {
+	if it.txn == nil {
+		return nil
+	}
+	it.cursor.Close()
+	it.txn.Abort()
+	it.txn = nil
+	it.valid = false
+	runtime.UnlockOSThread()
+	return nil
+}
+
 // noopCloser is a no-op implementation of io.Closer
 type noopCloser struct{}
 
@@ -0,0 +1,203 @@
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// MemoryDatabase implements the Database interface with an in-memory map.
+// It has no durability and is meant for tests and for measuring the harness
+// overhead without a storage engine underneath.
+type MemoryDatabase struct {
+	mu      sync.RWMutex
+	data    map[string][]byte
+	closed  bool
+	metrics DatabaseMetrics
+}
+
+// NewMemoryDatabase creates a new empty in-memory database
+func NewMemoryDatabase(cfg DatabaseConfig) (Database, error) {
+	return &MemoryDatabase{
+		data: make(map[string][]byte),
+	}, nil
+}
+
+// Set stores a copy of value under key
+func (d *MemoryDatabase) Set(key, value []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if old, ok := d.data[string(key)]; ok {
+		d.metrics.DataSize -= uint64(len(key) + len(old))
+	}
+	d.data[string(key)] = bytes.Clone(value)
+	d.metrics.DataSize += uint64(len(key) + len(value))
+	d.metrics.WriteCount++
+	return nil
+}
+
+// Get returns a copy of the value stored under key
+func (d *MemoryDatabase) Get(key []byte) ([]byte, io.Closer, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, nil, fmt.Errorf("database is closed")
+	}
+
+	d.metrics.ReadCount++
+	value, ok := d.data[string(key)]
+	if !ok {
+		return nil, nil, ErrKeyNotFound
+	}
+	return bytes.Clone(value), nil, nil
+}
+
+// Delete removes key, ignoring keys that do not exist
+func (d *MemoryDatabase) Delete(key []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return fmt.Errorf("database is closed")
+	}
+
+	if old, ok := d.data[string(key)]; ok {
+		d.metrics.DataSize -= uint64(len(key) + len(old))
+		delete(d.data, string(key))
+	}
+	return nil
+}
+
+// NewIterator returns an iterator over a sorted snapshot of the keys in [start, end)
+func (d *MemoryDatabase) NewIterator(start, end []byte) (Iterator, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("database is closed")
+	}
+
+	it := &memoryIterator{pos: -1}
+	for k := range d.data {
+		key := []byte(k)
+		if start != nil && bytes.Compare(key, start) < 0 {
+			continue
+		}
+		if end != nil && bytes.Compare(key, end) >= 0 {
+			continue
+		}
+		it.keys = append(it.keys, key)
+	}
+	slices.SortFunc(it.keys, bytes.Compare)
+
+	it.values = make([][]byte, len(it.keys))
+	for i, key := range it.keys {
+		it.values[i] = d.data[string(key)]
+	}
+	return it, nil
+}
+
+// Compact is a no-op: there is nothing to reclaim
+func (d *MemoryDatabase) Compact(start, end []byte) error {
+	return nil
+}
+
+// Flush is a no-op: writes are visible as soon as Set returns
+func (d *MemoryDatabase) Flush() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.metrics.FlushCount++
+	return nil
+}
+
+// Close releases the stored data
+func (d *MemoryDatabase) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.closed = true
+	d.data = nil
+	return nil
+}
+
+// Capabilities reports iteration and deletes, both served from the map
+func (d *MemoryDatabase) Capabilities() DatabaseCapabilities {
+	return DatabaseCapabilities{SupportsIterator: true, SupportsDelete: true}
+}
+
+// GetMetrics returns operation counts and the stored data size
+func (d *MemoryDatabase) GetMetrics() DatabaseMetrics {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	metrics := d.metrics
+	metrics.KeyCount = uint64(len(d.data))
+	return metrics
+}
+
+// memoryIterator walks a snapshot taken when the iterator was created
+type memoryIterator struct {
+	keys   [][]byte
+	values [][]byte
+	pos    int
+}
+
+func (it *memoryIterator) First() bool {
+	it.pos = 0
+	return it.Valid()
+}
+
+func (it *memoryIterator) Next() bool {
+	if it.Valid() {
+		it.pos++
+	}
+	return it.Valid()
+}
+
+func (it *memoryIterator) Last() bool {
+	it.pos = len(it.keys) - 1
+	return it.Valid()
+}
+
+func (it *memoryIterator) Prev() bool {
+	if it.Valid() {
+		it.pos--
+	}
+	return it.Valid()
+}
+
+func (it *memoryIterator) Valid() bool {
+	return it.pos >= 0 && it.pos < len(it.keys)
+}
+
+func (it *memoryIterator) Key() []byte {
+	if !it.Valid() {
+		return nil
+	}
+	return it.keys[it.pos]
+}
+
+func (it *memoryIterator) Value() []byte {
+	if !it.Valid() {
+		return nil
+	}
+	return it.values[it.pos]
+}
+
+func (it *memoryIterator) Error() error {
+	return nil
+}
+
+func (it *memoryIterator) Close() error {
+	it.keys, it.values = nil, nil
+	return nil
+}
@@ -0,0 +1,96 @@
+package benchmark
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// startMetricsSampler calls db.GetMetrics every interval from its own goroutine
+// and logs the result as a time series. Each call is timed so the sampling
+// overhead can be judged; for Pebble, GetMetrics walks the LSM under the DB
+// mutex and can briefly contend with writers. The returned function stops the
+// sampler and logs the total overhead.
+func startMetricsSampler(db Database, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		start := time.Now()
+		var samples int
+		var totalCost, maxCost time.Duration
+		var prevHits, prevMisses int64
+		var prevStalls uint64
+		var prevStallTime time.Duration
+
+		for {
+			select {
+			case <-done:
+				avgCost := time.Duration(0)
+				if samples > 0 {
+					avgCost = totalCost / time.Duration(samples)
+				}
+				wall := time.Since(start)
+				log.Info().
+					Int("samples", samples).
+					Dur("total_cost", totalCost).
+					Dur("avg_cost", avgCost).
+					Dur("max_cost", maxCost).
+					Float64("overhead_pct", float64(totalCost)/float64(wall)*100).
+					Msg("Metrics sampling overhead")
+				return
+			case <-ticker.C:
+				callStart := time.Now()
+				metrics := db.GetMetrics()
+				cost := time.Since(callStart)
+
+				samples++
+				totalCost += cost
+				maxCost = max(maxCost, cost)
+
+				// Hit ratio over the interval rather than since startup, so
+				// changes in the working set show up
+				hits := metrics.CacheHits - prevHits
+				misses := metrics.CacheMisses - prevMisses
+				prevHits, prevMisses = metrics.CacheHits, metrics.CacheMisses
+				hitRatio := float64(0)
+				if hits+misses > 0 {
+					hitRatio = float64(hits) / float64(hits+misses)
+				}
+
+				log.Info().
+					Dur("elapsed", time.Since(start)).
+					Float64("cache_hit_ratio", hitRatio).
+					Int64("l0_files", metrics.L0FileCount).
+					Int64("compactions", metrics.CompactionOps).
+					Int64("memtable_size", metrics.MemTableSize).
+					Int64("cache_size", metrics.CacheSize).
+					Uint64("write_stalls", metrics.WriteStallCount).
+					Dur("metrics_call_cost", cost).
+					Msg("Metrics sample")
+
+				// Stalls are what turn into write tail latency spikes
+				if stalls := metrics.WriteStallCount - prevStalls; stalls > 0 {
+					log.Warn().
+						Dur("elapsed", time.Since(start)).
+						Uint64("stalls", stalls).
+						Dur("stalled", metrics.WriteStallDuration-prevStallTime).
+						Uint64("total_stalls", metrics.WriteStallCount).
+						Dur("total_stalled", metrics.WriteStallDuration).
+						Msg("Write stall detected")
+				}
+				prevStalls, prevStallTime = metrics.WriteStallCount, metrics.WriteStallDuration
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
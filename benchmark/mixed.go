@@ -0,0 +1,186 @@
+package benchmark
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// mixedWriteSeedOffset separates the keys written by the mixed phase from the
+// keys of the write phase
+const mixedWriteSeedOffset = 2
+
+// poolOp performs one operation on key, reporting whether a read missed
+type poolOp func(rng *rand.Rand, key []byte) (notFound bool, err error)
+
+// runMixedPhase runs a read pool and a write pool against db at the same time.
+// cfg.KeyCount operations are split by cfg.ReadRatio: the readers take their
+// keys from readKeys and the writers insert fresh workload keys. Each pool
+// has its own worker count and is reported separately. With --read-ratios the
+// readers only take the keys the workload's ShouldRead picks.
+func runMixedPhase(db Database, cfg Config, readKeys iter.Seq[[]byte], workload Workload) (*PhaseResult, *PhaseResult, error) {
+	readWorkers, writeWorkers := cfg.ReadWorkers, cfg.WriteWorkers
+	if readWorkers <= 0 {
+		readWorkers = max(cfg.Concurrency, 1)
+	}
+	if writeWorkers <= 0 {
+		writeWorkers = max(cfg.Concurrency, 1)
+	}
+	reads := int(float64(cfg.KeyCount) * cfg.ReadRatio)
+	writes := cfg.KeyCount - reads
+
+	log.Info().
+		Int("read_workers", readWorkers).
+		Int("write_workers", writeWorkers).
+		Int("reads", reads).
+		Int("writes", writes).
+		Msg("Beginning mixed phase")
+
+	if cfg.ReadRatios != "" {
+		readKeys = selectReads(readKeys, workload, cfg.Seed)
+	}
+
+	readOp := func(_ *rand.Rand, key []byte) (bool, error) {
+		_, closer, err := db.Get(key)
+		if err != nil {
+			return IsKeyNotFound(err), err
+		}
+		if closer != nil {
+			closer.Close()
+		}
+		return false, nil
+	}
+	shape := newValueShape(cfg)
+	writeOp := func(rng *rand.Rand, key []byte) (bool, error) {
+		return false, db.Set(key, generateWorkloadValue(rng, workload, key, shape))
+	}
+
+	var readResult, writeResult *PhaseResult
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		readResult = runPool(cfg, "mixed-read", readWorkers, takeKeys(readKeys, reads), readOp)
+	}()
+	go func() {
+		defer wg.Done()
+		writeResult = runPool(cfg, "mixed-write", writeWorkers,
+			workload.GenerateKeys(cfg.Seed+mixedWriteSeedOffset, writes), writeOp)
+	}()
+	wg.Wait()
+
+	for _, r := range []*PhaseResult{readResult, writeResult} {
+		log.Info().
+			Str("pool", r.Phase).
+			Uint64("ops", r.Ops).
+			Uint64("failed", r.Failed).
+			Uint64("not_found", r.NotFound).
+			Float64("ops_per_sec", r.OpsPerSec()).
+			Float64("avg_latency_ms", durationMs(r.AvgLatency())).
+			Float64("p50_latency_ms", durationMs(r.P50)).
+			Float64("p99_latency_ms", durationMs(r.P99)).
+			Dur("elapsed", r.Elapsed).
+			Msg("Mixed pool complete")
+	}
+
+	if err := db.Flush(); err != nil {
+		return nil, nil, fmt.Errorf("mixed phase flush failed: %w", err)
+	}
+	return readResult, writeResult, nil
+}
+
+// runPool applies op to every key with a pool of workers and measures it as
+// one phase named name
+func runPool(cfg Config, name string, workers int, keys iter.Seq[[]byte], op poolOp) *PhaseResult {
+	depth := max(workers, 1) * defaultQueueDepthPerWorker
+	if cfg.QueueDepth > 0 {
+		depth = cfg.QueueDepth
+	}
+
+	jobs := make(chan []byte, depth)
+	latencies := make(chan time.Duration, depth)
+	collector := startLatencyCollector(name, latencies)
+	var notFound, failed uint64
+	var errs errorSampler
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	go func() {
+		for key := range keys {
+			jobs <- key
+		}
+		close(jobs)
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+
+			rng := rand.New(rand.NewSource(cfg.Seed + int64(workerID)))
+			for key := range jobs {
+				opStart := time.Now()
+				missed, err := op(rng, key)
+				latencies <- time.Since(opStart)
+
+				switch {
+				case missed:
+					atomic.AddUint64(&notFound, 1)
+				case err != nil:
+					atomic.AddUint64(&failed, 1)
+					errs.record(err)
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(latencies)
+	collector.wait()
+
+	result := newPhaseResult(name, collector, elapsed)
+	result.NotFound = notFound
+	result.Failed = failed
+	result.Successful = result.Ops - notFound - failed
+	result.FirstError = errs.first
+	result.Errors = errs.samples()
+	result.OtherErrors = errs.other
+	return result
+}
+
+// selectReads yields the keys workload.ShouldRead picks, so per-key read
+// probabilities shape the keys read
+func selectReads(keys iter.Seq[[]byte], workload Workload, seed int64) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+		for key := range keys {
+			if workload.ShouldRead(key, rng) && !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// takeKeys yields at most n keys from keys
+func takeKeys(keys iter.Seq[[]byte], n int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		if n <= 0 {
+			return
+		}
+		taken := 0
+		for key := range keys {
+			if !yield(key) {
+				return
+			}
+			if taken++; taken >= n {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package benchmark
+
+import "testing"
+
+func TestSelectReadsFollowsReadRatios(t *testing.T) {
+	ratios, err := ParseReadRatios("h=0,b=0,r=0,l=0,a=1")
+	if err != nil {
+		t.Fatalf("ParseReadRatios: %v", err)
+	}
+	cfg := blendTestConfig("")
+	cfg.ReadRatios = ratios
+	w := NewPoSMixedWorkload(cfg)
+
+	var read, accounts int
+	for key := range selectReads(w.GenerateKeys(1, 3000), w, 1) {
+		switch key[0] {
+		case 'h', 'b', 'r', 'l':
+			t.Fatalf("read block key %q with a zero read ratio", key)
+		case 'a':
+			accounts++
+		}
+		read++
+	}
+	if accounts == 0 || read == 3000 {
+		t.Fatalf("selected %d reads, %d of them accounts", read, accounts)
+	}
+}
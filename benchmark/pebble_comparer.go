@@ -0,0 +1,113 @@
+package benchmark
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+)
+
+const (
+	// PebbleComparerDefault orders keys bytewise
+	PebbleComparerDefault = "default"
+
+	// PebbleComparerBlockNum orders keys of the form prefix byte + 8-byte
+	// little-endian block number + rest by prefix, then block number, then rest
+	PebbleComparerBlockNum = "blocknum"
+)
+
+// blockNumKeyLen is the shortest key the blocknum comparer parses a block
+// number from: one prefix byte plus an 8-byte block number
+const blockNumKeyLen = 9
+
+// blockNumComparer orders block-prefixed keys numerically by their
+// little-endian block number, so consecutive blocks are adjacent in the LSM.
+//
+// It compares keys as if bytes 1..8 of every key of at least blockNumKeyLen
+// bytes were reversed. That transform is its own inverse, so the key
+// construction functions apply it, call the default implementation and apply
+// it again. Split is left at the default (no suffix), which keeps the
+// comparer valid for the row-based sstable formats Pebble uses by default.
+var blockNumComparer = func() *pebble.Comparer {
+	c := *pebble.DefaultComparer
+	c.Name = "pebble-bench.BlockNumComparator"
+	c.Compare = blockNumCompare
+	c.Equal = bytes.Equal
+	c.AbbreviatedKey = func(key []byte) uint64 {
+		return pebble.DefaultComparer.AbbreviatedKey(blockNumTransform(key))
+	}
+	c.Separator = func(dst, a, b []byte) []byte {
+		sep := pebble.DefaultComparer.Separator(nil, blockNumTransform(a), blockNumTransform(b))
+		return append(dst, blockNumTransform(sep)...)
+	}
+	c.Successor = func(dst, a []byte) []byte {
+		succ := pebble.DefaultComparer.Successor(nil, blockNumTransform(a))
+		return append(dst, blockNumTransform(succ)...)
+	}
+	c.ImmediateSuccessor = func(dst, a []byte) []byte {
+		succ := append(blockNumTransform(a), 0)
+		return append(dst, blockNumTransform(succ)...)
+	}
+	return &c
+}()
+
+// blockNumByte returns byte i of key as seen by the blocknum ordering
+func blockNumByte(key []byte, i int) byte {
+	if len(key) >= blockNumKeyLen && i >= 1 && i < blockNumKeyLen {
+		return key[blockNumKeyLen-i]
+	}
+	return key[i]
+}
+
+// blockNumCompare compares keys by prefix byte, little-endian block number and
+// remaining bytes. Keys too short to hold a block number compare bytewise.
+func blockNumCompare(a, b []byte) int {
+	if len(a) < blockNumKeyLen && len(b) < blockNumKeyLen {
+		return bytes.Compare(a, b)
+	}
+
+	n := minInt(minInt(len(a), len(b)), blockNumKeyLen)
+	for i := 0; i < n; i++ {
+		ca, cb := blockNumByte(a, i), blockNumByte(b, i)
+		if ca != cb {
+			return cmp.Compare(ca, cb)
+		}
+	}
+	if n < blockNumKeyLen {
+		return cmp.Compare(len(a), len(b))
+	}
+	return bytes.Compare(a[blockNumKeyLen:], b[blockNumKeyLen:])
+}
+
+// blockNumTransform returns a copy of key with its block number bytes reversed,
+// mapping the blocknum ordering onto the bytewise ordering and back
+func blockNumTransform(key []byte) []byte {
+	out := bytes.Clone(key)
+	if len(out) >= blockNumKeyLen {
+		for i, j := 1, blockNumKeyLen-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+	return out
+}
+
+// pebbleComparer returns the Pebble comparer for name, nil for the default
+func pebbleComparer(name string) (*pebble.Comparer, error) {
+	switch name {
+	case "", PebbleComparerDefault:
+		return nil, nil
+	case PebbleComparerBlockNum:
+		return blockNumComparer, nil
+	default:
+		return nil, fmt.Errorf("invalid pebble comparer %q: expected default or blocknum", name)
+	}
+}
+
+// keyCompare returns the ordering range bounds are checked against for name
+func keyCompare(name string) func(a, b []byte) int {
+	if name == PebbleComparerBlockNum {
+		return blockNumCompare
+	}
+	return bytes.Compare
+}
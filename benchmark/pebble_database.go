@@ -1,16 +1,39 @@
 package benchmark
 
 import (
+	"context"
 	"io"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/cockroachdb/pebble"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/rs/zerolog/log"
 )
 
 // PebbleDatabase implements the Database interface for Pebble
 type PebbleDatabase struct {
-	db    *pebble.DB
-	cache *pebble.Cache
+	db        *pebble.DB
+	cache     *pebble.Cache
+	writeOpts *pebble.WriteOptions
+
+	// Options after defaults were filled in, for EffectiveConfig
+	opts           *pebble.Options
+	tableCacheSize int
+
+	// Values copied out by Get under DatabaseConfig.PebbleCopyValues
+	copyValues bool
+	copies     atomic.Uint64
+	copyNanos  atomic.Int64
+
+	// Write stalls reported by the event listener
+	stalls *pebbleWriteStalls
+
+	// Compaction settling after Flush under DatabaseConfig.PebbleFlushWaitCompaction
+	flushWaitCompaction bool
+	flushSettleTimeout  time.Duration
+	settleNanos         atomic.Int64
 }
 
 // NewPebbleDatabase creates a new Pebble database instance
@@ -21,6 +44,20 @@ func NewPebbleDatabase(cfg DatabaseConfig) (Database, error) {
 		opts.ReadOnly = true
 	}
 
+	comparer, err := pebbleComparer(cfg.PebbleComparer)
+	if err != nil {
+		return nil, err
+	}
+	if comparer != nil {
+		opts.Comparer = comparer
+		log.Info().Str("comparer", comparer.Name).Msg("Using custom Pebble comparer")
+	}
+
+	if cfg.PebbleDisableWAL {
+		opts.DisableWAL = true
+		log.Warn().Msg("Pebble WAL disabled: writes not yet flushed to sstables are lost on a crash")
+	}
+
 	var cache *pebble.Cache
 	if cfg.BlockCacheSize >= 0 {
 		cache = pebble.NewCache(cfg.BlockCacheSize)
@@ -33,7 +70,32 @@ func NewPebbleDatabase(cfg DatabaseConfig) (Database, error) {
 		log.Info().Msg("Created Pebble with block cache disabled")
 	}
 
+	if cfg.PebbleMaxOpenFiles > 0 {
+		opts.MaxOpenFiles = cfg.PebbleMaxOpenFiles
+	}
+	opts.EnsureDefaults()
+	tableCacheSize := pebble.FileCacheSize(opts.MaxOpenFiles)
+	if cfg.PebbleTableCacheSize > 0 {
+		tableCacheSize = cfg.PebbleTableCacheSize
+		opts.FileCache = pebble.NewFileCache(opts.Experimental.FileCacheShards, tableCacheSize)
+	}
+	log.Info().
+		Int("max_open_files", opts.MaxOpenFiles).
+		Int("table_cache_size", tableCacheSize).
+		Msg("Pebble open file limits")
+	warnOpenFileLimit(opts.MaxOpenFiles)
+
+	stalls := &pebbleWriteStalls{}
+	opts.EventListener = &pebble.EventListener{
+		WriteStallBegin: stalls.begin,
+		WriteStallEnd:   stalls.end,
+	}
+
 	db, err := pebble.Open(cfg.Path, opts)
+	// Open takes its own reference to a caller-supplied table cache
+	if opts.FileCache != nil {
+		opts.FileCache.Unref()
+	}
 	if err != nil {
 		if cache != nil {
 			cache.Unref()
@@ -41,15 +103,117 @@ func NewPebbleDatabase(cfg DatabaseConfig) (Database, error) {
 		return nil, err
 	}
 
+	writeOpts := pebble.NoSync
+	if cfg.SyncWrites {
+		writeOpts = pebble.Sync
+	}
+
 	return &PebbleDatabase{
-		db:    db,
-		cache: cache,
+		db:         db,
+		cache:      cache,
+		writeOpts:  writeOpts,
+		copyValues: cfg.PebbleCopyValues,
+		stalls:     stalls,
+
+		opts:           opts,
+		tableCacheSize: tableCacheSize,
+
+		flushWaitCompaction: cfg.PebbleFlushWaitCompaction,
+		flushSettleTimeout:  cfg.FlushSettleTimeout,
 	}, nil
 }
 
+// EffectiveConfig implements ConfigReporter with the options Pebble resolved,
+// reporting the first level's block and compression settings
+func (p *PebbleDatabase) EffectiveConfig() map[string]interface{} {
+	o := p.opts
+	concurrencyLower, concurrencyUpper := o.CompactionConcurrencyRange()
+	filterPolicy := "none"
+	if o.Levels[0].FilterPolicy != nil {
+		filterPolicy = o.Levels[0].FilterPolicy.Name()
+	}
+	blockCacheSize := int64(-1)
+	if p.cache != nil {
+		blockCacheSize = p.cache.MaxSize()
+	}
+
+	return map[string]interface{}{
+		"format_major_version":           p.db.FormatMajorVersion().String(),
+		"comparer":                       o.Comparer.Name,
+		"block_cache_size":               blockCacheSize,
+		"table_cache_size":               p.tableCacheSize,
+		"max_open_files":                 o.MaxOpenFiles,
+		"memtable_size":                  o.MemTableSize,
+		"memtable_stop_writes_threshold": o.MemTableStopWritesThreshold,
+		"l0_compaction_threshold":        o.L0CompactionThreshold,
+		"l0_compaction_file_threshold":   o.L0CompactionFileThreshold,
+		"l0_stop_writes_threshold":       o.L0StopWritesThreshold,
+		"lbase_max_bytes":                o.LBaseMaxBytes,
+		"l0_target_file_size":            o.TargetFileSizes[0],
+		"compaction_concurrency_min":     concurrencyLower,
+		"compaction_concurrency_max":     concurrencyUpper,
+		"block_size":                     o.Levels[0].BlockSize,
+		"index_block_size":               o.Levels[0].IndexBlockSize,
+		"compression":                    o.Levels[0].Compression().Name,
+		"filter_policy":                  filterPolicy,
+		"bytes_per_sync":                 o.BytesPerSync,
+		"wal_bytes_per_sync":             o.WALBytesPerSync,
+		"disable_wal":                    o.DisableWAL,
+		"sync_writes":                    p.writeOpts.Sync,
+		"read_only":                      o.ReadOnly,
+	}
+}
+
+// warnOpenFileLimit warns when the process may open fewer files than Pebble
+// is allowed to keep open, in which case opens fail once the limit is reached
+func warnOpenFileLimit(maxOpenFiles int) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		log.Warn().Err(err).Msg("Failed to read the open file limit")
+		return
+	}
+	if limit.Cur < uint64(maxOpenFiles) {
+		log.Warn().
+			Uint64("ulimit_n", limit.Cur).
+			Int("max_open_files", maxOpenFiles).
+			Msg("Open file limit is lower than Pebble's max open files, raise it with ulimit -n")
+	}
+}
+
+// pebbleWriteStalls counts the write stalls Pebble's event listener reports
+// and the time writes spent stalled. Pebble's metrics do not track stalls.
+type pebbleWriteStalls struct {
+	count   atomic.Uint64
+	nanos   atomic.Int64 // total duration of ended stalls
+	started atomic.Int64 // UnixNano start of the ongoing stall, 0 when none
+}
+
+// begin is the WriteStallBegin listener. Pebble begins and ends stalls under
+// the commit mutex, so at most one is ongoing.
+func (s *pebbleWriteStalls) begin(info pebble.WriteStallBeginInfo) {
+	s.count.Add(1)
+	s.started.Store(time.Now().UnixNano())
+}
+
+// end is the WriteStallEnd listener
+func (s *pebbleWriteStalls) end() {
+	if started := s.started.Swap(0); started != 0 {
+		s.nanos.Add(time.Now().UnixNano() - started)
+	}
+}
+
+// total returns the stalls begun and the time writes were stalled
+func (s *pebbleWriteStalls) total() (uint64, time.Duration) {
+	nanos := s.nanos.Load()
+	if started := s.started.Load(); started != 0 {
+		nanos += time.Now().UnixNano() - started
+	}
+	return s.count.Load(), time.Duration(nanos)
+}
+
 // Set implements Database.Set for Pebble
 func (p *PebbleDatabase) Set(key, value []byte) error {
-	return p.db.Set(key, value, pebble.NoSync)
+	return p.db.Set(key, value, p.writeOpts)
 }
 
 // Get implements Database.Get for Pebble  
@@ -61,12 +225,103 @@ func (p *PebbleDatabase) Get(key []byte) ([]byte, io.Closer, error) {
 		}
 		return nil, nil, err
 	}
+	if p.copyValues {
+		start := time.Now()
+		copied := append([]byte(nil), value...)
+		closer.Close()
+		p.copyNanos.Add(int64(time.Since(start)))
+		p.copies.Add(1)
+		return copied, nil, nil
+	}
 	return value, closer, nil
 }
 
-// Flush implements Database.Flush for Pebble
+// ValueCopyStats implements ValueCopyReporter for Pebble
+func (p *PebbleDatabase) ValueCopyStats() (uint64, time.Duration) {
+	return p.copies.Load(), time.Duration(p.copyNanos.Load())
+}
+
+// Delete implements Database.Delete for Pebble
+func (p *PebbleDatabase) Delete(key []byte) error {
+	return p.db.Delete(key, p.writeOpts)
+}
+
+// NewIterator implements Database.NewIterator for Pebble
+func (p *PebbleDatabase) NewIterator(start, end []byte) (Iterator, error) {
+	return p.db.NewIter(&pebble.IterOptions{
+		LowerBound: start,
+		UpperBound: end,
+	})
+}
+
+// Compact implements Database.Compact for Pebble
+func (p *PebbleDatabase) Compact(start, end []byte) error {
+	return p.db.Compact(context.Background(), start, end, true)
+}
+
+// Flush implements Database.Flush for Pebble. The memtable flush returns
+// before the compactions it triggers finish, so with flushWaitCompaction it
+// also waits for them and reports the settle time.
 func (p *PebbleDatabase) Flush() error {
-	return p.db.Flush()
+	if err := p.db.Flush(); err != nil {
+		return err
+	}
+	if !p.flushWaitCompaction {
+		return nil
+	}
+
+	start := time.Now()
+	settled, err := p.WaitForQuiesce(p.flushSettleTimeout)
+	if err != nil {
+		return err
+	}
+	settle := time.Since(start)
+	total := time.Duration(p.settleNanos.Add(int64(settle)))
+
+	event := log.Info()
+	if !settled {
+		event = log.Warn().Dur("timeout", p.flushSettleTimeout)
+	}
+	event.
+		Float64("settle_ms", durationMs(settle)).
+		Float64("total_settle_ms", durationMs(total)).
+		Bool("settled", settled).
+		Msg("Flush waited for compactions to settle")
+	return nil
+}
+
+// quiescePollInterval is how often WaitForQuiesce samples the compaction metrics
+const quiescePollInterval = 100 * time.Millisecond
+
+// quiesceStablePolls is how many consecutive polls the compaction count and L0
+// file count must stay unchanged before WaitForQuiesce considers the LSM settled
+const quiesceStablePolls = 2
+
+// WaitForQuiesce implements Quiescer by polling the metrics until no compaction
+// is running, no compaction debt remains and the compaction count and L0 file
+// count have stopped changing
+func (p *PebbleDatabase) WaitForQuiesce(timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	var lastCompactions, lastL0Files int64 = -1, -1
+	stable := 0
+	for {
+		m := p.db.Metrics()
+		compactions, l0Files := m.Compact.Count, m.Levels[0].TablesCount
+		if compactions == lastCompactions && l0Files == lastL0Files {
+			stable++
+		} else {
+			stable = 0
+		}
+		lastCompactions, lastL0Files = compactions, l0Files
+
+		if m.Compact.NumInProgress == 0 && m.Compact.EstimatedDebt == 0 && stable >= quiesceStablePolls {
+			return true, nil
+		}
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+		time.Sleep(quiescePollInterval)
+	}
 }
 
 // Close implements Database.Close for Pebble
@@ -85,6 +340,67 @@ func (p *PebbleDatabase) Close() error {
 	return err
 }
 
+// Capabilities implements Database.Capabilities for Pebble
+func (p *PebbleDatabase) Capabilities() DatabaseCapabilities {
+	return DatabaseCapabilities{SupportsBatch: true, SupportsSnapshot: true, SupportsIterator: true, SupportsDelete: true}
+}
+
+// NewSnapshot implements Snapshotter for Pebble. Every open snapshot sits in
+// the DB's snapshot list, which compactions consult to keep the versions it
+// can still see.
+func (p *PebbleDatabase) NewSnapshot() (Snapshot, error) {
+	return &pebbleSnapshot{snap: p.db.NewSnapshot()}, nil
+}
+
+// pebbleSnapshot adapts a pebble.Snapshot to the Snapshot interface
+type pebbleSnapshot struct {
+	snap *pebble.Snapshot
+}
+
+func (s *pebbleSnapshot) Get(key []byte) ([]byte, io.Closer, error) {
+	value, closer, err := s.snap.Get(key)
+	if err == pebble.ErrNotFound {
+		return nil, nil, ErrKeyNotFound
+	}
+	return value, closer, err
+}
+
+func (s *pebbleSnapshot) Close() error {
+	return s.snap.Close()
+}
+
+// pebbleBatch applies its writes with DB.Apply, the way geth and erigon
+// persist a block
+type pebbleBatch struct {
+	db    *PebbleDatabase
+	batch *pebble.Batch
+}
+
+// NewBatch implements Batcher for Pebble
+func (p *PebbleDatabase) NewBatch() Batch {
+	return &pebbleBatch{db: p, batch: p.db.NewBatch()}
+}
+
+func (b *pebbleBatch) Set(key, value []byte) error {
+	return b.batch.Set(key, value, nil)
+}
+
+func (b *pebbleBatch) Count() int {
+	return int(b.batch.Count())
+}
+
+func (b *pebbleBatch) Size() int {
+	return b.batch.Len()
+}
+
+func (b *pebbleBatch) Apply() error {
+	return b.db.db.Apply(b.batch, b.db.writeOpts)
+}
+
+func (b *pebbleBatch) Close() error {
+	return b.batch.Close()
+}
+
 // GetMetrics implements Database.GetMetrics for Pebble
 func (p *PebbleDatabase) GetMetrics() DatabaseMetrics {
 	metrics := DatabaseMetrics{
@@ -103,7 +419,28 @@ func (p *PebbleDatabase) GetMetrics() DatabaseMetrics {
 	metrics.BytesRead = 0  // Will need to calculate from available metrics
 	metrics.BytesWritten = 0  // Will need to calculate from available metrics  
 	metrics.CompactionOps = pebbleMetrics.Compact.Count
+	metrics.L0FileCount = pebbleMetrics.Levels[0].TablesCount
+	metrics.DiskUsage = pebbleMetrics.DiskSpaceUsage()
+	metrics.DataSize = uint64(pebbleMetrics.Total().TablesSize)
+	
+	// WAL metrics
+	metrics.WALSize = pebbleMetrics.WAL.Size
+	metrics.WALPhysicalSize = pebbleMetrics.WAL.PhysicalSize
+	metrics.WALBytesIn = pebbleMetrics.WAL.BytesIn
+	metrics.WALBytesWritten = pebbleMetrics.WAL.BytesWritten
+	if fsync := pebbleMetrics.LogWriter.FsyncLatency; fsync != nil {
+		var m dto.Metric
+		if err := fsync.Write(&m); err == nil && m.Histogram != nil {
+			metrics.WALFsyncCount = m.Histogram.GetSampleCount()
+			if metrics.WALFsyncCount > 0 {
+				// Pebble observes fsync latencies in nanoseconds
+				metrics.WALFsyncLatency = time.Duration(m.Histogram.GetSampleSum() / float64(metrics.WALFsyncCount))
+			}
+		}
+	}
 	
+	metrics.WriteStallCount, metrics.WriteStallDuration = p.stalls.total()
+
 	// Cache metrics (if cache is enabled)
 	if p.cache != nil {
 		cacheMetrics := p.cache.Metrics()
@@ -0,0 +1,272 @@
+package benchmark
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/rs/zerolog/log"
+)
+
+// PhaseResult holds the measurements of one timed phase. Phases fill it in
+// and leave reporting to the caller.
+type PhaseResult struct {
+	Phase      string
+	Ops        uint64 // operations attempted
+	Successful uint64
+	Failed     uint64 // operations that returned an error other than not found
+	NotFound   uint64 // reads of missing keys
+
+	Elapsed      time.Duration // wall-clock time from the first job to the last completion
+	TotalLatency time.Duration // sum of per-operation latencies
+	P50          time.Duration
+	P95          time.Duration
+	P99          time.Duration
+	Histogram    *hdrhistogram.Histogram
+
+	// Set with Config.MeasureGeneration
+	KeyGenTime   time.Duration
+	ValueGenTime time.Duration
+	ValueGenP99  time.Duration // p99 of generating a single value
+
+	// FirstError is the first operation error the phase saw, nil without failures.
+	// Errors holds up to maxErrorSamples distinct messages with their counts and
+	// OtherErrors counts the failures whose message did not fit in the sample.
+	FirstError  error
+	Errors      []ErrorSample
+	OtherErrors uint64
+
+	// Set with Config.VerifyChecksums, see BenchmarkResult.WriteChecksum
+	Checksum uint64
+
+	// Time spent in the Flush that ends the phase, excluded from Elapsed
+	FlushTime time.Duration
+
+	// Bytes of value data successfully written by a write phase and the
+	// distribution of their sizes
+	ValueBytes uint64
+	ValueSizes *hdrhistogram.Histogram
+
+	// Per key class latencies for workloads implementing KeyClassifier
+	ByClass []*PhaseResult
+}
+
+// newPhaseResult fills the latency fields from a drained collector
+func newPhaseResult(phase string, c *latencyCollector, elapsed time.Duration) *PhaseResult {
+	return &PhaseResult{
+		Phase:        phase,
+		Ops:          uint64(c.hist.TotalCount()),
+		Elapsed:      elapsed,
+		TotalLatency: c.total,
+		P50:          c.percentile(50),
+		P95:          c.percentile(95),
+		P99:          c.percentile(99),
+		Histogram:    c.hist,
+	}
+}
+
+// classCollectors splits the latencies of a phase by key class
+type classCollectors struct {
+	classifier KeyClassifier
+	classes    []string
+	latencies  map[string]chan time.Duration
+	collectors map[string]*latencyCollector
+}
+
+// startClassCollectors starts one latency collector per key class, returning
+// nil when the workload does not classify its keys
+func startClassCollectors(phase string, workload Workload, depth int) *classCollectors {
+	classifier, ok := workload.(KeyClassifier)
+	if !ok {
+		return nil
+	}
+
+	c := &classCollectors{
+		classifier: classifier,
+		classes:    classifier.KeyClasses(),
+		latencies:  make(map[string]chan time.Duration),
+		collectors: make(map[string]*latencyCollector),
+	}
+	for _, class := range c.classes {
+		ch := make(chan time.Duration, depth)
+		c.latencies[class] = ch
+		c.collectors[class] = startLatencyCollector(phase+"/"+class, ch)
+	}
+	return c
+}
+
+// record adds the latency of an operation on key to its class
+func (c *classCollectors) record(key []byte, latency time.Duration) {
+	if c == nil {
+		return
+	}
+	if ch, ok := c.latencies[c.classifier.KeyClass(key)]; ok {
+		ch <- latency
+	}
+}
+
+// results drains the collectors and returns one result per class that saw operations
+func (c *classCollectors) results(elapsed time.Duration) []*PhaseResult {
+	if c == nil {
+		return nil
+	}
+
+	var results []*PhaseResult
+	for _, class := range c.classes {
+		close(c.latencies[class])
+		collector := c.collectors[class]
+		collector.wait()
+		if collector.hist.TotalCount() == 0 {
+			continue
+		}
+		results = append(results, newPhaseResult(class, collector, elapsed))
+	}
+	return results
+}
+
+// OpsPerSec returns throughput over the wall-clock duration of the phase
+func (r *PhaseResult) OpsPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Ops) / r.Elapsed.Seconds()
+}
+
+// AvgLatency returns the mean per-operation latency
+func (r *PhaseResult) AvgLatency() time.Duration {
+	if r.Ops == 0 {
+		return 0
+	}
+	return r.TotalLatency / time.Duration(r.Ops)
+}
+
+// ErrorRate returns the fraction of operations that failed
+func (r *PhaseResult) ErrorRate() float64 {
+	if r.Ops == 0 {
+		return 0
+	}
+	return float64(r.Failed) / float64(r.Ops)
+}
+
+// MeanValueSize returns the mean size of the values a write phase wrote
+func (r *PhaseResult) MeanValueSize() float64 {
+	if r.Successful == 0 {
+		return 0
+	}
+	return float64(r.ValueBytes) / float64(r.Successful)
+}
+
+// checkPhaseErrors logs the sampled errors of a phase with failures and, with
+// cfg.FailOnError, fails the run when the error rate exceeds cfg.MaxErrorRate
+func checkPhaseErrors(cfg Config, r *PhaseResult) error {
+	if r.Failed == 0 {
+		return nil
+	}
+
+	log.Error().
+		Str("phase", r.Phase).
+		Uint64("failed", r.Failed).
+		Float64("error_rate", r.ErrorRate()).
+		Int("distinct_errors", len(r.Errors)).
+		Uint64("unsampled_errors", r.OtherErrors).
+		Msg("Operations failed")
+	for _, sample := range r.Errors {
+		log.Error().
+			Str("phase", r.Phase).
+			Str("error", sample.Message).
+			Uint64("count", sample.Count).
+			Msg("Operation error")
+	}
+
+	if cfg.FailOnError && r.ErrorRate() > cfg.MaxErrorRate {
+		return fmt.Errorf("%s phase error rate %.4f exceeds --max-error-rate %.4f: %w",
+			r.Phase, r.ErrorRate(), cfg.MaxErrorRate, r.FirstError)
+	}
+	return nil
+}
+
+// checkLatencyBudget compares a phase's p99 latency against cfg.P99BudgetMs and
+// fails the run when it is exceeded
+func checkLatencyBudget(cfg Config, r *PhaseResult) error {
+	if cfg.P99BudgetMs <= 0 {
+		return nil
+	}
+
+	p99 := durationMs(r.P99)
+	if p99 > cfg.P99BudgetMs {
+		log.Error().
+			Str("phase", r.Phase).
+			Float64("p99_latency_ms", p99).
+			Float64("p99_budget_ms", cfg.P99BudgetMs).
+			Msg("p99 latency budget exceeded")
+		return fmt.Errorf("%s phase p99 latency %gms exceeds --p99-budget-ms %gms", r.Phase, p99, cfg.P99BudgetMs)
+	}
+
+	log.Info().
+		Str("phase", r.Phase).
+		Float64("p99_latency_ms", p99).
+		Float64("p99_budget_ms", cfg.P99BudgetMs).
+		Msg("p99 latency within budget")
+	return nil
+}
+
+// durationMs converts d to fractional milliseconds for logging
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// logWriteResult reports a write phase
+func logWriteResult(r *PhaseResult) {
+	log.Info().
+		Dur("total_elapsed", r.Elapsed).
+		Dur("total_latency", r.TotalLatency).
+		Uint64("failed_writes", r.Failed).
+		Uint64("successful_writes", r.Successful).
+		Float64("ops_per_sec", r.OpsPerSec()).
+		Float64("avg_latency_ms", durationMs(r.AvgLatency())).
+		Float64("p50_latency_ms", durationMs(r.P50)).
+		Float64("p95_latency_ms", durationMs(r.P95)).
+		Float64("p99_latency_ms", durationMs(r.P99)).
+		Float64("flush_ms", durationMs(r.FlushTime)).
+		Float64("mean_value_size", r.MeanValueSize()).
+		Msg("Write benchmark complete")
+
+	if r.ValueSizes != nil && r.ValueSizes.TotalCount() > 0 {
+		log.Info().
+			Int64("min", r.ValueSizes.Min()).
+			Int64("p50", r.ValueSizes.ValueAtQuantile(50)).
+			Int64("p95", r.ValueSizes.ValueAtQuantile(95)).
+			Int64("p99", r.ValueSizes.ValueAtQuantile(99)).
+			Int64("max", r.ValueSizes.Max()).
+			Float64("mean", r.MeanValueSize()).
+			Msg("Realized value sizes")
+	}
+}
+
+// logReadResult reports a read phase
+func logReadResult(r *PhaseResult) {
+	log.Info().
+		Float64("read_ops_per_sec", r.OpsPerSec()).
+		Float64("read_avg_latency_ms", durationMs(r.AvgLatency())).
+		Float64("read_p50_latency_ms", durationMs(r.P50)).
+		Float64("read_p95_latency_ms", durationMs(r.P95)).
+		Float64("read_p99_latency_ms", durationMs(r.P99)).
+		Uint64("not_found", r.NotFound).
+		Uint64("failed_reads", r.Failed).
+		Uint64("successful_reads", r.Successful).
+		Uint64("total_reads", r.Ops).
+		Dur("read_total_elapsed", r.Elapsed).
+		Dur("read_total_latency", r.TotalLatency).
+		Msg("Read benchmark complete")
+
+	for _, c := range r.ByClass {
+		log.Info().
+			Str("key_class", c.Phase).
+			Uint64("reads", c.Ops).
+			Float64("read_avg_latency_ms", durationMs(c.AvgLatency())).
+			Float64("read_p50_latency_ms", durationMs(c.P50)).
+			Float64("read_p95_latency_ms", durationMs(c.P95)).
+			Float64("read_p99_latency_ms", durationMs(c.P99)).
+			Msg("Read latency by key class")
+	}
+}
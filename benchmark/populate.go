@@ -0,0 +1,44 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"iter"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keyForIndex returns the 32-byte hashed key at index i of the keyspace seeded
+// with seed, so any populated key can be addressed by its index alone
+func keyForIndex(seed int64, i uint64) []byte {
+	var raw [16]byte
+	binary.BigEndian.PutUint64(raw[:8], uint64(seed))
+	binary.BigEndian.PutUint64(raw[8:], i)
+	return crypto.Keccak256(raw[:])
+}
+
+// populatedKeys yields the n keys written by --populate in index order
+func populatedKeys(seed int64, n int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(keyForIndex(seed, uint64(i))) {
+				return
+			}
+		}
+	}
+}
+
+// restrictToPopulated maps every workload key onto one of the n populated keys
+// by hashing it to an index. A key the workload repeats always maps to the same
+// index, so the workload's access skew carries over while every read hits.
+func restrictToPopulated(keys iter.Seq[[]byte], seed int64, n int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for key := range keys {
+			h := fnv.New64a()
+			h.Write(key)
+			if !yield(keyForIndex(seed, h.Sum64()%uint64(n))) {
+				return
+			}
+		}
+	}
+}
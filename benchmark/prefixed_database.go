@@ -0,0 +1,136 @@
+package benchmark
+
+import (
+	"io"
+	"time"
+)
+
+// PrefixedDatabase wraps a Database and confines every operation to the keys
+// under a namespace prefix, so a benchmark can run inside a database holding
+// other data without touching it. Keys are prefixed on the way in and stripped
+// from iterator keys on the way out, so workloads and the runner only ever see
+// their own keys. Iterators and compactions with an open bound stop at the
+// edge of the namespace.
+type PrefixedDatabase struct {
+	Database
+	prefix []byte
+}
+
+// NewPrefixedDatabase wraps db, storing every key under prefix
+func NewPrefixedDatabase(db Database, prefix []byte) *PrefixedDatabase {
+	return &PrefixedDatabase{Database: db, prefix: prefix}
+}
+
+// key returns key under the namespace prefix
+func (p *PrefixedDatabase) key(key []byte) []byte {
+	out := make([]byte, 0, len(p.prefix)+len(key))
+	return append(append(out, p.prefix...), key...)
+}
+
+// bounds returns [start, end) within the namespace, where nil bounds stand for
+// its first and last key
+func (p *PrefixedDatabase) bounds(start, end []byte) ([]byte, []byte) {
+	lower := p.key(start)
+	upper := prefixUpperBound(p.prefix)
+	if end != nil {
+		upper = p.key(end)
+	}
+	return lower, upper
+}
+
+func (p *PrefixedDatabase) Set(key, value []byte) error {
+	return p.Database.Set(p.key(key), value)
+}
+
+func (p *PrefixedDatabase) Get(key []byte) ([]byte, io.Closer, error) {
+	return p.Database.Get(p.key(key))
+}
+
+func (p *PrefixedDatabase) Delete(key []byte) error {
+	return p.Database.Delete(p.key(key))
+}
+
+// NewIterator walks [start, end) within the namespace, yielding keys without
+// the prefix
+func (p *PrefixedDatabase) NewIterator(start, end []byte) (Iterator, error) {
+	lower, upper := p.bounds(start, end)
+	it, err := p.Database.NewIterator(lower, upper)
+	if err != nil {
+		return nil, err
+	}
+	return &prefixedIterator{Iterator: it, strip: len(p.prefix)}, nil
+}
+
+// Compact compacts [start, end) within the namespace
+func (p *PrefixedDatabase) Compact(start, end []byte) error {
+	lower, upper := p.bounds(start, end)
+	return p.Database.Compact(lower, upper)
+}
+
+// NewBatch returns a batch storing its keys under the prefix. The wrapped
+// database must be a Batcher, which Capabilities.SupportsBatch promises.
+func (p *PrefixedDatabase) NewBatch() Batch {
+	return &prefixedBatch{Batch: p.Database.(Batcher).NewBatch(), db: p}
+}
+
+// NewSnapshot returns a snapshot reading keys under the prefix. The wrapped
+// database must be a Snapshotter, which Capabilities.SupportsSnapshot promises.
+func (p *PrefixedDatabase) NewSnapshot() (Snapshot, error) {
+	snap, err := p.Database.(Snapshotter).NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &prefixedSnapshot{Snapshot: snap, db: p}, nil
+}
+
+// WaitForQuiesce forwards to the wrapped database when it is a Quiescer
+func (p *PrefixedDatabase) WaitForQuiesce(timeout time.Duration) (bool, error) {
+	if q, ok := p.Database.(Quiescer); ok {
+		return q.WaitForQuiesce(timeout)
+	}
+	return true, nil
+}
+
+// ValueCopyStats forwards to the wrapped database when it is a ValueCopyReporter
+func (p *PrefixedDatabase) ValueCopyStats() (uint64, time.Duration) {
+	if r, ok := p.Database.(ValueCopyReporter); ok {
+		return r.ValueCopyStats()
+	}
+	return 0, 0
+}
+
+// EffectiveConfig forwards to the wrapped database
+func (p *PrefixedDatabase) EffectiveConfig() map[string]interface{} {
+	return effectiveConfig(p.Database)
+}
+
+// prefixedIterator strips the namespace prefix from the keys of a wrapped
+// iterator
+type prefixedIterator struct {
+	Iterator
+	strip int
+}
+
+func (it *prefixedIterator) Key() []byte {
+	return it.Iterator.Key()[it.strip:]
+}
+
+// prefixedBatch stores the keys of a wrapped batch under the prefix
+type prefixedBatch struct {
+	Batch
+	db *PrefixedDatabase
+}
+
+func (b *prefixedBatch) Set(key, value []byte) error {
+	return b.Batch.Set(b.db.key(key), value)
+}
+
+// prefixedSnapshot reads the keys of a wrapped snapshot under the prefix
+type prefixedSnapshot struct {
+	Snapshot
+	db *PrefixedDatabase
+}
+
+func (s *prefixedSnapshot) Get(key []byte) ([]byte, io.Closer, error) {
+	return s.Snapshot.Get(s.db.key(key))
+}
@@ -0,0 +1,57 @@
+package benchmark
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrefixedDatabaseStaysInNamespace(t *testing.T) {
+	raw, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer raw.Close()
+
+	// Neighbours on either side of the namespace must stay out of reach
+	for _, key := range []string{"a", "bench.", "bench0", "c"} {
+		if err := raw.Set([]byte(key), []byte("other")); err != nil {
+			t.Fatalf("Set %q: %v", key, err)
+		}
+	}
+
+	db := NewPrefixedDatabase(raw, []byte("bench/"))
+	for _, key := range []string{"x", "y"} {
+		if err := db.Set([]byte(key), []byte("mine")); err != nil {
+			t.Fatalf("Set %q: %v", key, err)
+		}
+	}
+
+	if _, closer, err := raw.Get([]byte("bench/x")); err != nil {
+		t.Errorf("prefixed key not stored under the namespace: %v", err)
+	} else if closer != nil {
+		closer.Close()
+	}
+	if _, _, err := db.Get([]byte("a")); !IsKeyNotFound(err) {
+		t.Errorf("Get of a key outside the namespace returned %v, want not found", err)
+	}
+
+	it, err := db.NewIterator(nil, nil)
+	if err != nil {
+		t.Fatalf("NewIterator: %v", err)
+	}
+	var keys [][]byte
+	for valid := it.First(); valid; valid = it.Next() {
+		keys = append(keys, bytes.Clone(it.Key()))
+	}
+	it.Close()
+	if len(keys) != 2 || string(keys[0]) != "x" || string(keys[1]) != "y" {
+		t.Errorf("iterated keys %q, want [x y]", keys)
+	}
+
+	if err := db.Delete([]byte("x")); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, _, err := raw.Get([]byte("bench/x")); !IsKeyNotFound(err) {
+		t.Errorf("Delete left the prefixed key behind: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package benchmark
+
+import (
+	"iter"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// pregenerateMaxPairs is the largest key count for which every key/value
+	// pair is materialized in memory before the write phase
+	pregenerateMaxPairs = 4_000_000
+
+	// pregenerateRingSize is the number of values kept in the ring buffer used
+	// when the key count is too large to materialize every pair
+	pregenerateRingSize = 1 << 16
+)
+
+// writeJob is a unit of work handed to write workers.
+// A nil value means the worker generates the value itself.
+type writeJob struct {
+	key       []byte
+	value     []byte
+	endsBlock bool // the key is the last of a workload block, see BlockBoundaryReporter
+	delete    bool // the workload deletes the key instead of writing it, see DeletionReporter
+
+	block     *sync.WaitGroup // the block the job belongs to under --block-time
+	scheduled time.Time       // start the rate limiter scheduled, zero without one
+}
+
+// done marks the job completed for the block pacer
+func (j writeJob) done() {
+	if j.block != nil {
+		j.block.Done()
+	}
+}
+
+// pregenerateJobs generates values before the write phase starts so the timed
+// loop only measures database writes. Up to pregenerateMaxPairs pairs are fully
+// materialized; beyond that a ring buffer of values is reused across keys.
+func pregenerateJobs(cfg Config, pending iter.Seq[writeJob], workload Workload) iter.Seq[writeJob] {
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	shape := newValueShape(cfg)
+
+	if cfg.KeyCount <= pregenerateMaxPairs {
+		jobs := make([]writeJob, 0, cfg.KeyCount)
+		for job := range pending {
+			job.value = generateWorkloadValue(rng, workload, job.key, shape)
+			jobs = append(jobs, job)
+		}
+		logPregeneration("full", len(jobs), time.Since(start), &before)
+		return slices.Values(jobs)
+	}
+
+	next, stop := iter.Pull(pending)
+	ring := make([]writeJob, 0, pregenerateRingSize)
+	for len(ring) < pregenerateRingSize {
+		job, ok := next()
+		if !ok {
+			break
+		}
+		job.value = generateWorkloadValue(rng, workload, job.key, shape)
+		ring = append(ring, job)
+	}
+	logPregeneration("ring", len(ring), time.Since(start), &before)
+
+	return func(yield func(writeJob) bool) {
+		defer stop()
+		for _, job := range ring {
+			if !yield(job) {
+				return
+			}
+		}
+		for i := 0; ; i++ {
+			job, ok := next()
+			if !ok {
+				return
+			}
+			job.value = ring[i%len(ring)].value
+			if !yield(job) {
+				return
+			}
+		}
+	}
+}
+
+// logPregeneration reports how many values were pregenerated and the heap they occupy
+func logPregeneration(mode string, pairs int, elapsed time.Duration, before *runtime.MemStats) {
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	var heapUsed uint64
+	if after.HeapAlloc > before.HeapAlloc {
+		heapUsed = after.HeapAlloc - before.HeapAlloc
+	}
+
+	log.Info().
+		Str("mode", mode).
+		Int("pairs", pairs).
+		Dur("elapsed", elapsed).
+		Uint64("heap_bytes", heapUsed).
+		Uint64("heap_alloc_bytes", after.HeapAlloc).
+		Msg("Pregenerated values")
+}
@@ -1,3 +1,5 @@
+//go:build qmdb
+
 package benchmark
 
 /*
@@ -21,6 +23,7 @@ type QMDBDatabase struct {
 	readOnly bool
 	closed   bool
 	handle   *C.QMDBHandle // QMDB database handle
+	config   QMDBConfig
 }
 
 // NewQMDBDatabase creates a new QMDB database instance
@@ -44,6 +47,7 @@ func NewQMDBDatabase(cfg DatabaseConfig) (Database, error) {
 		readOnly: cfg.ReadOnly,
 		closed:   false,
 		handle:   handle,
+		config:   cfg.QMDBConfig,
 	}
 
 	log.Info().
@@ -54,6 +58,15 @@ func NewQMDBDatabase(cfg DatabaseConfig) (Database, error) {
 	return db, nil
 }
 
+// EffectiveConfig implements ConfigReporter. QMDB is opened with its built-in
+// defaults, so only the settings the benchmark passes are reported.
+func (q *QMDBDatabase) EffectiveConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"library_path": q.config.LibraryPath,
+		"read_only":    q.readOnly,
+	}
+}
+
 // Set implements Database.Set for QMDB
 func (q *QMDBDatabase) Set(key, value []byte) error {
 	if q.closed {
@@ -126,6 +139,24 @@ func (q *QMDBDatabase) Get(key []byte) ([]byte, io.Closer, error) {
 	}
 }
 
+// Delete implements Database.Delete for QMDB
+// The QMDB FFI does not expose deletes yet
+func (q *QMDBDatabase) Delete(key []byte) error {
+	return fmt.Errorf("QMDB delete: %w", ErrInvalidOperation)
+}
+
+// NewIterator implements Database.NewIterator for QMDB
+// The QMDB FFI does not expose iteration yet
+func (q *QMDBDatabase) NewIterator(start, end []byte) (Iterator, error) {
+	return nil, fmt.Errorf("QMDB iterator: %w", ErrInvalidOperation)
+}
+
+// Compact implements Database.Compact for QMDB
+// The QMDB FFI does not expose compaction yet
+func (q *QMDBDatabase) Compact(start, end []byte) error {
+	return fmt.Errorf("QMDB compact: %w", ErrInvalidOperation)
+}
+
 // Flush implements Database.Flush for QMDB  
 func (q *QMDBDatabase) Flush() error {
 	if q.closed {
@@ -158,6 +189,12 @@ func (q *QMDBDatabase) Close() error {
 	return nil
 }
 
+// Capabilities implements Database.Capabilities for QMDB, which only supports
+// point reads and writes
+func (q *QMDBDatabase) Capabilities() DatabaseCapabilities {
+	return DatabaseCapabilities{}
+}
+
 // GetMetrics implements Database.GetMetrics for QMDB
 func (q *QMDBDatabase) GetMetrics() DatabaseMetrics {
 	metrics := DatabaseMetrics{
@@ -0,0 +1,11 @@
+//go:build !qmdb
+
+package benchmark
+
+import "fmt"
+
+// NewQMDBDatabase reports that the QMDB backend is unavailable. QMDB links
+// against the native libqmdb, so it is only compiled with the qmdb build tag.
+func NewQMDBDatabase(cfg DatabaseConfig) (Database, error) {
+	return nil, fmt.Errorf("%w: QMDB backend not built; rebuild with -tags qmdb", ErrBackendNotFound)
+}
@@ -0,0 +1,346 @@
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/rs/zerolog/log"
+)
+
+// ScanDirection selects which way range scans iterate
+type ScanDirection string
+
+const (
+	ScanForward ScanDirection = "forward"
+	ScanReverse ScanDirection = "reverse"
+	ScanBoth    ScanDirection = "both" // run every scan in both directions and compare
+)
+
+// directions expands d into the directions to scan, defaulting to forward
+func (d ScanDirection) directions() ([]ScanDirection, error) {
+	switch d {
+	case "", ScanForward:
+		return []ScanDirection{ScanForward}, nil
+	case ScanReverse:
+		return []ScanDirection{ScanReverse}, nil
+	case ScanBoth:
+		return []ScanDirection{ScanForward, ScanReverse}, nil
+	default:
+		return nil, fmt.Errorf("invalid scan direction %q: expected forward, reverse or both", d)
+	}
+}
+
+// prefixUpperBound returns the smallest key greater than every key starting with
+// prefix, or nil when no such key exists (prefix is empty or all 0xFF)
+func prefixUpperBound(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xFF {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// validRangeBounds reports whether [start, end) can contain keys under the
+// ordering compare. A nil end means the range is unbounded above.
+func validRangeBounds(compare func(a, b []byte) int, start, end []byte) bool {
+	return end == nil || compare(start, end) < 0
+}
+
+// rangeItemsMax bounds the items-per-scan histogram; longer scans are
+// recorded at the maximum
+const rangeItemsMax = 1 << 32
+
+// rangeQuery is a generated range query with well-formed bounds
+type rangeQuery struct {
+	start, end []byte
+	limit      int
+}
+
+// runRangePhase executes cfg.RangeQueries range queries generated by the workload
+// in each configured scan direction, each stopping after the limit the workload
+// requested or cfg.RangeLimit when set. Queries whose bounds are malformed are
+// skipped and counted rather than scanned.
+func runRangePhase(db Database, cfg Config, workload Workload) ([]*hdrhistogram.Histogram, error) {
+	if !workload.SupportsRangeQueries() {
+		log.Warn().Str("workload", workload.Name()).Msg("Workload does not support range queries, skipping range phase")
+		return nil, nil
+	}
+
+	directions, err := cfg.ScanDirection.directions()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Int("queries", cfg.RangeQueries).Str("direction", string(cfg.ScanDirection)).Msg("Beginning range query loop")
+
+	// Generate the queries up front so every direction scans the same ranges
+	compare := keyCompare(cfg.PebbleComparer)
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	queries := make([]rangeQuery, 0, cfg.RangeQueries)
+	var malformed uint64
+	for i := 0; i < cfg.RangeQueries; i++ {
+		start, end, limit := workload.GenerateRangeQuery(rng)
+		if !validRangeBounds(compare, start, end) {
+			malformed++
+			log.Debug().Hex("start", start).Hex("end", end).Msg("Skipping malformed range query")
+			continue
+		}
+		if cfg.RangeLimit > 0 {
+			limit = cfg.RangeLimit
+		}
+		queries = append(queries, rangeQuery{start: start, end: end, limit: limit})
+	}
+
+	if malformed > 0 {
+		log.Warn().
+			Uint64("malformed_ranges", malformed).
+			Msg("Workload generated range queries with start >= end; they were skipped")
+	}
+
+	var histograms []*hdrhistogram.Histogram
+	itemsPerSec := make(map[ScanDirection]float64)
+	for _, direction := range directions {
+		hist, rate, err := runRangeScans(db, cfg, queries, direction)
+		if err != nil {
+			return nil, err
+		}
+		histograms = append(histograms, hist)
+		itemsPerSec[direction] = rate
+	}
+
+	if len(directions) > 1 && itemsPerSec[ScanForward] > 0 {
+		log.Info().
+			Float64("forward_items_per_sec", itemsPerSec[ScanForward]).
+			Float64("reverse_items_per_sec", itemsPerSec[ScanReverse]).
+			Float64("reverse_to_forward_ratio", itemsPerSec[ScanReverse]/itemsPerSec[ScanForward]).
+			Msg("Forward vs reverse scan throughput")
+	}
+
+	return histograms, nil
+}
+
+// runRangeScans runs every query in one direction and returns its latency
+// histogram and the items/sec scanned. It reports how the items returned
+// compare to the limits requested: scans that stopped at their limit, and
+// short scans whose range ran out of keys first.
+func runRangeScans(db Database, cfg Config, queries []rangeQuery, direction ScanDirection) (*hdrhistogram.Histogram, float64, error) {
+	latencies := make(chan time.Duration, queueDepth(cfg))
+	collector := startLatencyCollector("range_"+string(direction), latencies)
+	seekLatencies := make(chan time.Duration, queueDepth(cfg))
+	seekCollector := startLatencyCollector("range_seek_"+string(direction), seekLatencies)
+
+	itemsPerScan := hdrhistogram.New(0, rangeItemsMax, histogramSigFigs)
+	var scans, empty, items, stepped uint64
+	var limited, atLimit, short, requested uint64
+	var scanTime time.Duration
+	var scanErr error
+	for _, q := range queries {
+		scanStart := time.Now()
+		timing, err := scanRange(db, q.start, q.end, q.limit, direction == ScanReverse)
+		latencies <- time.Since(scanStart)
+		if err != nil {
+			scanErr = err
+			break
+		}
+		seekLatencies <- timing.seek
+
+		if q.limit > 0 && timing.items > q.limit {
+			scanErr = fmt.Errorf("scan returned %d items past its limit of %d", timing.items, q.limit)
+			break
+		}
+		if q.limit > 0 {
+			limited++
+			requested += uint64(q.limit)
+			if timing.items == q.limit {
+				atLimit++
+			} else {
+				short++
+			}
+		}
+		itemsPerScan.RecordValue(int64(minInt(timing.items, rangeItemsMax)))
+
+		scans++
+		items += uint64(timing.items)
+		stepped += uint64(timing.stepped())
+		scanTime += timing.scan
+		if timing.items == 0 {
+			empty++
+		}
+	}
+	close(latencies)
+	collector.wait()
+	close(seekLatencies)
+	seekCollector.wait()
+
+	if scanErr != nil {
+		return nil, 0, fmt.Errorf("%s range scan failed: %w", direction, scanErr)
+	}
+
+	avg, perScan := float64(0), float64(0)
+	if scans > 0 {
+		avg = float64(collector.total.Microseconds()) / 1000.0 / float64(scans)
+		perScan = float64(items) / float64(scans)
+	}
+	rate := float64(0)
+	if collector.total > 0 {
+		rate = float64(items) / collector.total.Seconds()
+	}
+	requestedPerScan := float64(0)
+	if limited > 0 {
+		requestedPerScan = float64(requested) / float64(limited)
+	}
+	seekAvg, scanRate := float64(0), float64(0)
+	if scans > 0 {
+		seekAvg = durationMs(seekCollector.total) / float64(scans)
+	}
+	if scanTime > 0 {
+		scanRate = float64(stepped) / scanTime.Seconds()
+	}
+
+	log.Info().
+		Str("direction", string(direction)).
+		Uint64("scans", scans).
+		Uint64("empty_scans", empty).
+		Uint64("items_returned", items).
+		Float64("items_per_scan", perScan).
+		Int64("items_per_scan_p50", itemsPerScan.ValueAtQuantile(50)).
+		Int64("items_per_scan_p90", itemsPerScan.ValueAtQuantile(90)).
+		Int64("items_per_scan_p99", itemsPerScan.ValueAtQuantile(99)).
+		Int64("items_per_scan_max", itemsPerScan.Max()).
+		Uint64("limited_scans", limited).
+		Float64("requested_items_per_scan", requestedPerScan).
+		Uint64("scans_at_limit", atLimit).
+		Uint64("short_scans", short).
+		Float64("items_per_sec", rate).
+		Float64("range_avg_latency_ms", avg).
+		Float64("range_p50_latency_ms", collector.percentileMs(50)).
+		Float64("range_p99_latency_ms", collector.percentileMs(99)).
+		Dur("range_total_elapsed", collector.total).
+		Float64("seek_avg_latency_ms", seekAvg).
+		Float64("seek_p50_latency_ms", seekCollector.percentileMs(50)).
+		Float64("seek_p99_latency_ms", seekCollector.percentileMs(99)).
+		Float64("scan_items_per_sec", scanRate).
+		Msg("Range benchmark complete")
+
+	return collector.hist, rate, nil
+}
+
+// scanTiming splits a range scan into positioning the iterator at the start
+// of the range and stepping through the rest of it
+type scanTiming struct {
+	items int           // items visited
+	seek  time.Duration // First, or Last for reverse scans
+	scan  time.Duration // every Next or Prev after the seek
+}
+
+// stepped returns the items reached by stepping, every one but the item the
+// seek positioned at
+func (t scanTiming) stepped() int {
+	return max(t.items-1, 0)
+}
+
+// scanRange iterates [start, end) and returns the number of items visited
+// with the time to seek and to scan, stopping after limit items when limit
+// is positive. Reverse scans start at the last key of the range.
+func scanRange(db Database, start, end []byte, limit int, reverse bool) (scanTiming, error) {
+	var timing scanTiming
+	it, err := db.NewIterator(start, end)
+	if err != nil {
+		return timing, err
+	}
+
+	first, next := it.First, it.Next
+	if reverse {
+		first, next = it.Last, it.Prev
+	}
+
+	seekStart := time.Now()
+	valid := first()
+	scanStart := time.Now()
+	timing.seek = scanStart.Sub(seekStart)
+	for ; valid; valid = next() {
+		timing.items++
+		if limit > 0 && timing.items >= limit {
+			break
+		}
+	}
+	timing.scan = time.Since(scanStart)
+
+	err = it.Error()
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	return timing, err
+}
+
+// ScanConfig defines a single range scan run from the scan subcommand
+type ScanConfig struct {
+	DBPath         string
+	DatabaseType   string
+	BlockCacheSize int64
+	LogFormat      string
+	Start          []byte // inclusive lower bound, nil for the first key
+	End            []byte // exclusive upper bound, nil for past the last key
+	Limit          int    // stop after this many items, <= 0 for no limit
+	Direction      ScanDirection
+	PebbleComparer string // must match the comparer the database was created with
+}
+
+// RunScan opens an existing database read-only and times a scan of [Start, End)
+// in each requested direction
+func RunScan(cfg ScanConfig) error {
+	setupLog(Config{LogFormat: cfg.LogFormat})
+
+	directions, err := cfg.Direction.directions()
+	if err != nil {
+		return err
+	}
+	if !validRangeBounds(keyCompare(cfg.PebbleComparer), cfg.Start, cfg.End) {
+		return fmt.Errorf("invalid scan range: start must be below end")
+	}
+
+	db, err := createDatabase(Config{
+		DBPath:         cfg.DBPath,
+		DatabaseType:   cfg.DatabaseType,
+		BlockCacheSize: cfg.BlockCacheSize,
+		PebbleComparer: cfg.PebbleComparer,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	for _, direction := range directions {
+		scanStart := time.Now()
+		timing, err := scanRange(db, cfg.Start, cfg.End, cfg.Limit, direction == ScanReverse)
+		elapsed := time.Since(scanStart)
+		if err != nil {
+			return fmt.Errorf("%s scan failed: %w", direction, err)
+		}
+
+		rate, scanRate := float64(0), float64(0)
+		if elapsed > 0 {
+			rate = float64(timing.items) / elapsed.Seconds()
+		}
+		if timing.scan > 0 {
+			scanRate = float64(timing.stepped()) / timing.scan.Seconds()
+		}
+
+		log.Info().
+			Str("direction", string(direction)).
+			Int("items", timing.items).
+			Dur("elapsed", elapsed).
+			Float64("items_per_sec", rate).
+			Dur("seek", timing.seek).
+			Float64("scan_items_per_sec", scanRate).
+			Msg("Scan complete")
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package benchmark
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestScanRangeStopsAtLimit(t *testing.T) {
+	db, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer db.Close()
+	for i := 0; i < 100; i++ {
+		if err := db.Set([]byte(fmt.Sprintf("key%03d", i)), []byte("value")); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		limit, want int
+	}{
+		{limit: 10, want: 10},   // stops at the limit
+		{limit: 1, want: 1},     // only the seek
+		{limit: 100, want: 100}, // range exactly fills the limit
+		{limit: 500, want: 100}, // short range returns fewer
+		{limit: 0, want: 100},   // no limit scans the whole range
+	} {
+		for _, reverse := range []bool{false, true} {
+			timing, err := scanRange(db, []byte("key"), []byte("kez"), tc.limit, reverse)
+			if err != nil {
+				t.Fatalf("scanRange(limit %d, reverse %v): %v", tc.limit, reverse, err)
+			}
+			if timing.items != tc.want {
+				t.Errorf("scanRange(limit %d, reverse %v) returned %d items, want %d", tc.limit, reverse, timing.items, tc.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package benchmark
+
+import "time"
+
+const (
+	// rateLimiterMinSleep is the smallest lead worth sleeping for; shorter waits
+	// accumulate until they add up, since sleeps this short are not precise
+	rateLimiterMinSleep = time.Millisecond
+
+	// rateLimiterMaxLag bounds how far behind schedule the limiter catches up,
+	// enough to absorb oversleeping without bursting after a stall
+	rateLimiterMaxLag = 10 * time.Millisecond
+)
+
+// rateLimiter paces a feeder to a fixed number of operations per second. A
+// feeder that falls more than rateLimiterMaxLag behind does not burst to catch
+// up, so a backend that cannot sustain the rate shows up as achieved
+// throughput below the target. Each operation keeps its slot of the unclamped
+// schedule as its start, so the time it waited behind a slow backend counts
+// towards its latency instead of being omitted.
+// A nil *rateLimiter never waits.
+type rateLimiter struct {
+	interval  time.Duration
+	next      time.Time
+	scheduled time.Time // start of the next operation at exactly the target rate
+}
+
+// newRateLimiter returns a limiter for opsPerSec, or nil when opsPerSec <= 0
+func newRateLimiter(opsPerSec float64) *rateLimiter {
+	if opsPerSec <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / opsPerSec)}
+}
+
+// wait blocks until the next operation is due and returns when it was
+// scheduled to start, the zero time for a nil limiter
+func (r *rateLimiter) wait() time.Time {
+	if r == nil {
+		return time.Time{}
+	}
+	now := phaseClock.Now()
+	if r.next.IsZero() {
+		r.next, r.scheduled = now, now
+	}
+	if behind := now.Add(-rateLimiterMaxLag); r.next.Before(behind) {
+		r.next = behind
+	}
+	if lead := r.next.Sub(now); lead >= rateLimiterMinSleep {
+		time.Sleep(lead)
+	}
+	r.next = r.next.Add(r.interval)
+
+	scheduled := r.scheduled
+	r.scheduled = r.scheduled.Add(r.interval)
+	return scheduled
+}
+
+// startedAt returns when an operation issued at issued is timed from: its
+// scheduled start under a rate limit, issued otherwise
+func startedAt(scheduled, issued time.Time) time.Time {
+	if scheduled.IsZero() {
+		return issued
+	}
+	return scheduled
+}
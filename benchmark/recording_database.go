@@ -0,0 +1,360 @@
+package benchmark
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Operation names written to an operation log, one operation per line:
+//
+//	set <key> <value length>
+//	get <key> <value length, -1 when not found>
+//	delete <key>
+//	iterate <start> <end>
+//	compact <start> <end>
+//	flush
+//
+// Keys are hex encoded and "-" stands for a nil range bound.
+const (
+	opSet     = "set"
+	opGet     = "get"
+	opDelete  = "delete"
+	opIterate = "iterate"
+	opCompact = "compact"
+	opFlush   = "flush"
+)
+
+// opEncoder encodes one operation for a RecordingDatabase, returning nil to
+// leave it out. size is the length of the value written or read, -1 when a
+// get failed.
+type opEncoder func(op string, keys [][]byte, size int, err error) []byte
+
+// RecordingDatabase wraps a Database and appends every operation it delegates
+// to an operation log that --replay-ops can run against another database
+type RecordingDatabase struct {
+	Database
+
+	name   string // what the log is called in messages
+	encode opEncoder
+
+	mu  sync.Mutex
+	f   *os.File
+	w   *bufio.Writer
+	err error // first failure writing the log
+	ops uint64
+}
+
+// NewRecordingDatabase wraps db, recording its operations to a new file at path
+func NewRecordingDatabase(db Database, path string) (*RecordingDatabase, error) {
+	return newRecordingDatabase(db, path, "operation log", nil, encodeTextOp)
+}
+
+// newRecordingDatabase wraps db, writing header and then every operation
+// encode keeps to a new file at path
+func newRecordingDatabase(db Database, path, name string, header []byte, encode opEncoder) (*RecordingDatabase, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	w := bufio.NewWriterSize(f, readerBufferSize)
+	if _, err := w.Write(header); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return &RecordingDatabase{Database: db, name: name, encode: encode, f: f, w: w}, nil
+}
+
+// record appends one operation to the log
+func (r *RecordingDatabase) record(op string, keys [][]byte, size int, err error) {
+	line := r.encode(op, keys, size, err)
+	if line == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if _, err := r.w.Write(line); err != nil {
+		r.err = fmt.Errorf("failed to write %s: %w", r.name, err)
+		return
+	}
+	r.ops++
+}
+
+// encodeTextOp writes an operation as a line of the operation log
+func encodeTextOp(op string, keys [][]byte, size int, _ error) []byte {
+	fields := []string{op}
+	for _, key := range keys {
+		fields = append(fields, encodeOpKey(key))
+	}
+	if op == opSet || op == opGet {
+		fields = append(fields, strconv.Itoa(size))
+	}
+	return []byte(strings.Join(fields, " ") + "\n")
+}
+
+// encodeOpKey hex encodes a key, writing a nil range bound as "-"
+func encodeOpKey(key []byte) string {
+	if key == nil {
+		return "-"
+	}
+	return hex.EncodeToString(key)
+}
+
+// decodeOpKey reverses encodeOpKey
+func decodeOpKey(s string) ([]byte, error) {
+	if s == "-" {
+		return nil, nil
+	}
+	return hex.DecodeString(s)
+}
+
+func (r *RecordingDatabase) Set(key, value []byte) error {
+	err := r.Database.Set(key, value)
+	r.record(opSet, [][]byte{key}, len(value), err)
+	return err
+}
+
+func (r *RecordingDatabase) Get(key []byte) ([]byte, io.Closer, error) {
+	value, closer, err := r.Database.Get(key)
+	size := len(value)
+	if err != nil {
+		size = -1
+	}
+	r.record(opGet, [][]byte{key}, size, err)
+	return value, closer, err
+}
+
+func (r *RecordingDatabase) Delete(key []byte) error {
+	err := r.Database.Delete(key)
+	r.record(opDelete, [][]byte{key}, 0, err)
+	return err
+}
+
+func (r *RecordingDatabase) NewIterator(start, end []byte) (Iterator, error) {
+	it, err := r.Database.NewIterator(start, end)
+	r.record(opIterate, [][]byte{start, end}, 0, err)
+	return it, err
+}
+
+func (r *RecordingDatabase) Compact(start, end []byte) error {
+	err := r.Database.Compact(start, end)
+	r.record(opCompact, [][]byte{start, end}, 0, err)
+	return err
+}
+
+func (r *RecordingDatabase) Flush() error {
+	err := r.Database.Flush()
+	r.record(opFlush, nil, 0, err)
+	return err
+}
+
+// WaitForQuiesce forwards to the wrapped database when it is a Quiescer
+func (r *RecordingDatabase) WaitForQuiesce(timeout time.Duration) (bool, error) {
+	if q, ok := r.Database.(Quiescer); ok {
+		return q.WaitForQuiesce(timeout)
+	}
+	return true, nil
+}
+
+// Close closes the wrapped database and then the log
+func (r *RecordingDatabase) Close() error {
+	err := r.Database.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	logErr := r.err
+	if flushErr := r.w.Flush(); logErr == nil && flushErr != nil {
+		logErr = fmt.Errorf("failed to write %s: %w", r.name, flushErr)
+	}
+	if closeErr := r.f.Close(); logErr == nil && closeErr != nil {
+		logErr = fmt.Errorf("failed to close %s: %w", r.name, closeErr)
+	}
+	log.Info().Str("path", r.f.Name()).Uint64("ops", r.ops).Msg("Closed " + r.name)
+	return errors.Join(err, logErr)
+}
+
+// applyOp runs one logged operation against db, returning the length of the
+// value a get found, -1 when it failed
+func applyOp(db Database, op string, keys [][]byte, value []byte) (int, error) {
+	switch op {
+	case opSet:
+		return len(value), db.Set(keys[0], value)
+	case opGet:
+		got, closer, err := db.Get(keys[0])
+		size := len(got)
+		if closer != nil {
+			closer.Close()
+		}
+		if err != nil {
+			size = -1
+		}
+		return size, err
+	case opDelete:
+		return 0, db.Delete(keys[0])
+	case opIterate:
+		return 0, drainIterator(db, keys[0], keys[1])
+	case opCompact:
+		return 0, db.Compact(keys[0], keys[1])
+	case opFlush:
+		return 0, db.Flush()
+	}
+	return 0, fmt.Errorf("unknown operation %q", op)
+}
+
+// replayStats counts what a replay did per operation
+type replayStats struct {
+	ops        map[string]uint64
+	failed     uint64
+	notFound   uint64
+	mismatches uint64 // gets whose value length differs from the recorded one
+}
+
+// replayOps runs the operations recorded at path against db in order. Set
+// values are generated with the recorded length, so gets are compared by
+// value length and presence only.
+func replayOps(db Database, cfg Config) (*PhaseResult, error) {
+	f, err := os.Open(cfg.ReplayOps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open operation log: %w", err)
+	}
+	defer f.Close()
+
+	log.Info().Str("path", cfg.ReplayOps).Msg("Replaying operation log")
+
+	latencies := make(chan time.Duration, queueDepth(cfg))
+	collector := startLatencyCollector("replay", latencies)
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	stats := replayStats{ops: make(map[string]uint64)}
+	var errs errorSampler
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	phaseStart := time.Now()
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		op, args := fields[0], fields[1:]
+
+		var keys [][]byte
+		var size int
+		switch op {
+		case opSet, opGet:
+			if len(args) != 2 {
+				return nil, fmt.Errorf("operation log line %d: %s expects a key and a value length", line, op)
+			}
+			if size, err = strconv.Atoi(args[1]); err != nil {
+				return nil, fmt.Errorf("operation log line %d: invalid value length: %w", line, err)
+			}
+			args = args[:1]
+		case opDelete:
+			if len(args) != 1 {
+				return nil, fmt.Errorf("operation log line %d: delete expects a key", line)
+			}
+		case opIterate, opCompact:
+			if len(args) != 2 {
+				return nil, fmt.Errorf("operation log line %d: %s expects a start and an end key", line, op)
+			}
+		case opFlush:
+		default:
+			return nil, fmt.Errorf("operation log line %d: unknown operation %q", line, op)
+		}
+		for _, arg := range args {
+			key, err := decodeOpKey(arg)
+			if err != nil {
+				return nil, fmt.Errorf("operation log line %d: invalid key: %w", line, err)
+			}
+			keys = append(keys, key)
+		}
+
+		var value []byte
+		if op == opSet {
+			value = generateValue(rng, size)
+		}
+
+		opStart := time.Now()
+		gotSize, err := applyOp(db, op, keys, value)
+		if op == opGet {
+			if IsKeyNotFound(err) {
+				stats.notFound++
+				err = nil
+			}
+			if err == nil && gotSize != size {
+				stats.mismatches++
+				log.Warn().
+					Int("line", line).
+					Str("key", args[0]).
+					Int("recorded_size", size).
+					Int("replayed_size", gotSize).
+					Msg("Replayed get differs from the recording")
+			}
+		}
+		latencies <- time.Since(opStart)
+
+		stats.ops[op]++
+		if err != nil {
+			stats.failed++
+			errs.record(err)
+		}
+	}
+	elapsed := time.Since(phaseStart)
+	close(latencies)
+	collector.wait()
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read operation log: %w", err)
+	}
+
+	result := newPhaseResult("replay", collector, elapsed)
+	result.Failed = stats.failed
+	result.NotFound = stats.notFound
+	result.Successful = result.Ops - result.Failed
+	result.FirstError = errs.first
+	result.Errors = errs.samples()
+	result.OtherErrors = errs.other
+
+	event := log.Info()
+	for _, op := range []string{opSet, opGet, opDelete, opIterate, opCompact, opFlush} {
+		event = event.Uint64(op+"_ops", stats.ops[op])
+	}
+	event.
+		Uint64("ops", result.Ops).
+		Uint64("failed", result.Failed).
+		Uint64("not_found", result.NotFound).
+		Uint64("get_mismatches", stats.mismatches).
+		Float64("ops_per_sec", result.OpsPerSec()).
+		Float64("avg_latency_ms", durationMs(result.AvgLatency())).
+		Float64("p99_latency_ms", durationMs(result.P99)).
+		Dur("elapsed", result.Elapsed).
+		Msg("Replay complete")
+	return result, nil
+}
+
+// drainIterator walks every key in [start, end) the way a recorded range scan did
+func drainIterator(db Database, start, end []byte) error {
+	it, err := db.NewIterator(start, end)
+	if err != nil {
+		return err
+	}
+	for valid := it.First(); valid; valid = it.Next() {
+	}
+	err = it.Error()
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,213 @@
+package benchmark
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go "sqlite" driver for database/sql
+)
+
+// resultsSchema is the table every run is appended to. Latencies are stored in
+// nanoseconds and config holds the full Config as JSON.
+const resultsSchema = `CREATE TABLE IF NOT EXISTS runs (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp         TEXT NOT NULL,
+	git_commit        TEXT NOT NULL,
+	benchmark_id      TEXT NOT NULL,
+	backend           TEXT NOT NULL,
+	workload_type     TEXT NOT NULL,
+	workload          TEXT NOT NULL,
+	key_count         INTEGER NOT NULL,
+	write_ops         INTEGER NOT NULL,
+	write_failed      INTEGER NOT NULL,
+	write_ops_per_sec REAL NOT NULL,
+	write_p50_ns      INTEGER NOT NULL,
+	write_p99_ns      INTEGER NOT NULL,
+	read_ops          INTEGER NOT NULL,
+	read_not_found    INTEGER NOT NULL,
+	read_failed       INTEGER NOT NULL,
+	read_ops_per_sec  REAL NOT NULL,
+	read_p50_ns       INTEGER NOT NULL,
+	read_p99_ns       INTEGER NOT NULL,
+	disk_size_bytes   INTEGER NOT NULL,
+	cache_hits        INTEGER NOT NULL,
+	cache_misses      INTEGER NOT NULL,
+	config            TEXT NOT NULL
+)`
+
+// openResultsDB opens the SQLite results database at path, creating the table if needed
+func openResultsDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results database: %w", err)
+	}
+	if _, err := db.Exec(resultsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create results table: %w", err)
+	}
+	return db, nil
+}
+
+// recordResult appends a run's result and configuration to the results database
+func recordResult(path string, cfg Config, r *BenchmarkResult) error {
+	db, err := openResultsDB(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	configJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	workloadType := cfg.WorkloadType
+	if cfg.Blend != "" {
+		workloadType = string(WorkloadBlend)
+	}
+	if workloadType == "" {
+		workloadType = string(WorkloadGeneric)
+	}
+
+	_, err = db.Exec(`INSERT INTO runs (
+		timestamp, git_commit, benchmark_id, backend, workload_type, workload, key_count,
+		write_ops, write_failed, write_ops_per_sec, write_p50_ns, write_p99_ns,
+		read_ops, read_not_found, read_failed, read_ops_per_sec, read_p50_ns, read_p99_ns,
+		disk_size_bytes, cache_hits, cache_misses, config
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339), gitCommit(), r.BenchmarkID, r.Backend, workloadType, r.Workload, r.KeyCount,
+		r.WriteOps, r.WriteFailed, r.WriteOpsPerSec, int64(r.WriteP50), int64(r.WriteP99),
+		r.ReadOps, r.ReadNotFound, r.ReadFailed, r.ReadOpsPerSec, int64(r.ReadP50), int64(r.ReadP99),
+		r.DiskSizeBytes, r.CacheHits, r.CacheMisses, string(configJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record result: %w", err)
+	}
+	return nil
+}
+
+// gitCommit returns the commit the binary was built from, falling back to the
+// HEAD of the working directory, or "unknown"
+func gitCommit() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" && s.Value != "" {
+				return s.Value
+			}
+		}
+	}
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// HistoryConfig selects the runs the history subcommand reports on
+type HistoryConfig struct {
+	ResultsDB string
+	Workload  string // workload type, empty for every workload
+	Backend   string // database backend, empty for every backend
+	Last      int    // number of most recent runs to show
+}
+
+// historyRun is one row of the history report
+type historyRun struct {
+	timestamp      string
+	commit         string
+	backend        string
+	workloadType   string
+	keyCount       int
+	writeOpsPerSec float64
+	writeP99       time.Duration
+	readOpsPerSec  float64
+	readP99        time.Duration
+}
+
+// RunHistory prints the most recent runs matching cfg, oldest first, and the
+// throughput trend across them
+func RunHistory(cfg HistoryConfig) error {
+	db, err := openResultsDB(cfg.ResultsDB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT timestamp, git_commit, backend, workload_type, key_count,
+		write_ops_per_sec, write_p99_ns, read_ops_per_sec, read_p99_ns
+		FROM runs
+		WHERE (? = '' OR workload_type = ?) AND (? = '' OR backend = ?)
+		ORDER BY id DESC LIMIT ?`,
+		cfg.Workload, cfg.Workload, cfg.Backend, cfg.Backend, cfg.Last)
+	if err != nil {
+		return fmt.Errorf("failed to query results: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []historyRun
+	for rows.Next() {
+		var run historyRun
+		var writeP99, readP99 int64
+		if err := rows.Scan(&run.timestamp, &run.commit, &run.backend, &run.workloadType, &run.keyCount,
+			&run.writeOpsPerSec, &writeP99, &run.readOpsPerSec, &readP99); err != nil {
+			return fmt.Errorf("failed to read results: %w", err)
+		}
+		run.writeP99 = time.Duration(writeP99)
+		run.readP99 = time.Duration(readP99)
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read results: %w", err)
+	}
+
+	// The query returns the newest runs first
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+	return printHistory(os.Stdout, runs)
+}
+
+// printHistory renders runs as a table followed by the throughput trend
+func printHistory(out io.Writer, runs []historyRun) error {
+	if len(runs) == 0 {
+		_, err := fmt.Fprintln(out, "No matching runs")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIMESTAMP\tCOMMIT\tBACKEND\tWORKLOAD\tKEYS\tWRITE OPS/S\tWRITE P99\tREAD OPS/S\tREAD P99")
+	for _, run := range runs {
+		commit := run.commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%.0f\t%s\t%.0f\t%s\n",
+			run.timestamp, commit, run.backend, run.workloadType, run.keyCount,
+			run.writeOpsPerSec, run.writeP99, run.readOpsPerSec, run.readP99)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	first, last := runs[0], runs[len(runs)-1]
+	fmt.Fprintf(out, "\nTrend over %d runs:\n", len(runs))
+	fmt.Fprintf(out, "  write ops/sec: %.0f -> %.0f (%s)\n", first.writeOpsPerSec, last.writeOpsPerSec, formatChange(first.writeOpsPerSec, last.writeOpsPerSec))
+	fmt.Fprintf(out, "  read ops/sec:  %.0f -> %.0f (%s)\n", first.readOpsPerSec, last.readOpsPerSec, formatChange(first.readOpsPerSec, last.readOpsPerSec))
+	return nil
+}
+
+// formatChange renders the relative change from before to after as a signed percentage
+func formatChange(before, after float64) string {
+	if before == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (after-before)/before*100)
+}
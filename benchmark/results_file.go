@@ -0,0 +1,41 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// writeResultsFile writes a run's result as JSON to path, for the diff subcommand
+func writeResultsFile(path string, r *BenchmarkResult) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write results file: %w", err)
+	}
+	return nil
+}
+
+// writeResultLine writes a run's result as a single line of JSON, for --quiet
+func writeResultLine(w io.Writer, r *BenchmarkResult) error {
+	if err := json.NewEncoder(w).Encode(r); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+// readResultsFile reads a result written by writeResultsFile
+func readResultsFile(path string) (*BenchmarkResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+	var r BenchmarkResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode results file %s: %w", path, err)
+	}
+	return &r, nil
+}
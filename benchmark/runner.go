@@ -1,15 +1,20 @@
 package benchmark
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"iter"
 	"math/rand"
 	"os"
+	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/HdrHistogram/hdrhistogram-go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -21,16 +26,174 @@ type Config struct {
 	ValueSize      int     // size of values in bytes
 	Seed           int64   // RNG seed for deterministic behavior
 	DBPath         string  // path to database instance
+	ReadDBPath     string  // optional existing database the read phase runs against instead of DBPath
 	BenchmarkID    string  // optional label for this benchmark run
 	WriteEnabled   bool    // whether to write data to the DB
 	KeysFile       string  // optional file with pre-existing keys
+	UseExistingDB  bool    // read keys sampled from an already-populated database
 	Concurrency    int     // number of concurrent workers
+	QueueDepth     int     // capacity of the worker job queue, 0 means Concurrency*defaultQueueDepthPerWorker
 	LogFormat      string  // "json" or "console", default is "console"
+	LogLevel       string  // "debug", "info", "warn" or "error", default is "info"
 	BlockCacheSize int64   // in bytes, negative means disabled (nil)
+	SyncWrites     bool    // fsync the WAL on every write
+	HDROutput      string  // optional path for read/write latency histograms in HdrHistogram log format
+	LatencyCSV     string  // optional path for per-operation read/write latencies with key and value sizes
+	Summary        bool    // print an aligned summary table at the end of the run
+	Quiet          bool    // print only the result as one JSON line, logging nothing but errors to stderr
+
+	// Keys file encoding
+	KeysFormat KeyFormat // encoding of KeysFile or standard input: binary (default), hex or base64
+
+	// Disk sizing
+	FillDisk float64 // size KeyCount to fill this fraction of the free disk space, 0 disables
+
+	// Key analysis
+	AnalyzeKeys bool // report the locality of the workload's generated keys instead of running any phase
+
+	// Run estimation
+	Estimate bool // calibrate on a few thousand keys and estimate the run's duration and disk usage instead of running it
+
+	// Populated keyspace
+	Populate int // write exactly this many index-addressed keys and restrict the workload's reads to them, 0 disables
+
+	// Historical tracking
+	ResultsDB   string // optional SQLite database each run's result is appended to
+	ResultsFile string // optional JSON file the run's result is written to, for the diff command
+
+	// Operation log
+	RecordOps string // optional file every database operation is appended to
+	ReplayOps string // optional operation log to replay against a fresh database instead of the workload phases
+
+	// Key capture
+	CaptureKeys string // optional keys file every written and read key is captured to, with its value length
+
+	// Latency injection
+	InjectReadLatency   time.Duration // delay added to every read, 0 disables
+	InjectWriteLatency  time.Duration // delay added to every write, 0 disables
+	InjectLatencyJitter time.Duration // uniform random extra delay in [0, jitter) on top of each injected delay
+
+	// Profiling of the benchmark process itself
+	CPUProfile string // optional path for a pprof CPU profile of the run
+	MemProfile string // optional path for a pprof heap profile written at the end
+
+	// Load pacing
+	TargetOpsPerSec float64 // pace the write and read phases to this many operations per second, 0 runs unthrottled
+
+	// Key generation
+	GeneratorWorkers int // goroutines generating write keys in parallel, each with its own seed and workload instance; <= 1 uses a single generator
+
+	// Batched writes
+	ApplyBatch int // write through shared batches closed at block boundaries or after this many writes, 0 writes with Set
+
+	// Value shaping
+	ValueAlign    int     // round value sizes up to a multiple of this many bytes, <= 1 disables
+	ValueDupRatio float64 // fraction of values drawn from a small seeded pool of repeated values
+
+	// Value profile
+	ValueProfile ValueProfile // generate every value with this profile instead of the workload's generator, empty keeps the workload's
+
+	// Value determinism
+	DeterministicValues bool // derive every value from a hash of its key and Seed instead of a worker's generator
+
+	// Read phase
+	ReadOrder          ReadOrder // order of the read phase keys: sequential, random or shuffled
+	FlushBetweenPhases bool      // flush and wait for background work to settle before reads
+
+	// Read key shuffling
+	ShuffleSeed int64 // seed the read key order draws from instead of Seed, shuffling a sequential order; 0 disables
+
+	// Quiescing
+	QuiesceTimeout time.Duration // bound on waiting for background work to settle, 0 for the default
+
+	// Flush settling
+	FlushWaitCompaction bool // Pebble: every Flush also waits, up to QuiesceTimeout, for compactions to drain
+
+	// Compaction debt
+	L0FilesTarget int // stop the write phase once Pebble reports this many L0 files, 0 disables
+
+	// Byte limits
+	MaxBytesWritten int64 // stop the write phase once this many value bytes are written, 0 disables
+	MaxBytesRead    int64 // stop the read phase once this many value bytes are read, 0 disables
+
+	// Error handling
+	FailOnError  bool    // fail the run when a phase's error rate exceeds MaxErrorRate
+	MaxErrorRate float64 // tolerated fraction of failed operations per phase with FailOnError
+
+	// Latency SLA
+	P99BudgetMs float64 // fail the run when a write or read phase's p99 exceeds this, 0 disables
+
+	// Instrumentation
+	MeasureGeneration bool          // time key/value generation separately from database I/O
+	PregenerateValues bool          // generate all values before the timed write loop
+	MetricsInterval   time.Duration // sample GetMetrics at this interval, 0 disables
+	SpaceAmpInterval  time.Duration // sample space amplification during the write phase at this interval, 0 disables
+	VerifyChecksums   bool          // checksum written and read key/value pairs and compare them after the read phase
+
+	// Fill curve
+	FillCurve       string // optional CSV path for write throughput per bucket of logical bytes written
+	FillCurveBucket int64  // logical bytes per fill curve bucket, 0 means 1GB
+
+	// Write order
+	SortKeys bool // buffer and sort the write keys, the best case ingestion order
+
+	// Cache warming
+	WarmHotKeys bool // read the workload's hot set before the read phase, measuring a warm cache
+
+	// Datasets
+	GenDataset  string // write the generated key/value pairs to this file instead of running any phase
+	LoadDataset string // write the key/value pairs of a gen-dataset file instead of generating them
+
+	// CPU scheduling
+	GOMAXPROCS   int  // GOMAXPROCS for the run, 0 keeps the Go default of every CPU
+	LockOSThread bool // pin every write and read worker to its own OS thread
+
+	// Range query phase
+	RangeQueries  int           // number of workload range queries to execute after the read phase
+	ScanDirection ScanDirection // forward, reverse or both
+
+	// Range limit
+	RangeLimit int // items each range query returns at most, overriding the workload's limit, 0 keeps it
+
+	// Read-after-write freshness phase
+	FreshnessProbe bool          // read keys back after intervening writes / a delay
+	FreshnessKeys  int           // number of keys written for the freshness phase
+	FreshnessLags  string        // comma-separated intervening write counts before a probe read
+	FreshnessDelay time.Duration // additional re-read of all probes after this delay, 0 disables
+
+	// Tombstone scan phase
+	TombstoneScan bool // measure range scans over deleted keys before/after compaction
+	TombstoneKeys int  // number of keys written for the tombstone scan phase
+
+	// Mixed read/write phase
+	Mixed        bool // run concurrent read and write pools split by ReadRatio after the read phase
+	ReadWorkers  int  // read pool size, 0 uses Concurrency
+	WriteWorkers int  // write pool size, 0 uses Concurrency
+
+	// Snapshot phase
+	SnapshotReaders    int // goroutines opening, reading from and closing snapshots after the read phase, 0 disables
+	SnapshotsPerReader int // snapshots each reader opens in turn
+	SnapshotReads      int // reads from each snapshot
+
+	// Write contention phase
+	Contention        bool    // run concurrent writers over partially shared keys
+	ContentionOps     int     // writes per writer
+	CollisionRate     float64 // probability a write targets the shared hot keys
+	ContentionHotKeys int     // number of keys shared by every writer
 
 	// Database backend configuration
-	DatabaseType     string // "pebble", "qmdb", or "mdbx"
+	DatabaseType     string // "pebble", "qmdb", "mdbx", or "memory"
 	QMDBLibraryPath  string // path to QMDB shared library
+	PebbleComparer   string // Pebble key ordering: "default" or "blocknum"
+	PebbleDisableWAL bool   // Pebble: write without a WAL, losing unflushed data on crash
+	PebbleCopyValues bool   // Pebble: copy read values out of the block cache like the other backends
+
+	// Key namespace
+	KeyPrefix string // store every key under this prefix, confining the run to its namespace
+
+	// Pebble open file limits, 0 keeps Pebble's defaults
+	PebbleMaxOpenFiles   int // Pebble: files the database may keep open
+	PebbleTableCacheSize int // Pebble: sstables kept open in the table cache
 	
 	// MDBX-specific configuration
 	MDBXMapSize     int64 // maximum map size in bytes (-1 for default)
@@ -43,12 +206,39 @@ type Config struct {
 
 	// Workload configuration
 	WorkloadType     string  // Type of workload to run
+	Blend            string  // Weighted workload blend spec; overrides WorkloadType when set
+	ReadRatios       string  // pos-mixed read probability per key prefix, e.g. "h=0.9,a=0.85"
 	RecentBlockBias  float64 // PoS: probability of accessing recent blocks
 	HotAccountRatio  float64 // PoS: ratio of hot accounts
+	HotAccountCount  int     // PoS/TX: exact number of hot accounts, overrides the derived count when > 0
 	StateLocality    float64 // PoS: probability of accessing related state
 	BlockRange       int     // PoS: range of block numbers
+	BlockKeyLayout   string  // PoS blocks: block number encoding in keys, "be" or "le"
 	AccountCount     int     // PoS: number of unique accounts
 	StorageSlotRatio float64 // PoS: average storage slots per account
+	Keyspace         int     // Update: unique keys populated before overwrites begin
+	StorageTrieDepth int     // Storage trie: depth of each storage trie, 0 for default
+	ContractCount    int     // Storage trie: number of contracts
+
+	// Reorg workload
+	ReorgInterval int // blocks appended between reorgs, 0 for default
+	ReorgDepth    int // most recent blocks each reorg replaces, 0 for default
+
+	// Account existence workload
+	ExistenceHitRatio float64 // fraction of reads targeting written accounts
+
+	// Mixed value sizes
+	LargeValueRatio float64 // fraction of writes that are large values
+	LargeValueSize  int     // size of large values in bytes, 0 for default
+
+	// Trie simulation depth
+	TrieAverageDepth      int    // average state trie depth, 0 for default
+	TrieMaxDepth          int    // maximum state trie depth, 0 for default
+	TrieDepthVariance     *int   // spread of the per-path depth, nil for default
+	TrieDepthDistribution string // "uniform" or "normal"
+
+	// Trie node values
+	TrieNodeEncoding string // "random" or "rlp", empty for each workload's default
 	
 	// Transaction execution workload configuration
 	NetworkType              string  // Network type: ethereum, polygon, custom
@@ -60,6 +250,7 @@ type Config struct {
 	TxStorageTrieDepth       int     // Storage trie depth for transaction workload
 	TxReadWriteRatio         float64 // Read/write ratio for transaction workload
 	TxContractRatio          float64 // Contract ratio for transaction workload
+	TxStorageOpMultiplier    float64 // Scale of storage operations per transaction, 0 for 1
 	TxPerBlock               int     // Transactions per block
 	GasTargetPerBlock        uint64  // Target gas per block
 	TxSimpleTransferRatio    float64 // Simple transfer ratio in transaction mix
@@ -67,10 +258,55 @@ type Config struct {
 	TxUniswapSwapRatio       float64 // Uniswap swap ratio in transaction mix
 	TxComplexDeFiRatio       float64 // Complex DeFi ratio in transaction mix
 	TxContractDeployRatio    float64 // Contract deployment ratio in transaction mix
+
+	// Block production pacing
+	BlockTime time.Duration // TX: block interval to pace writes to, 0 for no pacing
+
+	// Block commit shape
+	CommitOpsMin    int    // TX: fewest operations per block commit
+	CommitOpsMax    int    // TX: most operations per block commit
+	CommitKeyPrefix string // TX: key prefix of block commit operations
+	CommitValueMin  int    // TX: smallest block commit value in bytes
+	CommitValueMax  int    // TX: largest block commit value in bytes
+
+	// State commit batching
+	CommitInterval          int    // pos-accounts-realistic: logical operations between commits, 0 commits at random
+	CommitNodesMin          int    // pos-accounts-realistic: fewest dirty nodes per commit
+	CommitNodesMax          int    // pos-accounts-realistic: most dirty nodes per commit
+	CommitNodesDistribution string // pos-accounts-realistic: "uniform" or "normal"
 }
 
 // RunBenchmark orchestrates the full benchmark lifecycle
 func RunBenchmark(cfg Config) error {
+	stopProfiling, err := startProfiling(cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := runBenchmark(cfg)
+	if err == nil && cfg.Quiet {
+		err = writeResultLine(os.Stdout, result)
+	}
+	if profErr := stopProfiling(); err == nil {
+		err = profErr
+	}
+	return err
+}
+
+// runBenchmark runs every configured phase and returns the headline results
+func runBenchmark(cfg Config) (*BenchmarkResult, error) {
+	if _, ok := logLevels[strings.ToLower(cfg.LogLevel)]; cfg.LogLevel != "" && !ok {
+		return nil, fmt.Errorf("invalid --log-level %q: expected debug, info, warn or error", cfg.LogLevel)
+	}
+	if cfg.Quiet && (cfg.Summary || cfg.AnalyzeKeys || cfg.Estimate) {
+		return nil, fmt.Errorf("--quiet prints only the result and cannot be combined with --summary, --analyze-keys or --estimate")
+	}
+	if cfg.GOMAXPROCS < 0 {
+		return nil, fmt.Errorf("--gomaxprocs cannot be negative, got %d", cfg.GOMAXPROCS)
+	}
+	if cfg.GOMAXPROCS > 0 {
+		runtime.GOMAXPROCS(cfg.GOMAXPROCS)
+	}
 	setupLog(cfg)
 	initialLog(cfg)
 
@@ -80,12 +316,33 @@ func RunBenchmark(cfg Config) error {
 		ValueSize:        cfg.ValueSize,
 		ReadRatio:        cfg.ReadRatio,
 		Seed:             cfg.Seed,
+		Blend:            cfg.Blend,
 		RecentBlockBias:  cfg.RecentBlockBias,
 		HotAccountRatio:  cfg.HotAccountRatio,
+		HotAccountCount:  cfg.HotAccountCount,
 		StateLocality:    cfg.StateLocality,
 		BlockRange:       cfg.BlockRange,
+		BlockKeyLayout:   cfg.BlockKeyLayout,
 		AccountCount:     cfg.AccountCount,
 		StorageSlotRatio: cfg.StorageSlotRatio,
+		Keyspace:         cfg.Keyspace,
+		StorageTrieDepth: cfg.StorageTrieDepth,
+		ContractCount:    cfg.ContractCount,
+		// Reorg workload
+		ReorgInterval: cfg.ReorgInterval,
+		ReorgDepth:    cfg.ReorgDepth,
+		// Account existence workload
+		ExistenceHitRatio: cfg.ExistenceHitRatio,
+		// Mixed value sizes
+		LargeValueRatio: cfg.LargeValueRatio,
+		LargeValueSize:  cfg.LargeValueSize,
+		// Trie simulation depth
+		TrieAverageDepth:      cfg.TrieAverageDepth,
+		TrieMaxDepth:          cfg.TrieMaxDepth,
+		TrieDepthVariance:     cfg.TrieDepthVariance,
+		TrieDepthDistribution: cfg.TrieDepthDistribution,
+		// Trie node values
+		TrieNodeEncoding: cfg.TrieNodeEncoding,
 		// Transaction execution workload configuration
 		NetworkType:              cfg.NetworkType,
 		TransactionMix:           cfg.TransactionMix,
@@ -96,6 +353,7 @@ func RunBenchmark(cfg Config) error {
 		TxStorageTrieDepth:       cfg.TxStorageTrieDepth,
 		TxReadWriteRatio:         cfg.TxReadWriteRatio,
 		TxContractRatio:          cfg.TxContractRatio,
+		TxStorageOpMultiplier:    cfg.TxStorageOpMultiplier,
 		TxPerBlock:               cfg.TxPerBlock,
 		GasTargetPerBlock:        cfg.GasTargetPerBlock,
 		TxSimpleTransferRatio:    cfg.TxSimpleTransferRatio,
@@ -103,43 +361,781 @@ func RunBenchmark(cfg Config) error {
 		TxUniswapSwapRatio:       cfg.TxUniswapSwapRatio,
 		TxComplexDeFiRatio:       cfg.TxComplexDeFiRatio,
 		TxContractDeployRatio:    cfg.TxContractDeployRatio,
+		// Block commit shape
+		CommitOpsMin:    cfg.CommitOpsMin,
+		CommitOpsMax:    cfg.CommitOpsMax,
+		CommitKeyPrefix: cfg.CommitKeyPrefix,
+		CommitValueMin:  cfg.CommitValueMin,
+		CommitValueMax:  cfg.CommitValueMax,
+		// State commit batching
+		CommitInterval:          cfg.CommitInterval,
+		CommitNodesMin:          cfg.CommitNodesMin,
+		CommitNodesMax:          cfg.CommitNodesMax,
+		CommitNodesDistribution: cfg.CommitNodesDistribution,
+	}
+	if err := cfg.ReadOrder.validate(); err != nil {
+		return nil, err
+	}
+	readOrderSeed := cfg.Seed
+	if cfg.ShuffleSeed != 0 {
+		// Scramble the order without changing the keyset the run seed generates
+		readOrderSeed = cfg.ShuffleSeed
+		if cfg.ReadOrder == "" || cfg.ReadOrder == ReadOrderSequential {
+			cfg.ReadOrder = ReadOrderShuffled
+		}
+	}
+
+	switch cfg.BlockKeyLayout {
+	case "", BlockKeyLayoutBigEndian, BlockKeyLayoutLittleEndian:
+	default:
+		return nil, fmt.Errorf("invalid --block-key-layout %q: expected be or le", cfg.BlockKeyLayout)
+	}
+	switch cfg.TrieDepthDistribution {
+	case "", TrieDepthUniform, TrieDepthNormal:
+	default:
+		return nil, fmt.Errorf("invalid --trie-depth-distribution %q: expected uniform or normal", cfg.TrieDepthDistribution)
+	}
+	switch cfg.TrieNodeEncoding {
+	case "", TrieNodeEncodingRandom, TrieNodeEncodingRLP:
+	default:
+		return nil, fmt.Errorf("invalid --trie-node-encoding %q: expected random or rlp", cfg.TrieNodeEncoding)
+	}
+	if cfg.TxStorageOpMultiplier < 0 {
+		return nil, fmt.Errorf("--storage-op-multiplier cannot be negative, got %g", cfg.TxStorageOpMultiplier)
+	}
+	if cfg.TrieMaxDepth > 0 && cfg.TrieAverageDepth > cfg.TrieMaxDepth {
+		return nil, fmt.Errorf("--trie-average-depth %d exceeds --trie-max-depth %d", cfg.TrieAverageDepth, cfg.TrieMaxDepth)
+	}
+
+	if cfg.FreshnessProbe {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("freshness probe requires --write")
+		}
+		if _, err := parseFreshnessLags(cfg.FreshnessLags); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.TombstoneScan {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("tombstone scan requires --write")
+		}
+		if cfg.TombstoneKeys <= 0 {
+			return nil, fmt.Errorf("tombstone scan requires a positive --tombstone-keys")
+		}
+	}
+
+	if cfg.Contention {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("contention phase requires --write")
+		}
+		if cfg.ContentionHotKeys <= 0 {
+			return nil, fmt.Errorf("contention phase requires a positive --contention-hot-keys")
+		}
+		if cfg.CollisionRate < 0 || cfg.CollisionRate > 1 {
+			return nil, fmt.Errorf("--collision-rate must be between 0 and 1")
+		}
+	}
+
+	if cfg.Mixed {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("mixed phase requires --write")
+		}
+		if cfg.ReadRatio < 0 || cfg.ReadRatio > 1 {
+			return nil, fmt.Errorf("--read-ratio must be between 0 and 1")
+		}
+	}
+
+	if cfg.ReadRatios != "" {
+		ratios, err := ParseReadRatios(cfg.ReadRatios)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --read-ratios: %w", err)
+		}
+		if !cfg.Mixed {
+			return nil, fmt.Errorf("--read-ratios picks the reads of the mixed phase and requires --mixed")
+		}
+		if workloadCfg.Type != WorkloadPoSMixed && cfg.Blend == "" {
+			log.Warn().Str("workload", string(workloadCfg.Type)).Msg("--read-ratios only applies to the pos-mixed workload and will be ignored")
+		}
+		workloadCfg.ReadRatios = ratios
+	}
+
+	if cfg.Blend != "" {
+		workloadCfg.Type = WorkloadBlend
+	}
+	newWorkload := func() (Workload, error) {
+		if cfg.Blend == "" {
+			return CreateWorkload(workloadCfg), nil
+		}
+		blend, err := NewBlendWorkload(workloadCfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --blend: %w", err)
+		}
+		return blend, nil
+	}
+	workload, err := newWorkload()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.L0FilesTarget > 0 {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--l0-files-target requires --write")
+		}
+		if dbType := DatabaseType(cfg.DatabaseType); dbType != "" && dbType != DatabaseTypePebble {
+			return nil, fmt.Errorf("--l0-files-target requires the pebble backend")
+		}
+		if cfg.FlushBetweenPhases || cfg.Populate > 0 {
+			return nil, fmt.Errorf("--l0-files-target cannot be combined with --flush-between-phases or --populate")
+		}
+	}
+	if cfg.MaxBytesWritten < 0 || cfg.MaxBytesRead < 0 {
+		return nil, fmt.Errorf("--max-bytes-written and --max-bytes-read must not be negative")
+	}
+	if cfg.MaxBytesWritten > 0 {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--max-bytes-written requires --write")
+		}
+		if cfg.Populate > 0 || cfg.SortKeys {
+			// The reads follow the first generated keys
+			return nil, fmt.Errorf("--max-bytes-written cannot be combined with --populate or --sort-keys")
+		}
+	}
+	if cfg.SpaceAmpInterval > 0 {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--space-amp-interval requires --write")
+		}
+		if DatabaseType(cfg.DatabaseType) == DatabaseTypeMemory {
+			return nil, fmt.Errorf("--space-amp-interval requires an on-disk backend")
+		}
+	}
+	if cfg.SnapshotReaders < 0 {
+		return nil, fmt.Errorf("--snapshot-readers must not be negative")
+	}
+	if cfg.SnapshotReaders > 0 && (cfg.SnapshotsPerReader <= 0 || cfg.SnapshotReads <= 0) {
+		return nil, fmt.Errorf("--snapshot-readers requires a positive --snapshots-per-reader and --snapshot-reads")
+	}
+	if cfg.FillCurve != "" && !cfg.WriteEnabled {
+		return nil, fmt.Errorf("--fill-curve requires --write")
+	}
+	if cfg.FillCurveBucket < 0 {
+		return nil, fmt.Errorf("--fill-curve-bucket must not be negative")
+	}
+	if cfg.SortKeys {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--sort-keys requires --write")
+		}
+		if cfg.L0FilesTarget > 0 {
+			// The reads follow the first generated keys, not the first sorted ones
+			return nil, fmt.Errorf("--sort-keys cannot be combined with --l0-files-target")
+		}
+	}
+	if cfg.DeterministicValues && cfg.PregenerateValues && cfg.KeyCount > pregenerateMaxPairs {
+		return nil, fmt.Errorf("--deterministic-values cannot be combined with --pregenerate-values above %d keys, which reuses values across keys", pregenerateMaxPairs)
+	}
+	if cfg.LoadDataset != "" {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--load-dataset requires --write")
+		}
+		if cfg.Populate > 0 || cfg.SortKeys || cfg.PregenerateValues || cfg.GeneratorWorkers > 1 {
+			// The dataset fixes the keys, their order and their values
+			return nil, fmt.Errorf("--load-dataset cannot be combined with --populate, --sort-keys, --pregenerate-values or --generator-workers")
+		}
+		if err := checkDataset(cfg.LoadDataset); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.CommitKeyPrefix != "" {
+		if _, err := transactionKeyPrefixes([]byte(cfg.CommitKeyPrefix)); err != nil {
+			return nil, fmt.Errorf("invalid --commit-key-prefix: %w", err)
+		}
+	}
+	if cfg.CommitOpsMin < 0 || cfg.CommitOpsMax < cfg.CommitOpsMin {
+		return nil, fmt.Errorf("--commit-ops-min must be non-negative and at most --commit-ops-max")
+	}
+	if cfg.CommitValueMin < 0 || cfg.CommitValueMax < cfg.CommitValueMin {
+		return nil, fmt.Errorf("--commit-value-min must be non-negative and at most --commit-value-max")
+	}
+	if cfg.ApplyBatch < 0 {
+		return nil, fmt.Errorf("--apply-batch must be non-negative")
+	}
+	if cfg.ApplyBatch > 0 && !cfg.WriteEnabled {
+		return nil, fmt.Errorf("--apply-batch requires --write")
+	}
+	if cfg.CommitInterval < 0 {
+		return nil, fmt.Errorf("--commit-interval must be non-negative")
+	}
+	if cfg.CommitNodesMin < 0 || cfg.CommitNodesMax < cfg.CommitNodesMin {
+		return nil, fmt.Errorf("--commit-nodes-min must be non-negative and at most --commit-nodes-max")
+	}
+	switch cfg.CommitNodesDistribution {
+	case "", CommitNodesUniform, CommitNodesNormal:
+	default:
+		return nil, fmt.Errorf("invalid --commit-nodes-distribution %q: expected uniform or normal", cfg.CommitNodesDistribution)
+	}
+	if cfg.ValueDupRatio < 0 || cfg.ValueDupRatio > 1 {
+		return nil, fmt.Errorf("--value-dup-ratio must be between 0 and 1")
+	}
+	if cfg.RangeLimit < 0 {
+		return nil, fmt.Errorf("--range-limit must be non-negative")
+	}
+	if cfg.ExistenceHitRatio < 0 || cfg.ExistenceHitRatio > 1 {
+		return nil, fmt.Errorf("--existence-hit-ratio must be between 0 and 1")
+	}
+	if err := cfg.ValueProfile.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Populate > 0 {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--populate requires --write")
+		}
+		if cfg.FillDisk > 0 {
+			return nil, fmt.Errorf("--populate cannot be combined with --fill-disk")
+		}
+	}
+
+	var fillDisk *fillDiskEstimate
+	if cfg.FillDisk > 0 {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--fill-disk requires --write")
+		}
+		if cfg.FillDisk > 1 {
+			return nil, fmt.Errorf("--fill-disk must be between 0 and 1")
+		}
+		if DatabaseType(cfg.DatabaseType) == DatabaseTypeMemory {
+			return nil, fmt.Errorf("--fill-disk requires an on-disk backend")
+		}
+		if fillDisk, err = estimateFillDisk(cfg, newWorkload); err != nil {
+			return nil, err
+		}
+		cfg.KeyCount = fillDisk.keyCount
 	}
-	workload := CreateWorkload(workloadCfg)
 
 	log.Info().
 		Str("workload", workload.Name()).
 		Str("description", workload.GetDescription()).
 		Msg("Using workload")
+	if ignorer, ok := workload.(ValueSizeIgnorer); ok && ignorer.IgnoresValueSize() && cfg.Populate == 0 && cfg.ValueProfile == "" {
+		log.Warn().
+			Str("workload", workload.Name()).
+			Int("value_size", cfg.ValueSize).
+			Msg("--value-size is ignored: this workload sizes values by key type, see the realized value sizes after the write phase")
+	}
+	if WorkloadType(cfg.WorkloadType) == WorkloadPoSAccountsReal && cfg.Populate == 0 && cfg.ValueProfile == "" {
+		log.Warn().
+			Int("value_size", cfg.ValueSize).
+			Msg("--value-size only sizes the pos-accounts-realistic keys of no known type, accounts, storage and trie nodes carry their own sizes")
+	}
+	if WorkloadType(cfg.WorkloadType) == WorkloadStorageDump && cfg.RangeQueries == 0 {
+		log.Warn().Msg("The storage-dump workload measures its dumps in the range phase, set --range-queries to run them")
+	}
+
+	if cfg.AnalyzeKeys {
+		if cfg.WriteEnabled || cfg.UseExistingDB {
+			return nil, fmt.Errorf("--analyze-keys opens no database and cannot be combined with --write or --use-existing-db")
+		}
+		if err := analyzeKeys(cfg, workload); err != nil {
+			return nil, err
+		}
+		return &BenchmarkResult{BenchmarkID: cfg.BenchmarkID, Workload: workload.Name(), KeyCount: cfg.KeyCount}, nil
+	}
+	if cfg.Estimate {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--estimate calibrates on written keys and requires --write")
+		}
+		if cfg.Populate > 0 || cfg.LoadDataset != "" {
+			return nil, fmt.Errorf("--estimate calibrates on workload keys and cannot be combined with --populate or --load-dataset")
+		}
+		if err := runEstimate(cfg, newWorkload); err != nil {
+			return nil, err
+		}
+		return &BenchmarkResult{BenchmarkID: cfg.BenchmarkID, Workload: workload.Name(), KeyCount: cfg.KeyCount}, nil
+	}
+	if _, ok := workload.(DeletionReporter); ok {
+		// Deletes follow the key order of a single generator and bypass
+		// batches, and a second worker could apply a delete before the write
+		// it removes
+		if cfg.Concurrency > 1 || cfg.GeneratorWorkers > 1 || cfg.SortKeys || cfg.ApplyBatch > 0 || cfg.LoadDataset != "" || cfg.GenDataset != "" || cfg.VerifyChecksums {
+			return nil, fmt.Errorf("workload %s deletes keys and cannot be combined with --concurrency above 1, --generator-workers, --sort-keys, --apply-batch, --load-dataset, --gen-dataset or --verify-checksums", workload.Name())
+		}
+	}
+	if fixed, ok := workload.(SeedIndependentKeys); ok && fixed.KeysIgnoreSeed() && cfg.GeneratorWorkers > 1 && cfg.Populate == 0 {
+		return nil, fmt.Errorf("workload %s generates the same keys for every seed and cannot be combined with --generator-workers", workload.Name())
+	}
+	if _, ok := workload.(ReadKeyGenerator); ok {
+		if cfg.VerifyChecksums {
+			return nil, fmt.Errorf("workload %s reads keys it never wrote and cannot be combined with --verify-checksums", workload.Name())
+		}
+		// Hits are drawn from every key a single generator yields, so the
+		// write phase must write all of them
+		if (cfg.GeneratorWorkers > 1 && cfg.Populate == 0) || cfg.MaxBytesWritten > 0 || cfg.L0FilesTarget > 0 {
+			return nil, fmt.Errorf("workload %s reads back every generated key and cannot be combined with --generator-workers, --max-bytes-written or --l0-files-target", workload.Name())
+		}
+	}
+	if cfg.BlockTime > 0 {
+		if _, ok := workload.(BlockBoundaryReporter); !ok {
+			return nil, fmt.Errorf("--block-time: workload %s has no block boundaries", workload.Name())
+		}
+		if cfg.SortKeys || cfg.LoadDataset != "" || cfg.GeneratorWorkers > 1 {
+			return nil, fmt.Errorf("--block-time paces the generated blocks and cannot be combined with --sort-keys, --load-dataset or --generator-workers")
+		}
+	}
+	if _, ok := workload.(HotKeyReporter); cfg.WarmHotKeys && !ok {
+		return nil, fmt.Errorf("--warm-hot-keys: workload %s has no hot set", workload.Name())
+	}
+	if cfg.GenDataset != "" {
+		if cfg.WriteEnabled || cfg.UseExistingDB {
+			return nil, fmt.Errorf("gen-dataset opens no database and cannot be combined with --write or --use-existing-db")
+		}
+		if err := genDataset(cfg, workload); err != nil {
+			return nil, err
+		}
+		return &BenchmarkResult{BenchmarkID: cfg.BenchmarkID, Workload: workload.Name(), KeyCount: cfg.KeyCount}, nil
+	}
+
+	// The read phase opens its own database only when it targets another path
+	separateReadDB := cfg.ReadDBPath != "" && cfg.ReadDBPath != cfg.DBPath
+	if separateReadDB {
+		if DatabaseType(cfg.DatabaseType) == DatabaseTypeMemory {
+			return nil, fmt.Errorf("--read-db-path requires an on-disk backend")
+		}
+		if cfg.RecordOps != "" || cfg.CaptureKeys != "" {
+			return nil, fmt.Errorf("--read-db-path cannot be combined with --record-ops or --capture-keys, which only see --db-path")
+		}
+		if _, err := os.Stat(cfg.ReadDBPath); err != nil {
+			return nil, fmt.Errorf("read database not found: %w", err)
+		}
+	}
+
+	if cfg.UseExistingDB {
+		if cfg.WriteEnabled {
+			return nil, fmt.Errorf("--use-existing-db cannot be combined with --write")
+		}
+		if _, err := os.Stat(cfg.DBPath); err != nil {
+			return nil, fmt.Errorf("existing database not found: %w", err)
+		}
+	}
+
+	if cfg.ReplayOps != "" {
+		if !cfg.WriteEnabled {
+			return nil, fmt.Errorf("--replay-ops requires --write")
+		}
+		if DatabaseType(cfg.DatabaseType) != DatabaseTypeMemory {
+			if _, err := os.Stat(cfg.DBPath); err == nil {
+				return nil, fmt.Errorf("--replay-ops needs a fresh database but %s already exists", cfg.DBPath)
+			}
+		}
+	}
+
+	switch cfg.KeysFormat {
+	case "", KeyFormatBinary, KeyFormatHex, KeyFormatBase64:
+	default:
+		return nil, fmt.Errorf("invalid --keys-format %q: expected binary, hex or base64", cfg.KeysFormat)
+	}
+
+	// A keys file of captured operations replays its writes and reads in place
+	// of both phases
+	var replayCaptured bool
+	if cfg.KeysFile != "" && (cfg.KeysFormat == "" || cfg.KeysFormat == KeyFormatBinary) {
+		if replayCaptured, err = isKeyOpsFile(cfg.KeysFile); err != nil {
+			return nil, err
+		}
+		if replayCaptured && !cfg.WriteEnabled {
+			return nil, fmt.Errorf("replaying the operations captured in %s requires --write", cfg.KeysFile)
+		}
+	}
+
+	if cfg.InjectReadLatency < 0 || cfg.InjectWriteLatency < 0 || cfg.InjectLatencyJitter < 0 {
+		return nil, fmt.Errorf("--inject-read-latency, --inject-write-latency and --inject-latency-jitter cannot be negative")
+	}
+	injectLatency := cfg.InjectReadLatency > 0 || cfg.InjectWriteLatency > 0
 
 	dbConn, err := createDatabase(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create database: %w", err)
+		return nil, fmt.Errorf("failed to create database: %w", err)
+	}
+	backendConfig := effectiveConfig(dbConn)
+	if injectLatency {
+		log.Info().
+			Dur("read", cfg.InjectReadLatency).
+			Dur("write", cfg.InjectWriteLatency).
+			Dur("jitter", cfg.InjectLatencyJitter).
+			Msg("Injecting storage latency")
+		dbConn = NewLatencyInjectingDatabase(dbConn, cfg.InjectReadLatency, cfg.InjectWriteLatency, cfg.InjectLatencyJitter)
+	}
+	if cfg.RecordOps != "" {
+		recorder, err := NewRecordingDatabase(dbConn, cfg.RecordOps)
+		if err != nil {
+			dbConn.Close()
+			return nil, err
+		}
+		log.Info().Str("path", cfg.RecordOps).Msg("Recording database operations")
+		dbConn = recorder
+	}
+	if cfg.CaptureKeys != "" {
+		capture, err := NewKeyCaptureDatabase(dbConn, cfg.CaptureKeys)
+		if err != nil {
+			dbConn.Close()
+			return nil, err
+		}
+		log.Info().Str("path", cfg.CaptureKeys).Msg("Capturing keys")
+		dbConn = capture
 	}
 	defer dbConn.Close()
 
+	readDB := dbConn
+	if separateReadDB {
+		readCfg := cfg
+		readCfg.DBPath = cfg.ReadDBPath
+		readCfg.WriteEnabled = false
+		if readDB, err = createDatabase(readCfg); err != nil {
+			return nil, fmt.Errorf("failed to open read database: %w", err)
+		}
+		if injectLatency {
+			readDB = NewLatencyInjectingDatabase(readDB, cfg.InjectReadLatency, cfg.InjectWriteLatency, cfg.InjectLatencyJitter)
+		}
+		defer readDB.Close()
+		log.Info().Str("path", cfg.ReadDBPath).Msg("Reading from a separate database")
+	}
+
+	caps := dbConn.Capabilities()
+	if cfg.UseExistingDB && !caps.SupportsIterator {
+		return nil, fmt.Errorf("--use-existing-db samples keys with an iterator, which the %s backend does not support", cfg.DatabaseType)
+	}
+	if cfg.ApplyBatch > 0 && !caps.SupportsBatch {
+		return nil, fmt.Errorf("--apply-batch needs atomic batches, which the %s backend does not support", cfg.DatabaseType)
+	}
+	if _, ok := dbConn.(Batcher); cfg.ApplyBatch > 0 && !ok {
+		return nil, fmt.Errorf("--apply-batch cannot be combined with --record-ops or --capture-keys, which only see individual writes")
+	}
+	if cfg.SnapshotReaders > 0 && !caps.SupportsSnapshot {
+		return nil, fmt.Errorf("--snapshot-readers needs snapshots, which the %s backend does not support", cfg.DatabaseType)
+	}
+	if _, ok := dbConn.(Snapshotter); cfg.SnapshotReaders > 0 && !ok {
+		return nil, fmt.Errorf("--snapshot-readers cannot be combined with --record-ops or --capture-keys, which only see database reads")
+	}
+	if _, ok := workload.(DeletionReporter); ok && cfg.WriteEnabled && !caps.SupportsDelete {
+		return nil, fmt.Errorf("workload %s deletes keys, which the %s backend does not support", workload.Name(), cfg.DatabaseType)
+	}
+	if cfg.TombstoneScan && (!caps.SupportsIterator || !caps.SupportsDelete) {
+		return nil, fmt.Errorf("tombstone scan needs deletes and iterators, which the %s backend does not support", cfg.DatabaseType)
+	}
+	if cfg.RangeQueries > 0 && !caps.SupportsIterator {
+		log.Warn().Str("database", cfg.DatabaseType).Msg("Backend does not support iterators, skipping the range query phase")
+		cfg.RangeQueries = 0
+	}
+
+	if cfg.MetricsInterval > 0 {
+		stopSampler := startMetricsSampler(dbConn, cfg.MetricsInterval)
+		defer stopSampler()
+	}
+
+	result := &BenchmarkResult{
+		BenchmarkID: cfg.BenchmarkID,
+		Backend:     cfg.DatabaseType,
+		Workload:    workload.Name(),
+		KeyCount:    cfg.KeyCount,
+		NumCPU:      runtime.NumCPU(),
+		GOMAXPROCS:  runtime.GOMAXPROCS(0),
+
+		BackendConfig: backendConfig,
+	}
+	if result.Backend == "" {
+		result.Backend = string(DatabaseTypePebble)
+	}
+
+	if cfg.ReplayOps != "" {
+		replayResult, err := replayOps(dbConn, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPhaseErrors(cfg, replayResult); err != nil {
+			return nil, err
+		}
+		log.Info().Str("benchmark_id", cfg.BenchmarkID).Msg("Benchmark complete")
+		return result, nil
+	}
+
+	if replayCaptured {
+		writeResult, readResult, err := replayKeyOps(dbConn, cfg)
+		if err != nil {
+			return nil, err
+		}
+		logWriteResult(writeResult)
+		logReadResult(readResult)
+		for _, r := range []*PhaseResult{writeResult, readResult} {
+			if err := checkPhaseErrors(cfg, r); err != nil {
+				return nil, err
+			}
+		}
+		result.setWrite(writeResult)
+		result.setRead(readResult)
+		log.Info().Str("benchmark_id", cfg.BenchmarkID).Msg("Benchmark complete")
+		return result, nil
+	}
+
+	var export *latencyCSV
+	if cfg.LatencyCSV != "" {
+		if export, err = startLatencyCSV(cfg.LatencyCSV); err != nil {
+			return nil, err
+		}
+	}
+
+	var histograms []*hdrhistogram.Histogram
 	var keys iter.Seq[[]byte]
 	if cfg.WriteEnabled {
-		log.Info().Msg("Generating keys for write mode")
-		keys = workload.GenerateKeys(cfg.Seed, cfg.KeyCount)
-		if err := runWritePhase(dbConn, cfg, keys, workload); err != nil {
-			return err
+		writeKeys, writeWorkload := workload.GenerateKeys(cfg.Seed, cfg.KeyCount), workload
+		if cfg.GeneratorWorkers > 1 && cfg.Populate == 0 {
+			// The first generator is the run's own workload, so its stats and
+			// state still describe the keys it generated
+			generators := []Workload{workload}
+			for len(generators) < cfg.GeneratorWorkers {
+				generator, err := newWorkload()
+				if err != nil {
+					return nil, err
+				}
+				generators = append(generators, generator)
+			}
+			log.Info().Int("generators", len(generators)).Msg("Generating write keys in parallel")
+			writeKeys = parallelKeys(generators, cfg.Seed, cfg.KeyCount)
+		}
+		if cfg.LoadDataset != "" {
+			log.Info().Str("path", cfg.LoadDataset).Msg("Loading key/value pairs from dataset")
+			writeKeys = datasetKeys(cfg.LoadDataset)
+			keys = writeKeys
+		} else if cfg.Populate > 0 {
+			// Population is independent of the workload, which only shapes the reads
+			log.Info().Int("keys", cfg.Populate).Msg("Populating index-addressed keys")
+			writeKeys, writeWorkload = populatedKeys(cfg.Seed, cfg.Populate), NewGenericWorkload(workloadCfg)
+			keys = restrictToPopulated(workload.GenerateKeys(cfg.Seed, cfg.KeyCount), cfg.Seed, cfg.Populate)
+		} else {
+			log.Info().Msg("Generating keys for write mode")
+			keys = writeKeys
+			if generator, ok := workload.(ReadKeyGenerator); ok {
+				keys = generator.GenerateReadKeys(cfg.Seed, cfg.KeyCount)
+			}
+		}
+		if cfg.SortKeys {
+			// Reads keep the generated order
+			writeKeys = sortWriteKeys(writeKeys, max(cfg.KeyCount, cfg.Populate))
+		}
+		writeResult, err := runWritePhase(dbConn, cfg, writeKeys, writeWorkload, export)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.L0FilesTarget > 0 || cfg.MaxBytesWritten > 0 {
+			// Only the keys written before the target or byte limit was reached
+			// can be read back
+			keys = takeKeys(keys, int(writeResult.Ops))
+		}
+		logWriteResult(writeResult)
+		if err := checkPhaseErrors(cfg, writeResult); err != nil {
+			return nil, err
+		}
+		if err := checkLatencyBudget(cfg, writeResult); err != nil {
+			return nil, err
+		}
+		if cfg.SyncWrites {
+			logWALMetrics(dbConn.GetMetrics(), writeResult.TotalLatency)
 		}
+		if cfg.MeasureGeneration {
+			logGenerationSplit("write", writeResult.KeyGenTime, writeResult.ValueGenTime, writeResult.TotalLatency)
+			logValueGenLatency(writeResult)
+		}
+		result.setWrite(writeResult)
+		histograms = append(histograms, writeResult.Histogram)
+		if fillDisk != nil {
+			fillDisk.report(cfg.DBPath)
+		}
+
+		if reporter, ok := workload.(StatsReporter); ok && cfg.Populate == 0 && cfg.LoadDataset == "" {
+			log.Info().Fields(reporter.Stats()).Msg("Workload key statistics")
+		}
+
+		if cfg.FlushBetweenPhases {
+			if result.QuiesceTime, err = settleDatabase(dbConn, cfg); err != nil {
+				return nil, err
+			}
+		}
+	} else if cfg.UseExistingDB {
+		samplePath := cfg.DBPath
+		if separateReadDB {
+			samplePath = cfg.ReadDBPath
+		}
+		log.Info().Str("path", samplePath).Int("sample_size", cfg.KeyCount).Msg("Sampling keys from existing database")
+		sample, err := sampleKeysFromDatabase(readDB, cfg.KeyCount, cfg.Seed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample existing keys: %w", err)
+		}
+		keys = slices.Values(sample)
 	} else {
 		if cfg.KeysFile != "" {
 			log.Info().Str("path", cfg.KeysFile).Msg("Loading keys from file")
-			keys = loadKeysFromFile(cfg.KeysFile)
+			keys = loadKeysFromFile(cfg.KeysFile, cfg.KeysFormat)
 		} else {
 			log.Info().Msg("Loading keys from standard input")
-			keys = loadKeysFromStdin()
+			keys = loadKeysFromStdin(cfg.KeysFormat)
 		}
 	}
 
-	if err := runReadPhase(dbConn, cfg, keys, workload); err != nil {
-		return err
+	if cfg.L0FilesTarget > 0 {
+		log.Info().Int64("l0_files", readDB.GetMetrics().L0FileCount).Msg("L0 files at read start")
+	}
+
+	if cfg.WarmHotKeys {
+		if result.WarmUpTime, err = warmHotKeys(readDB, workload.(HotKeyReporter)); err != nil {
+			return nil, err
+		}
+	}
+
+	keys = orderReadKeys(keys, cfg.ReadOrder, readOrderSeed)
+	copier, _ := readDB.(ValueCopyReporter)
+	var copiesBefore uint64
+	var copyTimeBefore time.Duration
+	if copier != nil {
+		copiesBefore, copyTimeBefore = copier.ValueCopyStats()
+	}
+	cacheBefore := readDB.GetMetrics()
+	readResult, err := runReadPhase(readDB, cfg, keys, workload, export)
+	if err != nil {
+		return nil, err
+	}
+	result.CacheHitRatio = readCacheHitRatio(cacheBefore, readDB.GetMetrics())
+	if copier != nil && cfg.PebbleCopyValues {
+		copies, copyTime := copier.ValueCopyStats()
+		logValueCopyCost(copies-copiesBefore, copyTime-copyTimeBefore, readResult.TotalLatency)
+	}
+	if export != nil {
+		if err := export.close(); err != nil {
+			return nil, err
+		}
+		log.Info().Str("path", cfg.LatencyCSV).Msg("Wrote per-operation latency CSV")
+	}
+	logReadResult(readResult)
+	if reporter, ok := workload.(ReadStatsReporter); ok && cfg.WriteEnabled && cfg.Populate == 0 && cfg.LoadDataset == "" {
+		log.Info().Fields(reporter.ReadStats(readResult)).Msg("Workload read statistics")
+	}
+	if err := checkPhaseErrors(cfg, readResult); err != nil {
+		return nil, err
+	}
+	if err := checkLatencyBudget(cfg, readResult); err != nil {
+		return nil, err
+	}
+	if cfg.MeasureGeneration {
+		logGenerationSplit("read", readResult.KeyGenTime, 0, readResult.TotalLatency)
+	}
+	result.setRead(readResult)
+	histograms = append(histograms, readResult.Histogram)
+
+	// Random order reads some keys twice and others never, so only the
+	// sequential and shuffled orders can reproduce the write checksum, and
+	// only when the reads are the written keys rather than a populated keyspace,
+	// read back from the database they were written to
+	if cfg.VerifyChecksums && cfg.WriteEnabled && cfg.Populate == 0 && cfg.ReadOrder != ReadOrderRandom && !separateReadDB {
+		if result.WriteChecksum != result.ReadChecksum {
+			log.Warn().
+				Hex("write_checksum", binary.BigEndian.AppendUint64(nil, result.WriteChecksum)).
+				Hex("read_checksum", binary.BigEndian.AppendUint64(nil, result.ReadChecksum)).
+				Msg("Read-back checksum does not match the written data")
+		} else {
+			log.Info().Msg("Read-back checksum matches the written data")
+		}
+	}
+
+	if cfg.Mixed {
+		mixedRead, mixedWrite, err := runMixedPhase(dbConn, cfg, keys, workload)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range []*PhaseResult{mixedRead, mixedWrite} {
+			if err := checkPhaseErrors(cfg, r); err != nil {
+				return nil, err
+			}
+		}
+		histograms = append(histograms, mixedRead.Histogram, mixedWrite.Histogram)
+	}
+
+	if cfg.RangeQueries > 0 {
+		rangeHists, err := runRangePhase(dbConn, cfg, workload)
+		if err != nil {
+			return nil, err
+		}
+		histograms = append(histograms, rangeHists...)
+	}
+
+	if cfg.HDROutput != "" {
+		if err := writeHDRLog(cfg.HDROutput, histograms); err != nil {
+			return nil, err
+		}
+		log.Info().Str("path", cfg.HDROutput).Msg("Wrote HDR latency histograms")
+	}
+
+	if cfg.FreshnessProbe {
+		if err := runFreshnessPhase(dbConn, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.TombstoneScan {
+		if err := runTombstoneScanPhase(dbConn, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.SnapshotReaders > 0 {
+		snapshotResult, err := runSnapshotPhase(dbConn, cfg, keys)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPhaseErrors(cfg, snapshotResult); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Contention {
+		contentionResult, err := runContentionPhase(dbConn, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPhaseErrors(cfg, contentionResult); err != nil {
+			return nil, err
+		}
+	}
+
+	result.WriteStalls, result.WriteStallTime = reportWriteStalls(dbConn.GetMetrics())
+
+	if cfg.Summary || cfg.ResultsDB != "" || cfg.ResultsFile != "" {
+		metrics := dbConn.GetMetrics()
+		result.CacheHits = metrics.CacheHits
+		result.CacheMisses = metrics.CacheMisses
+		if size, err := directorySize(cfg.DBPath); err != nil {
+			log.Warn().Err(err).Msg("Failed to measure database disk size")
+		} else {
+			result.DiskSizeBytes = size
+		}
+	}
+
+	if cfg.Summary {
+		if err := printSummary(os.Stdout, *result); err != nil {
+			return nil, fmt.Errorf("failed to print summary: %w", err)
+		}
+	}
+
+	if cfg.ResultsDB != "" {
+		if err := recordResult(cfg.ResultsDB, cfg, result); err != nil {
+			return nil, err
+		}
+		log.Info().Str("path", cfg.ResultsDB).Msg("Recorded result in results database")
+	}
+	if cfg.ResultsFile != "" {
+		if err := writeResultsFile(cfg.ResultsFile, result); err != nil {
+			return nil, err
+		}
+		log.Info().Str("path", cfg.ResultsFile).Msg("Wrote results file")
 	}
 
 	log.Info().Str("benchmark_id", cfg.BenchmarkID).Msg("Benchmark complete")
-	return nil
+	return result, nil
 }
 
 func initialLog(cfg Config) {
@@ -164,12 +1160,40 @@ func initialLog(cfg Config) {
 		Str("db_path", cfg.DBPath).
 		Bool("write_enabled", cfg.WriteEnabled).
 		Str("keys_file", cfg.KeysFile).
+		Bool("use_existing_db", cfg.UseExistingDB).
 		Int("concurrency", cfg.Concurrency).
 		Str("block_cache", blockCacheInfo).
+		Int("num_cpu", runtime.NumCPU()).
+		Int("gomaxprocs", runtime.GOMAXPROCS(0)).
+		Bool("lock_os_thread", cfg.LockOSThread).
 		Msg("Starting benchmark")
 }
 
+// logLevels maps the --log-level names to zerolog levels
+var logLevels = map[string]zerolog.Level{
+	"debug": zerolog.DebugLevel,
+	"info":  zerolog.InfoLevel,
+	"warn":  zerolog.WarnLevel,
+	"error": zerolog.ErrorLevel,
+}
+
+// opLogSampleEvery is how many operations pass between the per-operation
+// lines logged at debug level
+const opLogSampleEvery = 1000
+
 func setupLog(cfg Config) {
+	level, ok := logLevels[strings.ToLower(cfg.LogLevel)]
+	if !ok {
+		level = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(level)
+
+	if cfg.Quiet {
+		// Keep standard output for the result line
+		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+		log.Logger = log.Output(os.Stderr)
+		return
+	}
 	if strings.ToLower(cfg.LogFormat) == "json" {
 		zerolog.TimeFieldFormat = time.RFC3339Nano
 		log.Logger = log.Output(os.Stdout)
@@ -185,11 +1209,34 @@ func createDatabase(cfg Config) (Database, error) {
 		dbType = DatabaseTypePebble
 	}
 
+	if cfg.PebbleComparer != "" && cfg.PebbleComparer != PebbleComparerDefault && dbType != DatabaseTypePebble {
+		return nil, fmt.Errorf("--pebble-comparer %s requires the pebble backend", cfg.PebbleComparer)
+	}
+	if cfg.FlushWaitCompaction && dbType != DatabaseTypePebble {
+		return nil, fmt.Errorf("--flush-wait-compaction requires the pebble backend")
+	}
+	if cfg.KeyPrefix != "" && cfg.PebbleComparer != "" && cfg.PebbleComparer != PebbleComparerDefault {
+		return nil, fmt.Errorf("--key-prefix requires the default comparer, --pebble-comparer %s orders keys by their leading bytes", cfg.PebbleComparer)
+	}
+	if (cfg.PebbleMaxOpenFiles > 0 || cfg.PebbleTableCacheSize > 0) && dbType != DatabaseTypePebble {
+		return nil, fmt.Errorf("--pebble-max-open-files and --pebble-table-cache-size require the pebble backend")
+	}
+	if cfg.PebbleDisableWAL {
+		if dbType != DatabaseTypePebble {
+			return nil, fmt.Errorf("--pebble-disable-wal requires the pebble backend")
+		}
+		if cfg.SyncWrites {
+			return nil, fmt.Errorf("--pebble-disable-wal cannot be combined with --sync-writes")
+		}
+	}
+
 	dbCfg := DatabaseConfig{
 		Type:           dbType,
 		Path:           cfg.DBPath,
 		ReadOnly:       !cfg.WriteEnabled,
+		SyncWrites:     cfg.SyncWrites,
 		BlockCacheSize: cfg.BlockCacheSize,
+		PebbleComparer: cfg.PebbleComparer,
 		QMDBConfig: QMDBConfig{
 			LibraryPath: cfg.QMDBLibraryPath,
 		},
@@ -202,122 +1249,384 @@ func createDatabase(cfg Config) (Database, error) {
 			WriteMap:    cfg.MDBXWriteMap,
 			NoReadahead: cfg.MDBXNoReadahead,
 		},
+		PebbleDisableWAL: cfg.PebbleDisableWAL,
+		PebbleCopyValues: cfg.PebbleCopyValues,
+
+		PebbleMaxOpenFiles:   cfg.PebbleMaxOpenFiles,
+		PebbleTableCacheSize: cfg.PebbleTableCacheSize,
+
+		PebbleFlushWaitCompaction: cfg.FlushWaitCompaction,
+		FlushSettleTimeout:        quiesceTimeout(cfg),
 	}
 
-	return NewDatabase(dbCfg)
+	db, err := NewDatabase(dbCfg)
+	if err != nil {
+		return nil, err
+	}
+	if config := effectiveConfig(db); config != nil {
+		log.Info().Str("database", string(dbType)).Fields(config).Msg("Database configuration")
+	}
+	if cfg.KeyPrefix == "" {
+		return db, nil
+	}
+	log.Info().Str("prefix", cfg.KeyPrefix).Msg("Confining keys to namespace prefix")
+	return NewPrefixedDatabase(db, []byte(cfg.KeyPrefix)), nil
 }
 
 // runWritePhase concurrently writes keys to database using iterator
-func runWritePhase(db Database, cfg Config, keys iter.Seq[[]byte], workload Workload) error {
+// and returns the phase measurements
+func runWritePhase(db Database, cfg Config, keys iter.Seq[[]byte], workload Workload, export *latencyCSV) (*PhaseResult, error) {
 	log.Info().Int("workers", cfg.Concurrency).Msg("Beginning write loop")
 
-	jobs := make(chan []byte, cfg.KeyCount)
-	writeTimeHistory := make(chan time.Duration, cfg.KeyCount)
+	// Block boundaries only follow the key order of a single, unsorted generator
+	var blocks BlockBoundaryReporter
+	if (cfg.ApplyBatch > 0 || cfg.BlockTime > 0) && cfg.GeneratorWorkers <= 1 && !cfg.SortKeys && cfg.LoadDataset == "" {
+		blocks, _ = workload.(BlockBoundaryReporter)
+	}
+	deletes, _ := workload.(DeletionReporter)
+	observer, _ := workload.(WriteObserver)
+	writeJobs := keysToJobs(keys, blocks, deletes)
+	if cfg.LoadDataset != "" {
+		// The dataset carries its values, written as they are
+		writeJobs = loadDataset(cfg.LoadDataset)
+	}
+	if cfg.PregenerateValues {
+		log.Info().Msg("Pregenerating values before write loop")
+		writeJobs = pregenerateJobs(cfg, writeJobs, workload)
+	}
+	batches := newApplyBatcher(db, cfg.ApplyBatch)
+	if batches != nil {
+		log.Info().Int("max_writes", cfg.ApplyBatch).Bool("block_boundaries", blocks != nil).Msg("Writing through shared batches")
+	}
+
+	opLog := log.Sample(&zerolog.BasicSampler{N: opLogSampleEvery})
+	depth := queueDepth(cfg)
+	jobs := make(chan writeJob, depth)
+	writeTimeHistory := make(chan time.Duration, depth)
+	collector := startLatencyCollector("write", writeTimeHistory)
+	deleteTimeHistory := make(chan time.Duration, depth)
+	deleteCollector := startLatencyCollector("delete", deleteTimeHistory)
 	var wg sync.WaitGroup
-	var failed, successful uint64
+	var failed, successful, deleted, valueBytes uint64
+	var logicalBytes atomic.Uint64 // key and value bytes written
+	var keyGenNanos, valueGenNanos int64
+	var checksum kvChecksum
+	var errs errorSampler
+	var backpressure feederBackpressure
+	valueSizes := newValueSizeHistogram()
+	valueGenLatencies := newGenerationHistogram()
+	shape := newValueShape(cfg)
 
-	// Feed keys to workers
+	phaseStart := phaseClock.Now()
+	l0 := startL0Watcher(db, cfg.L0FilesTarget)
+	written := newByteLimit("write", cfg.MaxBytesWritten)
+	spaceAmp := startSpaceAmpSampler(db, cfg.DBPath, cfg.SpaceAmpInterval, &logicalBytes)
+	curve := newFillCurve(cfg.FillCurve, cfg.FillCurveBucket)
+
+	// settle counts a write once it is persisted, which under --apply-batch is
+	// when its batch is applied
+	settle := func(write batchedWrite, err error, sizes *hdrhistogram.Histogram, sum *uint64) {
+		if err != nil {
+			atomic.AddUint64(&failed, 1)
+			errs.record(err)
+			return
+		}
+		writes := atomic.AddUint64(&successful, 1)
+		if observer != nil {
+			observer.ObserveWrite(write.key)
+		}
+		atomic.AddUint64(&valueBytes, uint64(write.size))
+		written.add(write.size)
+		curve.observe(logicalBytes.Add(uint64(len(write.key)+write.size)), writes)
+		sizes.RecordValue(int64(write.size))
+		*sum ^= write.checksum
+	}
+
+	// Feed keys to workers, pacing whole blocks under --block-time
+	limiter := newRateLimiter(cfg.TargetOpsPerSec)
+	var pacer *blockPacer
+	if blocks != nil {
+		pacer = newBlockPacer(cfg.BlockTime)
+	}
 	go func() {
-		for key := range keys {
-			jobs <- key
+		pacer.begin()
+		genStart := phaseClock.Now()
+		for job := range writeJobs {
+			if l0.reachedTarget() || written.reachedLimit() {
+				break
+			}
+			if cfg.MeasureGeneration {
+				atomic.AddInt64(&keyGenNanos, int64(phaseClock.Since(genStart)))
+			}
+			job.scheduled = limiter.wait()
+			pacer.track(&job)
+			sendJob(jobs, job, &backpressure)
+			if job.endsBlock {
+				pacer.endBlock()
+			}
+			genStart = phaseClock.Now()
 		}
 		close(jobs)
 	}()
 
-	// this interval is required to ensure the channel is ready before workers start
-	time.Sleep(time.Second)
-
 	// Start workers
 	for w := 0; w < cfg.Concurrency; w++ {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-
-			// if there is no key to read, just return
-			if len(jobs) == 0 {
-				return
+			if cfg.LockOSThread {
+				runtime.LockOSThread()
+				defer runtime.UnlockOSThread()
 			}
 
 			rng := rand.New(rand.NewSource(cfg.Seed + int64(workerID)))
-			for key := range jobs {
-				value := workload.GenerateValue(rng, key)
+			var workerChecksum uint64
+			defer func() { checksum.add(workerChecksum) }()
+			workerSizes := valueSizes.worker()
+			defer valueSizes.merge(workerSizes)
+			workerGenLatencies := valueGenLatencies.worker()
+			defer valueGenLatencies.merge(workerGenLatencies)
 
-				writeStart := time.Now()
-				err := db.Set(key, value)
-				writeTimeHistory <- time.Since(writeStart)
-
-				if err != nil {
-					atomic.AddUint64(&failed, 1)
+			for job := range jobs {
+				if job.delete {
+					deleteStart := phaseClock.Now()
+					err := db.Delete(job.key)
+					deleteLatency := phaseClock.Since(startedAt(job.scheduled, deleteStart))
+					deleteTimeHistory <- deleteLatency
+					export.record("delete", deleteLatency, len(job.key), 0, err)
+					job.done()
+					if err != nil {
+						atomic.AddUint64(&failed, 1)
+						errs.record(err)
+						continue
+					}
+					atomic.AddUint64(&deleted, 1)
 					continue
 				}
-				atomic.AddUint64(&successful, 1)
+
+				value := job.value
+				if value == nil {
+					valueStart := phaseClock.Now()
+					value = generateWorkloadValue(rng, workload, job.key, shape)
+					if cfg.MeasureGeneration {
+						valueGen := phaseClock.Since(valueStart)
+						atomic.AddInt64(&valueGenNanos, int64(valueGen))
+						workerGenLatencies.RecordValue(int64(valueGen))
+					}
+				}
+
+				write := batchedWrite{key: job.key, size: len(value)}
+				if cfg.VerifyChecksums {
+					write.checksum = pairChecksum(job.key, value)
+				}
+				settled := []batchedWrite{write}
+
+				writeStart := phaseClock.Now()
+				var err error
+				if batches != nil {
+					settled, err = batches.set(write, value, job.endsBlock)
+				} else {
+					err = db.Set(job.key, value)
+				}
+				writeLatency := phaseClock.Since(startedAt(job.scheduled, writeStart))
+				job.done()
+				writeTimeHistory <- writeLatency
+				export.record("write", writeLatency, len(job.key), len(value), err)
+				opLog.Debug().Int("worker", workerID).Hex("key", job.key).Int("value_size", len(value)).
+					Dur("latency", writeLatency).Err(err).Msg("Write")
+
+				for _, write := range settled {
+					settle(write, err, workerSizes, &workerChecksum)
+				}
 			}
 		}(w)
 	}
 
 	// Collect results
 	wg.Wait()
+	if batches != nil {
+		sizes := valueSizes.worker()
+		var sum uint64
+		settled, err := batches.close()
+		for _, write := range settled {
+			settle(write, err, sizes, &sum)
+		}
+		valueSizes.merge(sizes)
+		checksum.add(sum)
+	}
+	elapsed := phaseClock.Since(phaseStart)
 	close(writeTimeHistory)
-
-	var totalWriteTime time.Duration
-	for writeTime := range writeTimeHistory {
-		totalWriteTime += writeTime
+	collector.wait()
+	close(deleteTimeHistory)
+	deleteCollector.wait()
+	if n := atomic.LoadUint64(&deleted); n > 0 {
+		log.Info().
+			Uint64("deletes", n).
+			Float64("delete_p50_latency_ms", deleteCollector.percentileMs(50)).
+			Float64("delete_p99_latency_ms", deleteCollector.percentileMs(99)).
+			Msg("Write phase deletes")
+	}
+	if cfg.L0FilesTarget > 0 && !l0.close() {
+		log.Warn().
+			Int("target", cfg.L0FilesTarget).
+			Int64("l0_files", db.GetMetrics().L0FileCount).
+			Msg("Wrote every key without reaching the L0 file target")
 	}
+	written.report(elapsed)
+	pacer.report()
 
-	elapsed := totalWriteTime.Seconds()
-	ops := float64(cfg.KeyCount) / elapsed
-	avg := float64(totalWriteTime.Microseconds()) / 1000.0 / float64(cfg.KeyCount)
+	result := newPhaseResult("write", collector, elapsed)
+	result.Successful = atomic.LoadUint64(&successful)
+	result.Failed = atomic.LoadUint64(&failed)
+	result.FirstError = errs.first
+	result.Errors = errs.samples()
+	result.OtherErrors = errs.other
+	result.KeyGenTime = time.Duration(atomic.LoadInt64(&keyGenNanos))
+	result.ValueGenTime = time.Duration(atomic.LoadInt64(&valueGenNanos))
+	result.ValueGenP99 = time.Duration(valueGenLatencies.hist.ValueAtQuantile(99))
+	result.ValueBytes = atomic.LoadUint64(&valueBytes)
+	result.ValueSizes = valueSizes.hist
+	result.Checksum = checksum.sum
 
-	log.Info().
-		Dur("total_elapsed", totalWriteTime).
-		Uint64("failed_writes", atomic.LoadUint64(&failed)).
-		Uint64("successful_writes", atomic.LoadUint64(&successful)).
-		Float64("ops_per_sec", ops).
-		Float64("avg_latency_ms", avg).
-		Msg("Write benchmark complete")
+	backpressure.log("write", depth)
+	if err := curve.finish(logicalBytes.Load(), result.Successful); err != nil {
+		return nil, err
+	}
 
+	flushStart := phaseClock.Now()
 	if err := db.Flush(); err != nil {
 		log.Error().Err(err).Msg("Flush failed")
-		return err
+		return nil, err
 	}
-	return nil
+	result.FlushTime = phaseClock.Since(flushStart)
+	spaceAmp.close()
+	return result, nil
+}
+
+// defaultQuiesceTimeout bounds how long settleDatabase waits for background
+// work when --quiesce-timeout is not set
+const defaultQuiesceTimeout = 10 * time.Minute
+
+// quiesceTimeout returns cfg.QuiesceTimeout, or the default when unset
+func quiesceTimeout(cfg Config) time.Duration {
+	if cfg.QuiesceTimeout <= 0 {
+		return defaultQuiesceTimeout
+	}
+	return cfg.QuiesceTimeout
+}
+
+// settleDatabase flushes db and waits for its background work to drain so the
+// next phase runs against a settled database. It returns the time to quiesce,
+// which measures the write debt the previous phase left behind, or 0 when the
+// database did not settle within the timeout.
+func settleDatabase(db Database, cfg Config) (time.Duration, error) {
+	timeout := quiesceTimeout(cfg)
+
+	flushStart := time.Now()
+	if err := db.Flush(); err != nil {
+		return 0, fmt.Errorf("flush between phases failed: %w", err)
+	}
+	flushTime := time.Since(flushStart)
+
+	before := db.GetMetrics()
+	settleStart := time.Now()
+	settled := true
+	if q, ok := db.(Quiescer); ok {
+		var err error
+		if settled, err = q.WaitForQuiesce(timeout); err != nil {
+			return 0, fmt.Errorf("waiting for database to settle failed: %w", err)
+		}
+	}
+	quiesceTime := time.Since(settleStart)
+	after := db.GetMetrics()
+
+	event := log.Info()
+	if !settled {
+		event = log.Warn().Dur("timeout", timeout)
+	}
+	event.
+		Float64("flush_ms", durationMs(flushTime)).
+		Float64("quiesce_ms", durationMs(quiesceTime)).
+		Int64("compactions", after.CompactionOps-before.CompactionOps).
+		Int64("l0_files_before", before.L0FileCount).
+		Int64("l0_files_after", after.L0FileCount).
+		Bool("settled", settled).
+		Msg("Flushed and settled database between phases")
+	if !settled {
+		return 0, nil
+	}
+	return quiesceTime, nil
+}
+
+// readJob is one key for a read worker
+type readJob struct {
+	key       []byte
+	scheduled time.Time // start the rate limiter scheduled, zero without one
 }
 
 // runReadPhase concurrently reads keys from database using iterator
-func runReadPhase(db Database, cfg Config, keys iter.Seq[[]byte], workload Workload) error {
+// and returns the phase measurements
+func runReadPhase(db Database, cfg Config, keys iter.Seq[[]byte], workload Workload, export *latencyCSV) (*PhaseResult, error) {
 	log.Info().Int("workers", cfg.Concurrency).Msg("Beginning read loop")
 
-	channelBufferSize := cfg.Concurrency * 2
+	opLog := log.Sample(&zerolog.BasicSampler{N: opLogSampleEvery})
+	depth := queueDepth(cfg)
 
-	jobs := make(chan []byte, channelBufferSize)
-	readTimeHistory := make(chan time.Duration, channelBufferSize)
+	jobs := make(chan readJob, depth)
+	readTimeHistory := make(chan time.Duration, depth)
+	collector := startLatencyCollector("read", readTimeHistory)
+	classes := startClassCollectors("read", workload, depth)
 	var wg sync.WaitGroup
 	var totalReads, notFound, failed, successful uint64
+	var keyGenNanos int64
+	var checksum kvChecksum
+	var errs errorSampler
+	var backpressure feederBackpressure
+
+	phaseStart := phaseClock.Now()
+	read := newByteLimit("read", cfg.MaxBytesRead)
 
 	// Feed keys to workers
+	limiter := newRateLimiter(cfg.TargetOpsPerSec)
 	go func() {
+		genStart := phaseClock.Now()
 		for key := range keys {
-			jobs <- key
+			if read.reachedLimit() {
+				break
+			}
+			if cfg.MeasureGeneration {
+				atomic.AddInt64(&keyGenNanos, int64(phaseClock.Since(genStart)))
+			}
+			scheduled := limiter.wait()
+			sendJob(jobs, readJob{key: key, scheduled: scheduled}, &backpressure)
+			genStart = phaseClock.Now()
 		}
 		close(jobs)
 	}()
 
-	time.Sleep(time.Second) // ensure channel is ready before workers start
-
 	for w := 0; w < cfg.Concurrency; w++ {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-
-			// if there is no key to read, just return
-			if len(jobs) == 0 {
-				return
+			if cfg.LockOSThread {
+				// MDBX read transactions stay on one thread instead of each
+				// Get locking and unlocking one
+				runtime.LockOSThread()
+				defer runtime.UnlockOSThread()
 			}
+			var workerChecksum uint64
+			defer func() { checksum.add(workerChecksum) }()
 
-			for key := range jobs {
-				readStart := time.Now()
-				_, closer, err := db.Get(key)
-				readTimeHistory <- time.Since(readStart)
+			for job := range jobs {
+				key := job.key
+				readStart := phaseClock.Now()
+				value, closer, err := db.Get(key)
+				readLatency := phaseClock.Since(startedAt(job.scheduled, readStart))
+				readTimeHistory <- readLatency
+				classes.record(key, readLatency)
+				export.record("read", readLatency, len(key), len(value), err)
+				opLog.Debug().Int("worker", workerID).Hex("key", key).Int("value_size", len(value)).
+					Dur("latency", readLatency).Err(err).Msg("Read")
 
 				atomic.AddUint64(&totalReads, 1)
 
@@ -326,9 +1635,14 @@ func runReadPhase(db Database, cfg Config, keys iter.Seq[[]byte], workload Workl
 						atomic.AddUint64(&notFound, 1)
 					} else {
 						atomic.AddUint64(&failed, 1)
+						errs.record(err)
 					}
 					continue
 				}
+				if cfg.VerifyChecksums {
+					workerChecksum ^= pairChecksum(key, value)
+				}
+				read.add(len(value))
 				if closer != nil {
 					closer.Close()
 				}
@@ -337,14 +1651,6 @@ func runReadPhase(db Database, cfg Config, keys iter.Seq[[]byte], workload Workl
 		}(w)
 	}
 
-	// Summarize read times
-	var totalReadTime time.Duration
-	go func() {
-		for readTime := range readTimeHistory {
-			totalReadTime += readTime
-		}
-	}()
-
 	// print progress every second while workers are running
 	chDone := make(chan struct{})
 	go func() {
@@ -361,30 +1667,239 @@ func runReadPhase(db Database, cfg Config, keys iter.Seq[[]byte], workload Workl
 	}()
 
 	wg.Wait()
+	elapsed := phaseClock.Since(phaseStart)
 	close(readTimeHistory)
 	chDone <- struct{}{}
+	collector.wait()
 
-	elapsed := totalReadTime.Seconds()
-	read_ops_per_sec := float64(0)
-	if elapsed > 0 {
-		read_ops_per_sec = float64(atomic.LoadUint64(&totalReads)) / elapsed
+	read.report(elapsed)
+
+	result := newPhaseResult("read", collector, elapsed)
+	result.ByClass = classes.results(elapsed)
+	result.Successful = atomic.LoadUint64(&successful)
+	result.Failed = atomic.LoadUint64(&failed)
+	result.FirstError = errs.first
+	result.Errors = errs.samples()
+	result.OtherErrors = errs.other
+	result.NotFound = atomic.LoadUint64(&notFound)
+	result.KeyGenTime = time.Duration(atomic.LoadInt64(&keyGenNanos))
+	result.Checksum = checksum.sum
+
+	backpressure.log("read", depth)
+
+	return result, nil
+}
+
+// kvChecksum accumulates order-independent checksums from several workers
+type kvChecksum struct {
+	mu  sync.Mutex
+	sum uint64
+}
+
+// add folds one worker's XORed pair checksums into the total
+func (c *kvChecksum) add(sum uint64) {
+	c.mu.Lock()
+	c.sum ^= sum
+	c.mu.Unlock()
+}
+
+// pairChecksum hashes a key/value pair; XOR-ing the results makes the total
+// independent of the order pairs were processed in
+func pairChecksum(key, value []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(key)
+	h.Write([]byte{0})
+	h.Write(value)
+	return h.Sum64()
+}
+
+// defaultQueueDepthPerWorker sizes the job queue when --queue-depth is not set
+const defaultQueueDepthPerWorker = 64
+
+// queueDepth returns the capacity of the job queue shared by the workers
+func queueDepth(cfg Config) int {
+	if cfg.QueueDepth > 0 {
+		return cfg.QueueDepth
+	}
+	return max(cfg.Concurrency, 1) * defaultQueueDepthPerWorker
+}
+
+// feederBackpressure counts how often the feeder found the job queue full.
+// It is only written by the feeder goroutine and must be read after the
+// workers have drained the queue.
+type feederBackpressure struct {
+	blockedSends uint64
+	blockedTime  time.Duration
+	totalSends   uint64
+}
+
+// sendJob enqueues job, recording the time spent blocked when the queue is full
+func sendJob[T any](jobs chan<- T, job T, bp *feederBackpressure) {
+	bp.totalSends++
+	select {
+	case jobs <- job:
+		return
+	default:
+	}
+
+	blockedStart := time.Now()
+	jobs <- job
+	bp.blockedSends++
+	bp.blockedTime += time.Since(blockedStart)
+}
+
+// bottleneck names the side of the queue that limited the phase: the database
+// when the feeder blocked on a full queue for most sends, generation otherwise
+func (bp *feederBackpressure) bottleneck() string {
+	if bp.totalSends > 0 && bp.blockedSends*2 >= bp.totalSends {
+		return "database"
 	}
-	read_avg_latency_ms := float64(0)
-	if atomic.LoadUint64(&totalReads) > 0 {
-		read_avg_latency_ms = float64(totalReadTime.Microseconds()) / 1000.0 / float64(atomic.LoadUint64(&totalReads))
+	return "generation"
+}
+
+// log reports feeder backpressure. A blocked feeder means the workers are
+// the bottleneck; an unblocked feeder means key/value generation is.
+func (bp *feederBackpressure) log(phase string, depth int) {
+	log.Info().
+		Str("phase", phase).
+		Int("queue_depth", depth).
+		Bool("feeder_blocked", bp.blockedSends > 0).
+		Str("bottleneck", bp.bottleneck()).
+		Uint64("blocked_sends", bp.blockedSends).
+		Uint64("total_sends", bp.totalSends).
+		Dur("blocked_time", bp.blockedTime).
+		Msg("Feeder backpressure")
+}
+
+// logWALMetrics reports WAL append volume and fsync cost, separating log-append
+// time from the rest of the write path
+func logWALMetrics(metrics DatabaseMetrics, totalWriteTime time.Duration) {
+	if metrics.WALBytesIn == 0 {
+		log.Info().Msg("Backend does not report WAL metrics")
+		return
+	}
+
+	fsyncTime := time.Duration(metrics.WALFsyncCount) * metrics.WALFsyncLatency
+	fsyncPct := float64(0)
+	if totalWriteTime > 0 {
+		fsyncPct = float64(fsyncTime) / float64(totalWriteTime) * 100
 	}
 
 	log.Info().
-		Float64("read_ops_per_sec", read_ops_per_sec).
-		Float64("read_avg_latency_ms", read_avg_latency_ms).
-		Uint64("not_found", atomic.LoadUint64(&notFound)).
-		Uint64("failed_reads", atomic.LoadUint64(&failed)).
-		Uint64("successful_reads", atomic.LoadUint64(&successful)).
-		Uint64("total_reads", atomic.LoadUint64(&totalReads)).
-		Dur("read_total_elapsed", totalReadTime).
-		Msg("Read benchmark complete")
-
-	return nil
+		Uint64("wal_size", metrics.WALSize).
+		Uint64("wal_physical_size", metrics.WALPhysicalSize).
+		Uint64("wal_bytes_in", metrics.WALBytesIn).
+		Uint64("wal_bytes_written", metrics.WALBytesWritten).
+		Float64("wal_write_amp", float64(metrics.WALBytesWritten)/float64(metrics.WALBytesIn)).
+		Uint64("wal_fsyncs", metrics.WALFsyncCount).
+		Dur("wal_fsync_avg_latency", metrics.WALFsyncLatency).
+		Dur("wal_fsync_total", fsyncTime).
+		Float64("wal_fsync_pct_of_write_time", fsyncPct).
+		Msg("WAL metrics")
+}
+
+// reportWriteStalls logs the write stalls of the run and returns their count
+// and total duration, warning when writes stalled at all
+func reportWriteStalls(metrics DatabaseMetrics) (uint64, time.Duration) {
+	event := log.Info()
+	if metrics.WriteStallCount > 0 {
+		event = log.Warn()
+	}
+	event.
+		Uint64("write_stalls", metrics.WriteStallCount).
+		Float64("write_stall_ms", durationMs(metrics.WriteStallDuration)).
+		Msg("Write stalls")
+	return metrics.WriteStallCount, metrics.WriteStallDuration
+}
+
+// readCacheHitRatio logs and returns the cache hit ratio over the read phase,
+// from the metrics before and after it, or 0 when the backend keeps no cache stats
+func readCacheHitRatio(before, after DatabaseMetrics) float64 {
+	hits := after.CacheHits - before.CacheHits
+	misses := after.CacheMisses - before.CacheMisses
+	if hits+misses <= 0 {
+		return 0
+	}
+	ratio := float64(hits) / float64(hits+misses)
+	log.Info().
+		Int64("cache_hits", hits).
+		Int64("cache_misses", misses).
+		Float64("cache_hit_ratio", ratio).
+		Msg("Read phase cache hit ratio")
+	return ratio
+}
+
+// generationDominanceThreshold is the share of measured time spent generating
+// keys/values above which the benchmark is considered to measure the generator
+const generationDominanceThreshold = 0.5
+
+// logGenerationSplit reports how measured time divides between key generation,
+// value generation and database I/O, warning when generation dominates
+func logGenerationSplit(phase string, keyGen, valueGen, dbIO time.Duration) {
+	total := keyGen + valueGen + dbIO
+	if total <= 0 {
+		return
+	}
+
+	pct := func(d time.Duration) float64 {
+		return float64(d) / float64(total) * 100
+	}
+
+	log.Info().
+		Str("phase", phase).
+		Dur("key_generation", keyGen).
+		Dur("value_generation", valueGen).
+		Dur("db_io", dbIO).
+		Float64("key_generation_pct", pct(keyGen)).
+		Float64("value_generation_pct", pct(valueGen)).
+		Float64("db_io_pct", pct(dbIO)).
+		Msg("Generation vs database I/O time split")
+
+	if float64(keyGen+valueGen)/float64(total) > generationDominanceThreshold {
+		log.Warn().
+			Str("phase", phase).
+			Float64("generation_pct", pct(keyGen+valueGen)).
+			Msg("Key/value generation dominates measured time; results may reflect the generator rather than the database")
+	}
+}
+
+// logValueCopyCost reports the share of read latency spent copying values out
+// under --copy-values
+func logValueCopyCost(copies uint64, elapsed, readLatency time.Duration) {
+	if copies == 0 {
+		return
+	}
+	event := log.Info().
+		Uint64("copies", copies).
+		Dur("copy_total", elapsed).
+		Float64("copy_mean_ns", float64(elapsed.Nanoseconds())/float64(copies))
+	if readLatency > 0 {
+		event = event.Float64("copy_pct_of_read_latency", float64(elapsed)/float64(readLatency)*100)
+	}
+	event.Msg("Value copy cost")
+}
+
+// generatorBoundRatio is the fraction of the p99 write latency above which the
+// p99 of a single value generation marks the write phase as generator-bound
+const generatorBoundRatio = 0.5
+
+// logValueGenLatency reports the p99 of generating one value next to the p99
+// write latency. Workers generate each value just before writing it, so a slow
+// generator starves the database and its stalls look like database slowness.
+func logValueGenLatency(result *PhaseResult) {
+	log.Info().
+		Str("phase", result.Phase).
+		Float64("value_generation_p99_ms", durationMs(result.ValueGenP99)).
+		Float64("db_p99_latency_ms", durationMs(result.P99)).
+		Msg("Value generation latency")
+
+	if result.P99 > 0 && float64(result.ValueGenP99) >= generatorBoundRatio*float64(result.P99) {
+		log.Warn().
+			Str("phase", result.Phase).
+			Float64("value_generation_p99_ms", durationMs(result.ValueGenP99)).
+			Float64("db_p99_latency_ms", durationMs(result.P99)).
+			Msg("Value generation p99 approaches database p99 latency; the benchmark may be generator-bound")
+	}
 }
 
 // generateValue returns a random byte slice of specified size
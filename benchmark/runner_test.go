@@ -0,0 +1,303 @@
+package benchmark
+
+import (
+	"errors"
+	"io"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// quietLogs raises the log level for the duration of a test
+func quietLogs(t *testing.T) {
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	t.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+}
+
+func roundTripConfig(workload WorkloadType) Config {
+	return Config{
+		KeyCount:        2000,
+		ReadRatio:       0.7,
+		ValueSize:       64,
+		Seed:            42,
+		WriteEnabled:    true,
+		Concurrency:     4,
+		LogFormat:       "json",
+		BlockCacheSize:  -1,
+		VerifyChecksums: true,
+		DatabaseType:    string(DatabaseTypeMemory),
+		WorkloadType:    string(workload),
+		RecentBlockBias: 0.8,
+		BlockRange:      1000,
+	}
+}
+
+func TestRoundTripReadsBackWrittenKeys(t *testing.T) {
+	quietLogs(t)
+
+	for _, workload := range []WorkloadType{WorkloadGeneric, WorkloadPoSBlocks} {
+		t.Run(string(workload), func(t *testing.T) {
+			cfg := roundTripConfig(workload)
+
+			result, err := runBenchmark(cfg)
+			if err != nil {
+				t.Fatalf("runBenchmark: %v", err)
+			}
+
+			if result.WriteOps != uint64(cfg.KeyCount) || result.WriteFailed != 0 {
+				t.Errorf("wrote %d keys with %d failures, want %d and 0", result.WriteOps, result.WriteFailed, cfg.KeyCount)
+			}
+			if result.ReadOps != uint64(cfg.KeyCount) || result.ReadFailed != 0 {
+				t.Errorf("read %d keys with %d failures, want %d and 0", result.ReadOps, result.ReadFailed, cfg.KeyCount)
+			}
+			if rate := result.NotFoundRate(); rate > 0.001 {
+				t.Errorf("not-found rate %.4f, want ~0", rate)
+			}
+			if result.WriteChecksum == 0 || result.WriteChecksum != result.ReadChecksum {
+				t.Errorf("read checksum %x does not match write checksum %x", result.ReadChecksum, result.WriteChecksum)
+			}
+		})
+	}
+}
+
+func TestReadPhaseCountsMissingKeys(t *testing.T) {
+	quietLogs(t)
+
+	cfg := roundTripConfig(WorkloadGeneric)
+	db, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer db.Close()
+
+	workload := CreateWorkload(WorkloadConfig{Type: WorkloadGeneric, ValueSize: cfg.ValueSize, ReadRatio: cfg.ReadRatio, Seed: cfg.Seed})
+	write, err := runWritePhase(db, cfg, workload.GenerateKeys(cfg.Seed, cfg.KeyCount), workload, nil)
+	if err != nil {
+		t.Fatalf("write phase: %v", err)
+	}
+	if write.Ops != uint64(cfg.KeyCount) || write.Successful != write.Ops {
+		t.Errorf("write phase recorded %d ops, %d successful, want %d", write.Ops, write.Successful, cfg.KeyCount)
+	}
+
+	// A different seed yields a disjoint keyspace, so every read must miss
+	read, err := runReadPhase(db, cfg, workload.GenerateKeys(cfg.Seed+1, cfg.KeyCount), workload, nil)
+	if err != nil {
+		t.Fatalf("read phase: %v", err)
+	}
+	if read.NotFound != uint64(cfg.KeyCount) || read.Successful != 0 {
+		t.Errorf("not found %d of %d reads, want all", read.NotFound, read.Ops)
+	}
+	if read.Checksum != 0 {
+		t.Errorf("read checksum %x over missing keys, want 0", read.Checksum)
+	}
+	if read.Elapsed <= 0 || read.OpsPerSec() <= 0 || read.Histogram.TotalCount() != int64(read.Ops) {
+		t.Errorf("read phase timing not recorded: elapsed %s, %.0f ops/s", read.Elapsed, read.OpsPerSec())
+	}
+}
+
+// fakeClock only moves when advanced, so a phase measures exactly the time
+// its database operations advance it by
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// clockedDatabase takes the n-th Set or Get latencies[n] of fake time
+type clockedDatabase struct {
+	Database
+	clock     *fakeClock
+	latencies []time.Duration
+	ops       int
+}
+
+func (d *clockedDatabase) tick() {
+	d.clock.advance(d.latencies[d.ops%len(d.latencies)])
+	d.ops++
+}
+
+func (d *clockedDatabase) Set(key, value []byte) error {
+	d.tick()
+	return d.Database.Set(key, value)
+}
+
+func (d *clockedDatabase) Get(key []byte) ([]byte, io.Closer, error) {
+	d.tick()
+	return d.Database.Get(key)
+}
+
+func TestPhaseLatencyReportingWithFakeClock(t *testing.T) {
+	quietLogs(t)
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	phaseClock = clock
+	t.Cleanup(func() { phaseClock = realClock{} })
+
+	// Operations take 1ms to 100ms, 5.05s in total
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	memory, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer memory.Close()
+
+	cfg := roundTripConfig(WorkloadGeneric)
+	cfg.KeyCount = len(latencies)
+	cfg.Concurrency = 1 // a second worker would overlap the fake time of the first
+	workload := CreateWorkload(WorkloadConfig{Type: WorkloadGeneric, ValueSize: cfg.ValueSize, Seed: cfg.Seed})
+
+	write, err := runWritePhase(&clockedDatabase{Database: memory, clock: clock, latencies: latencies}, cfg, workload.GenerateKeys(cfg.Seed, cfg.KeyCount), workload, nil)
+	if err != nil {
+		t.Fatalf("write phase: %v", err)
+	}
+	read, err := runReadPhase(&clockedDatabase{Database: memory, clock: clock, latencies: latencies}, cfg, workload.GenerateKeys(cfg.Seed, cfg.KeyCount), workload, nil)
+	if err != nil {
+		t.Fatalf("read phase: %v", err)
+	}
+
+	for _, r := range []*PhaseResult{write, read} {
+		if r.Ops != 100 || r.Elapsed != 5050*time.Millisecond || r.TotalLatency != 5050*time.Millisecond {
+			t.Errorf("%s: %d ops in %s with %s total latency, want 100 in 5.05s", r.Phase, r.Ops, r.Elapsed, r.TotalLatency)
+		}
+		if got, want := r.OpsPerSec(), 100/5.05; math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: %.6f ops/s, want %.6f", r.Phase, got, want)
+		}
+		if got := r.AvgLatency(); got != 50500*time.Microsecond {
+			t.Errorf("%s: average latency %s, want 50.5ms", r.Phase, got)
+		}
+		// The histogram keeps 3 significant figures
+		for _, p := range []struct {
+			name      string
+			got, want time.Duration
+		}{
+			{"p50", r.P50, 50 * time.Millisecond},
+			{"p95", r.P95, 95 * time.Millisecond},
+			{"p99", r.P99, 99 * time.Millisecond},
+		} {
+			if diff := p.got - p.want; diff < 0 || diff > p.want/1000 {
+				t.Errorf("%s: %s %s, want %s", r.Phase, p.name, p.got, p.want)
+			}
+		}
+	}
+}
+
+func TestRateLimitedLatencyIncludesScheduleDelay(t *testing.T) {
+	quietLogs(t)
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	phaseClock = clock
+	t.Cleanup(func() { phaseClock = realClock{} })
+
+	memory, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer memory.Close()
+
+	// One write every 1ms against writes that take 10ms
+	cfg := roundTripConfig(WorkloadGeneric)
+	cfg.KeyCount = 10
+	cfg.Concurrency = 1
+	cfg.TargetOpsPerSec = 1000
+	workload := CreateWorkload(WorkloadConfig{Type: WorkloadGeneric, ValueSize: cfg.ValueSize, Seed: cfg.Seed})
+
+	db := &clockedDatabase{Database: memory, clock: clock, latencies: []time.Duration{10 * time.Millisecond}}
+	write, err := runWritePhase(db, cfg, workload.GenerateKeys(cfg.Seed, cfg.KeyCount), workload, nil)
+	if err != nil {
+		t.Fatalf("write phase: %v", err)
+	}
+
+	// Write i is scheduled at i ms and completes at (i+1)*10ms
+	if want := 505 * time.Millisecond; write.TotalLatency != want {
+		t.Errorf("total latency %s, want %s measured from the scheduled starts", write.TotalLatency, want)
+	}
+}
+
+// failingBatcher hands out batches whose Apply fails
+type failingBatcher struct {
+	Database
+}
+
+func (failingBatcher) NewBatch() Batch { return &failingBatch{} }
+
+type failingBatch struct{ count int }
+
+func (b *failingBatch) Set(key, value []byte) error { b.count++; return nil }
+func (b *failingBatch) Count() int                  { return b.count }
+func (b *failingBatch) Size() int                   { return 0 }
+func (b *failingBatch) Apply() error                { return errors.New("apply failed") }
+func (b *failingBatch) Close() error                { return nil }
+
+func TestApplyBatchFailureFailsEveryWrite(t *testing.T) {
+	quietLogs(t)
+
+	memory, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer memory.Close()
+
+	// Two full batches of 4 and 2 writes left for close
+	cfg := roundTripConfig(WorkloadGeneric)
+	cfg.KeyCount = 10
+	cfg.ApplyBatch = 4
+	workload := CreateWorkload(WorkloadConfig{Type: WorkloadGeneric, ValueSize: cfg.ValueSize, Seed: cfg.Seed})
+
+	write, err := runWritePhase(failingBatcher{memory}, cfg, workload.GenerateKeys(cfg.Seed, cfg.KeyCount), workload, nil)
+	if err != nil {
+		t.Fatalf("write phase: %v", err)
+	}
+	if write.Successful != 0 || write.Failed != 10 || write.ValueBytes != 0 {
+		t.Errorf("%d successful and %d failed writes of %d bytes, want every write failed", write.Successful, write.Failed, write.ValueBytes)
+	}
+}
+
+// unsettledDatabase never quiesces
+type unsettledDatabase struct {
+	Database
+}
+
+func (unsettledDatabase) WaitForQuiesce(timeout time.Duration) (bool, error) {
+	time.Sleep(time.Millisecond)
+	return false, nil
+}
+
+func TestSettleDatabaseTimeoutLeavesQuiesceTimeZero(t *testing.T) {
+	quietLogs(t)
+
+	memory, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer memory.Close()
+
+	quiesce, err := settleDatabase(unsettledDatabase{memory}, Config{QuiesceTimeout: time.Millisecond})
+	if err != nil {
+		t.Fatalf("settleDatabase: %v", err)
+	}
+	if quiesce != 0 {
+		t.Errorf("quiesce time %s for a database that never settled, want 0", quiesce)
+	}
+}
@@ -0,0 +1,220 @@
+package benchmark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// selfCheckKeys is how many keys the self-check writes to each backend
+const selfCheckKeys = 32
+
+// SelfCheckConfig selects the backends the selfcheck subcommand exercises
+type SelfCheckConfig struct {
+	Backends        []string
+	LogFormat       string
+	QMDBLibraryPath string
+	MDBXMapSize     int64
+}
+
+// selfCheckResult is the outcome of checking one backend
+type selfCheckResult struct {
+	backend string
+	err     error
+	skipped []string // operations the backend reports as unsupported
+	elapsed time.Duration
+}
+
+// RunSelfCheck opens a temporary database on every configured backend, runs a
+// short write/read/delete/iterate round trip against it and prints PASS or
+// FAIL per backend. Backends this binary was built without are reported as
+// SKIP. It fails when any backend fails.
+func RunSelfCheck(cfg SelfCheckConfig) error {
+	setupLog(Config{LogFormat: cfg.LogFormat})
+
+	// Only backends missing from the build are skipped, so a misspelt name
+	// must not reach NewDatabase and come back as ErrBackendNotFound
+	for _, backend := range cfg.Backends {
+		switch DatabaseType(backend) {
+		case DatabaseTypePebble, DatabaseTypeQMDB, DatabaseTypeMDBX, DatabaseTypeMemory:
+		default:
+			return fmt.Errorf("unknown backend %q", backend)
+		}
+	}
+
+	var results []selfCheckResult
+	failed := 0
+	for _, backend := range cfg.Backends {
+		start := time.Now()
+		skipped, err := checkBackend(cfg, backend)
+		results = append(results, selfCheckResult{backend: backend, err: err, skipped: skipped, elapsed: time.Since(start)})
+		if err != nil && !errors.Is(err, ErrBackendNotFound) {
+			failed++
+		}
+	}
+
+	if err := printSelfCheck(os.Stdout, results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d backends failed the self-check", failed, len(results))
+	}
+	return nil
+}
+
+// checkBackend runs the round trip against a fresh database of the given backend
+func checkBackend(cfg SelfCheckConfig, backend string) (skipped []string, err error) {
+	dir, err := os.MkdirTemp("", "pebble-bench-selfcheck-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := createDatabase(Config{
+		DBPath:          filepath.Join(dir, backend),
+		DatabaseType:    backend,
+		WriteEnabled:    true,
+		BlockCacheSize:  8 << 20,
+		QMDBLibraryPath: cfg.QMDBLibraryPath,
+		MDBXMapSize:     cfg.MDBXMapSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer func() {
+		if db != nil {
+			db.Close()
+		}
+	}()
+
+	key := func(i int) []byte { return []byte(fmt.Sprintf("selfcheck/%04d", i)) }
+	value := func(i int) []byte { return bytes.Repeat([]byte{byte(i)}, 64+i) }
+
+	for i := 0; i < selfCheckKeys; i++ {
+		if err := db.Set(key(i), value(i)); err != nil {
+			return skipped, fmt.Errorf("set: %w", err)
+		}
+	}
+	if err := db.Flush(); err != nil {
+		return skipped, fmt.Errorf("flush: %w", err)
+	}
+	for i := 0; i < selfCheckKeys; i++ {
+		if err := checkValue(db, key(i), value(i)); err != nil {
+			return skipped, err
+		}
+	}
+
+	// Delete the even keys and expect them to be gone
+	caps := db.Capabilities()
+	remaining := selfCheckKeys
+	if !caps.SupportsDelete {
+		skipped = append(skipped, "delete")
+	} else {
+		for i := 0; i < selfCheckKeys; i += 2 {
+			if err := db.Delete(key(i)); err != nil {
+				return skipped, fmt.Errorf("delete: %w", err)
+			}
+			if _, _, err := db.Get(key(i)); !IsKeyNotFound(err) {
+				return skipped, fmt.Errorf("get %s after delete: want not found, got %v", key(i), err)
+			}
+			remaining--
+		}
+	}
+
+	if !caps.SupportsIterator {
+		skipped = append(skipped, "iterate")
+	} else if it, err := db.NewIterator([]byte("selfcheck/"), []byte("selfcheck0")); err != nil {
+		return skipped, fmt.Errorf("iterate: %w", err)
+	} else {
+		count := 0
+		var prev []byte
+		for valid := it.First(); valid; valid = it.Next() {
+			if prev != nil && bytes.Compare(prev, it.Key()) >= 0 {
+				it.Close()
+				return skipped, fmt.Errorf("iterate: key %s after %s", it.Key(), prev)
+			}
+			prev = append(prev[:0], it.Key()...)
+			count++
+		}
+		err := it.Error()
+		if closeErr := it.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return skipped, fmt.Errorf("iterate: %w", err)
+		}
+		if count != remaining {
+			return skipped, fmt.Errorf("iterate: saw %d keys, want %d", count, remaining)
+		}
+	}
+
+	if err := checkMetrics(db.GetMetrics()); err != nil {
+		return skipped, err
+	}
+
+	err = db.Close()
+	db = nil
+	if err != nil {
+		return skipped, fmt.Errorf("close: %w", err)
+	}
+	return skipped, nil
+}
+
+// checkValue reads key back and compares it with want
+func checkValue(db Database, key, want []byte) error {
+	got, closer, err := db.Get(key)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", key, err)
+	}
+	defer func() {
+		if closer != nil {
+			closer.Close()
+		}
+	}()
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("get %s: read %d bytes that differ from the %d written", key, len(got), len(want))
+	}
+	return nil
+}
+
+// checkMetrics rejects metrics no working backend can report
+func checkMetrics(m DatabaseMetrics) error {
+	for name, v := range map[string]int64{
+		"cache_size":    m.CacheSize,
+		"memtable_size": m.MemTableSize,
+		"bytes_read":    m.BytesRead,
+		"bytes_written": m.BytesWritten,
+		"cache_hits":    m.CacheHits,
+		"cache_misses":  m.CacheMisses,
+		"l0_files":      m.L0FileCount,
+	} {
+		if v < 0 {
+			return fmt.Errorf("metrics: negative %s %d", name, v)
+		}
+	}
+	return nil
+}
+
+// printSelfCheck renders one PASS/FAIL/SKIP row per backend
+func printSelfCheck(out io.Writer, results []selfCheckResult) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BACKEND\tSTATUS\tTIME\tDETAIL")
+	for _, r := range results {
+		status, detail := "PASS", ""
+		if errors.Is(r.err, ErrBackendNotFound) {
+			status, detail = "SKIP", r.err.Error()
+		} else if r.err != nil {
+			status, detail = "FAIL", r.err.Error()
+		} else if len(r.skipped) > 0 {
+			detail = "unsupported: " + strings.Join(r.skipped, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.backend, status, r.elapsed.Round(time.Millisecond), detail)
+	}
+	return tw.Flush()
+}
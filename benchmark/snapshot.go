@@ -0,0 +1,136 @@
+package benchmark
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// snapshotKeySample caps the read keys the snapshot phase samples its reads from
+const snapshotKeySample = 100_000
+
+// runSnapshotPhase runs cfg.SnapshotReaders goroutines that each open
+// cfg.SnapshotsPerReader snapshots in turn, read cfg.SnapshotReads random keys
+// of keys from each and close it, the pattern of a node serving concurrent RPC
+// calls. It reports snapshots per second and the latency of opening, reading
+// from and closing a snapshot, returning the snapshot reads.
+func runSnapshotPhase(db Database, cfg Config, keys iter.Seq[[]byte]) (*PhaseResult, error) {
+	snapshotter, ok := db.(Snapshotter)
+	if !ok || !db.Capabilities().SupportsSnapshot {
+		return nil, fmt.Errorf("snapshot phase needs snapshots, which the %s backend does not support", cfg.DatabaseType)
+	}
+	sample := slices.Collect(takeKeys(keys, snapshotKeySample))
+	if len(sample) == 0 {
+		return nil, fmt.Errorf("snapshot phase has no keys to read")
+	}
+
+	log.Info().
+		Int("readers", cfg.SnapshotReaders).
+		Int("snapshots_per_reader", cfg.SnapshotsPerReader).
+		Int("reads_per_snapshot", cfg.SnapshotReads).
+		Int("keys", len(sample)).
+		Msg("Beginning snapshot phase")
+
+	depth := queueDepth(cfg)
+	openTimes := make(chan time.Duration, depth)
+	openCollector := startLatencyCollector("snapshot_open", openTimes)
+	readTimes := make(chan time.Duration, depth)
+	readCollector := startLatencyCollector("snapshot_read", readTimes)
+	closeTimes := make(chan time.Duration, depth)
+	closeCollector := startLatencyCollector("snapshot_close", closeTimes)
+	var snapshots, failedSnapshots, failedReads, notFound atomic.Uint64
+	var errs errorSampler
+	var wg sync.WaitGroup
+
+	phaseStart := time.Now()
+	for r := 0; r < cfg.SnapshotReaders; r++ {
+		wg.Add(1)
+		go func(reader int) {
+			defer wg.Done()
+
+			rng := rand.New(rand.NewSource(cfg.Seed + int64(reader)))
+			for i := 0; i < cfg.SnapshotsPerReader; i++ {
+				openStart := time.Now()
+				snap, err := snapshotter.NewSnapshot()
+				openTimes <- time.Since(openStart)
+				if err != nil {
+					failedSnapshots.Add(1)
+					errs.record(err)
+					continue
+				}
+
+				for j := 0; j < cfg.SnapshotReads; j++ {
+					key := sample[rng.Intn(len(sample))]
+					readStart := time.Now()
+					_, closer, err := snap.Get(key)
+					if closer != nil {
+						closer.Close()
+					}
+					readTimes <- time.Since(readStart)
+					if IsKeyNotFound(err) {
+						notFound.Add(1)
+					} else if err != nil {
+						failedReads.Add(1)
+						errs.record(err)
+					}
+				}
+
+				closeStart := time.Now()
+				err = snap.Close()
+				closeTimes <- time.Since(closeStart)
+				if err != nil {
+					failedSnapshots.Add(1)
+					errs.record(err)
+					continue
+				}
+				snapshots.Add(1)
+			}
+		}(r)
+	}
+
+	wg.Wait()
+	elapsed := time.Since(phaseStart)
+	for _, ch := range []chan time.Duration{openTimes, readTimes, closeTimes} {
+		close(ch)
+	}
+	openCollector.wait()
+	readCollector.wait()
+	closeCollector.wait()
+
+	result := newPhaseResult("snapshot", readCollector, elapsed)
+	// Snapshots that failed to open or close fail the phase like failed reads
+	result.Failed = failedReads.Load() + failedSnapshots.Load()
+	result.NotFound = notFound.Load()
+	result.Successful = result.Ops - failedReads.Load() - result.NotFound
+	result.FirstError = errs.first
+	result.Errors = errs.samples()
+	result.OtherErrors = errs.other
+
+	snapshotsPerSec := float64(0)
+	if elapsed > 0 {
+		snapshotsPerSec = float64(snapshots.Load()) / elapsed.Seconds()
+	}
+	log.Info().
+		Int("readers", cfg.SnapshotReaders).
+		Uint64("snapshots", snapshots.Load()).
+		Uint64("failed_snapshots", failedSnapshots.Load()).
+		Float64("snapshots_per_sec", snapshotsPerSec).
+		Float64("open_p50_latency_ms", openCollector.percentileMs(50)).
+		Float64("open_p99_latency_ms", openCollector.percentileMs(99)).
+		Float64("close_p50_latency_ms", closeCollector.percentileMs(50)).
+		Float64("close_p99_latency_ms", closeCollector.percentileMs(99)).
+		Uint64("reads", result.Ops).
+		Uint64("not_found", result.NotFound).
+		Uint64("failed_reads", failedReads.Load()).
+		Float64("read_p50_latency_ms", durationMs(result.P50)).
+		Float64("read_p99_latency_ms", durationMs(result.P99)).
+		Dur("elapsed", elapsed).
+		Msg("Snapshot benchmark complete")
+	return result, nil
+}
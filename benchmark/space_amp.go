@@ -0,0 +1,111 @@
+package benchmark
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// spaceAmpSampler samples the on-disk size of the database directory during
+// the write phase and divides it by the logical key and value bytes written so
+// far, logging space amplification as a time series. Compactions briefly hold
+// both their inputs and outputs, so the peak can be far above the final value.
+type spaceAmpSampler struct {
+	db      Database
+	path    string
+	logical *atomic.Uint64
+	start   time.Time
+
+	samples  int
+	last     float64
+	peak     float64
+	peakAt   time.Duration
+	peakDisk int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startSpaceAmpSampler samples every interval until close, returning nil when
+// interval <= 0. logical counts the key and value bytes written.
+func startSpaceAmpSampler(db Database, path string, interval time.Duration, logical *atomic.Uint64) *spaceAmpSampler {
+	if interval <= 0 {
+		return nil
+	}
+
+	s := &spaceAmpSampler{
+		db:      db,
+		path:    path,
+		logical: logical,
+		start:   time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+	return s
+}
+
+// sample logs the current space amplification and tracks its peak
+func (s *spaceAmpSampler) sample() {
+	logical := s.logical.Load()
+	disk, err := directorySize(s.path)
+	if err != nil {
+		log.Warn().Err(err).Str("path", s.path).Msg("Failed to measure database size")
+		return
+	}
+	elapsed := time.Since(s.start)
+
+	event := log.Info().
+		Dur("elapsed", elapsed).
+		Int64("disk_bytes", disk).
+		Uint64("logical_bytes", logical)
+	if logical > 0 {
+		amp := float64(disk) / float64(logical)
+		event = event.Float64("space_amp", amp)
+		s.last = amp
+		if amp > s.peak {
+			s.peak, s.peakAt, s.peakDisk = amp, elapsed, disk
+		}
+	}
+	// Pebble accounts for its own files, WAL and obsolete tables included
+	if usage := s.db.GetMetrics().DiskUsage; usage > 0 {
+		event = event.Uint64("pebble_disk_bytes", usage)
+		if logical > 0 {
+			event = event.Float64("pebble_space_amp", float64(usage)/float64(logical))
+		}
+	}
+	event.Msg("Space amplification sample")
+	s.samples++
+}
+
+// close stops sampling, takes a last sample of the flushed database and logs
+// the peak against the final space amplification
+func (s *spaceAmpSampler) close() {
+	if s == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.sample()
+
+	log.Info().
+		Int("samples", s.samples).
+		Float64("peak_space_amp", s.peak).
+		Dur("peak_at", s.peakAt).
+		Int64("peak_disk_bytes", s.peakDisk).
+		Float64("final_space_amp", s.last).
+		Msg("Space amplification")
+}
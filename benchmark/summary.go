@@ -0,0 +1,158 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+)
+
+// BenchmarkResult holds the headline numbers of a run, filled in by the phases
+type BenchmarkResult struct {
+	BenchmarkID string `json:"benchmark_id"`
+	Backend     string `json:"backend"`
+	Workload    string `json:"workload"`
+	KeyCount    int    `json:"key_count"`
+
+	WriteOps       uint64        `json:"write_ops"`
+	WriteFailed    uint64        `json:"write_failed"`
+	WriteOpsPerSec float64       `json:"write_ops_per_sec"`
+	WriteP50       time.Duration `json:"write_p50_ns"`
+	WriteP99       time.Duration `json:"write_p99_ns"`
+
+	ReadOps       uint64        `json:"read_ops"`
+	ReadNotFound  uint64        `json:"read_not_found"`
+	ReadFailed    uint64        `json:"read_failed"`
+	ReadOpsPerSec float64       `json:"read_ops_per_sec"`
+	ReadP50       time.Duration `json:"read_p50_ns"`
+	ReadP99       time.Duration `json:"read_p99_ns"`
+
+	// XOR of per-pair checksums of everything written and read back, only
+	// computed with VerifyChecksums. They match when every written key is read
+	// exactly once and no key was written twice.
+	WriteChecksum uint64 `json:"write_checksum"`
+	ReadChecksum  uint64 `json:"read_checksum"`
+
+	DiskSizeBytes int64 `json:"disk_size_bytes"`
+	CacheHits     int64 `json:"cache_hits"`
+	CacheMisses   int64 `json:"cache_misses"`
+
+	// Fraction of cache lookups that hit during the read phase, 0 when the
+	// backend keeps no cache stats
+	CacheHitRatio float64 `json:"cache_hit_ratio"`
+
+	// Time background work took to settle after the write phase, set with
+	// FlushBetweenPhases and left 0 when it did not settle within the timeout
+	QuiesceTime time.Duration `json:"quiesce_ns"`
+
+	// Time spent reading the hot set into cache before the read phase, set
+	// with WarmHotKeys
+	WarmUpTime time.Duration `json:"warm_up_ns"`
+
+	// Write stalls over the whole run and the time writes spent stalled, 0
+	// for backends that do not report stalls
+	WriteStalls    uint64        `json:"write_stalls"`
+	WriteStallTime time.Duration `json:"write_stall_ns"`
+
+	// CPUs of the machine and the GOMAXPROCS the run used, without which
+	// throughput is not comparable across machines
+	NumCPU     int `json:"num_cpu"`
+	GOMAXPROCS int `json:"gomaxprocs"`
+
+	// Backend settings after defaults were resolved, nil for backends that
+	// cannot report them
+	BackendConfig map[string]interface{} `json:"backend_config,omitempty"`
+}
+
+// setWrite copies the headline numbers of a write phase
+func (r *BenchmarkResult) setWrite(p *PhaseResult) {
+	r.WriteOps = p.Successful
+	r.WriteFailed = p.Failed
+	r.WriteOpsPerSec = p.OpsPerSec()
+	r.WriteP50 = p.P50
+	r.WriteP99 = p.P99
+	r.WriteChecksum = p.Checksum
+}
+
+// setRead copies the headline numbers of a read phase
+func (r *BenchmarkResult) setRead(p *PhaseResult) {
+	r.ReadOps = p.Ops
+	r.ReadNotFound = p.NotFound
+	r.ReadFailed = p.Failed
+	r.ReadOpsPerSec = p.OpsPerSec()
+	r.ReadP50 = p.P50
+	r.ReadP99 = p.P99
+	r.ReadChecksum = p.Checksum
+}
+
+// NotFoundRate returns the fraction of reads that did not find their key
+func (r BenchmarkResult) NotFoundRate() float64 {
+	if r.ReadOps == 0 {
+		return 0
+	}
+	return float64(r.ReadNotFound) / float64(r.ReadOps)
+}
+
+// printSummary renders the result as an aligned table with a header row
+func printSummary(out io.Writer, r BenchmarkResult) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "BACKEND\tWORKLOAD\tKEYS\tWRITE OPS/S\tWRITE P50\tWRITE P99\tREAD OPS/S\tREAD P50\tREAD P99\tNOT FOUND\tDISK SIZE\tCACHE HIT")
+	fmt.Fprintf(tw, "%s\t%s\t%d\t%.0f\t%s\t%s\t%.0f\t%s\t%s\t%.2f%%\t%s\t%.2f%%\n",
+		r.Backend,
+		r.Workload,
+		r.KeyCount,
+		r.WriteOpsPerSec,
+		r.WriteP50,
+		r.WriteP99,
+		r.ReadOpsPerSec,
+		r.ReadP50,
+		r.ReadP99,
+		r.NotFoundRate()*100,
+		formatBytes(r.DiskSizeBytes),
+		r.CacheHitRatio*100,
+	)
+
+	return tw.Flush()
+}
+
+// directorySize returns the total size of the regular files under path
+func directorySize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// Deleted since it was listed, e.g. an obsolete table removed by compaction
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// formatBytes renders a byte count with a binary unit suffix
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,116 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// tombstonePrefix namespaces the tombstone-scan keyspace away from workload keys
+	tombstonePrefix = "tombstone/"
+
+	// tombstoneScanCount is the number of range scans run before and after compaction
+	tombstoneScanCount = 100
+
+	// tombstoneScanWidth is the number of key indexes covered by each range scan,
+	// half of which are deleted
+	tombstoneScanWidth = 1000
+)
+
+// tombstoneKey returns the key for index i in the tombstone-scan keyspace
+func tombstoneKey(i int) []byte {
+	key := make([]byte, len(tombstonePrefix)+8)
+	copy(key, tombstonePrefix)
+	binary.BigEndian.PutUint64(key[len(tombstonePrefix):], uint64(i))
+	return key
+}
+
+// runTombstoneScanPhase writes a dedicated keyspace, deletes every other key and
+// measures range scans over the deleted region before and after a forced compaction
+func runTombstoneScanPhase(db Database, cfg Config) error {
+	keyCount := cfg.TombstoneKeys
+	log.Info().Int("keys", keyCount).Msg("Beginning tombstone scan phase")
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	for i := 0; i < keyCount; i++ {
+		if err := db.Set(tombstoneKey(i), generateValue(rng, cfg.ValueSize)); err != nil {
+			return fmt.Errorf("tombstone phase write failed: %w", err)
+		}
+	}
+
+	deleteStart := time.Now()
+	for i := 1; i < keyCount; i += 2 {
+		if err := db.Delete(tombstoneKey(i)); err != nil {
+			return fmt.Errorf("tombstone phase delete failed: %w", err)
+		}
+	}
+	log.Info().
+		Int("deleted", keyCount/2).
+		Dur("elapsed", time.Since(deleteStart)).
+		Msg("Deleted every other key")
+
+	if err := db.Flush(); err != nil {
+		return fmt.Errorf("tombstone phase flush failed: %w", err)
+	}
+
+	if err := runTombstoneScans(db, cfg, "before_compaction"); err != nil {
+		return err
+	}
+
+	compactStart := time.Now()
+	if err := db.Compact(tombstoneKey(0), tombstoneKey(keyCount)); err != nil {
+		return fmt.Errorf("tombstone phase compaction failed: %w", err)
+	}
+	log.Info().Dur("elapsed", time.Since(compactStart)).Msg("Forced compaction of tombstone range")
+
+	return runTombstoneScans(db, cfg, "after_compaction")
+}
+
+// runTombstoneScans runs tombstoneScanCount scans over random windows of the
+// tombstone keyspace. The windows are seeded so both stages scan the same ranges.
+func runTombstoneScans(db Database, cfg Config, stage string) error {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	width := min(tombstoneScanWidth, cfg.TombstoneKeys)
+
+	var total, slowest time.Duration
+	var items uint64
+	for s := 0; s < tombstoneScanCount; s++ {
+		from := rng.Intn(cfg.TombstoneKeys - width + 1)
+
+		scanStart := time.Now()
+		it, err := db.NewIterator(tombstoneKey(from), tombstoneKey(from+width))
+		if err != nil {
+			return fmt.Errorf("tombstone scan failed: %w", err)
+		}
+		for valid := it.First(); valid; valid = it.Next() {
+			items++
+		}
+		err = it.Error()
+		if closeErr := it.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("tombstone scan failed: %w", err)
+		}
+
+		elapsed := time.Since(scanStart)
+		total += elapsed
+		slowest = max(slowest, elapsed)
+	}
+
+	log.Info().
+		Str("stage", stage).
+		Int("scans", tombstoneScanCount).
+		Int("scan_width", width).
+		Uint64("items_returned", items).
+		Float64("avg_scan_latency_ms", float64(total.Microseconds())/1000.0/float64(tombstoneScanCount)).
+		Dur("max_scan_latency", slowest).
+		Dur("total_elapsed", total).
+		Msg("Tombstone scan complete")
+
+	return nil
+}
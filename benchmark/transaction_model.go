@@ -22,6 +22,9 @@ type TransactionModelConfig struct {
 	CodeAccessOps     int     `json:"code_access_ops"`     // Contract code access
 	UpdateProbability float64 `json:"update_probability"`  // Trie update probability
 	CommitRatio       float64 `json:"commit_ratio"`        // Persistence overhead
+
+	// Scale of every transaction's storage operations per account, 0 for 1
+	StorageOpMultiplier float64 `json:"storage_op_multiplier"`
 }
 
 // Default configurations for different networks
@@ -147,14 +150,24 @@ func (tm *TransactionModel) calculateAccountOperations(chars TransactionCharacte
 	return int(basicOps + contractOps)
 }
 
+// storageOpsPerAccount returns the transaction's S parameter scaled by the
+// storage op multiplier
+func (tm *TransactionModel) storageOpsPerAccount(chars TransactionCharacteristics) float64 {
+	if tm.config.StorageOpMultiplier > 0 {
+		return chars.StorageOpsPerAccount * tm.config.StorageOpMultiplier
+	}
+	return chars.StorageOpsPerAccount
+}
+
 // calculateStorageOperations implements the storage operations formula
 func (tm *TransactionModel) calculateStorageOperations(chars TransactionCharacteristics) int {
-	if chars.StorageOpsPerAccount == 0 {
+	s := tm.storageOpsPerAccount(chars)
+	if s == 0 {
 		return 0
 	}
 
 	// A × S × (R_ratio + 1) × (1 - L × C)
-	storageOps := float64(chars.AccountsTouched) * chars.StorageOpsPerAccount *
+	storageOps := float64(chars.AccountsTouched) * s *
 		(tm.config.ReadWriteRatio + 1.0) *
 		(1.0 - tm.config.StorageLocalityFactor*tm.config.CacheHitRatio)
 
@@ -170,7 +183,7 @@ func (tm *TransactionModel) calculateTrieOperations(chars TransactionCharacteris
 		2.0 * (1.0 + tm.config.UpdateProbability) * callDepthFactor
 
 	// Storage trie operations: A × S × S_depth × 2 × (1 + UpdateProbability) × CallDepthFactor
-	storageTrieOps := float64(chars.AccountsTouched) * chars.StorageOpsPerAccount *
+	storageTrieOps := float64(chars.AccountsTouched) * tm.storageOpsPerAccount(chars) *
 		float64(tm.config.StorageTrieDepth) * 2.0 *
 		(1.0 + tm.config.UpdateProbability) * callDepthFactor
 
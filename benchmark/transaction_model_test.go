@@ -0,0 +1,22 @@
+package benchmark
+
+import "testing"
+
+func TestStorageOpMultiplierScalesStorageOperations(t *testing.T) {
+	chars := TransactionCharacteristics{AccountsTouched: 4, StorageOpsPerAccount: 2.5, CallDepth: 2}
+	base := NewTransactionModel(EthereumMainnetConfig, 1).CalculateDatabaseOperations(chars)
+
+	cfg := EthereumMainnetConfig
+	cfg.StorageOpMultiplier = 3
+	scaled := NewTransactionModel(cfg, 1).CalculateDatabaseOperations(chars)
+
+	if want := 3 * base.StorageOperations; scaled.StorageOperations < want-1 || scaled.StorageOperations > want+1 {
+		t.Errorf("storage operations = %d, want about %d", scaled.StorageOperations, want)
+	}
+	if scaled.TrieOperations <= base.TrieOperations {
+		t.Errorf("trie operations = %d, want more than the unscaled %d", scaled.TrieOperations, base.TrieOperations)
+	}
+	if scaled.AccountOperations != base.AccountOperations {
+		t.Errorf("account operations = %d, want the unscaled %d", scaled.AccountOperations, base.AccountOperations)
+	}
+}
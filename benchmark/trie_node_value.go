@@ -0,0 +1,91 @@
+package benchmark
+
+import (
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Trie node value encodings
+const (
+	TrieNodeEncodingRandom = "random" // random bytes of a plausible node size
+	TrieNodeEncodingRLP    = "rlp"    // RLP-encoded branch, extension and leaf nodes
+)
+
+// trieBranchMinSize is the encoded size of a branch node with two children,
+// the smallest a branch can be. Smaller nodes are extensions or leaves.
+const trieBranchMinSize = 20 + 2*32
+
+// trieNodeValue returns a trie node value of about size bytes. Random values
+// are exactly size bytes. RLP values are shaped after the size the way real
+// nodes are: a branch holding as many child hashes as fit, or below
+// trieBranchMinSize an extension or a leaf.
+func trieNodeValue(rng *rand.Rand, encoding string, size int) []byte {
+	if encoding != TrieNodeEncodingRLP {
+		value := make([]byte, size)
+		rng.Read(value)
+		return value
+	}
+
+	var node []interface{}
+	switch {
+	case size >= trieBranchMinSize:
+		// 3 bytes of list header, 33 per child hash and 1 per empty slot
+		children := minInt((size-20)/32, 16)
+		node = make([]interface{}, 17)
+		for i := range node {
+			node[i] = []byte{}
+		}
+		for _, i := range rng.Perm(16)[:children] {
+			node[i] = randomBytes(rng, 32)
+		}
+
+	case rng.Intn(2) == 0:
+		// Extensions share a few nibbles before the next branch
+		node = []interface{}{hexPrefix(randomNibbles(rng, 1+rng.Intn(8)), false), randomBytes(rng, 32)}
+
+	default:
+		// Leaves hold the rest of a 64-nibble key hash and the value
+		path := hexPrefix(randomNibbles(rng, 50+rng.Intn(15)), true)
+		node = []interface{}{path, randomBytes(rng, maxInt(size-len(path)-4, 1))}
+	}
+
+	encoded, _ := rlp.EncodeToBytes(node)
+	return encoded
+}
+
+// randomBytes returns n bytes from rng
+func randomBytes(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	rng.Read(b)
+	return b
+}
+
+// randomNibbles returns n nibbles from rng, one per byte
+func randomNibbles(rng *rand.Rand, n int) []byte {
+	nibbles := make([]byte, n)
+	for i := range nibbles {
+		nibbles[i] = byte(rng.Intn(16))
+	}
+	return nibbles
+}
+
+// hexPrefix packs nibbles into the compact encoding trie nodes store paths
+// in, whose first nibble flags leaves and odd lengths
+func hexPrefix(nibbles []byte, leaf bool) []byte {
+	var flag byte
+	if leaf {
+		flag = 2
+	}
+	out := make([]byte, len(nibbles)/2+1)
+	if len(nibbles)%2 == 1 {
+		out[0] = (flag|1)<<4 | nibbles[0]
+		nibbles = nibbles[1:]
+	} else {
+		out[0] = flag << 4
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		out[i/2+1] = nibbles[i]<<4 | nibbles[i+1]
+	}
+	return out
+}
@@ -4,12 +4,19 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"math/big"
+	"math/bits"
+	mathrand "math/rand"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
+// defaultTrieAverageDepth is the typical state trie depth in Ethereum
+const defaultTrieAverageDepth = 6
+
 // TrieSimulation models the actual database operations that happen during trie traversal and updates
 type TrieSimulation struct {
 	// Current state root - most frequently accessed node
@@ -19,10 +26,29 @@ type TrieSimulation struct {
 	knownPaths map[string][]byte
 	
 	// Track trie depth for realistic traversal patterns
-	averageDepth int
-	maxDepth     int
+	averageDepth      int
+	maxDepth          int
+	depthVariance     int    // spread of the per-path depth around averageDepth
+	depthDistribution string // TrieDepthUniform or TrieDepthNormal
+	
+	// Storage trie depth override, 0 derives it from averageDepth
+	storageDepth int
+
+	// Encoding and source of the updated node values
+	nodeEncoding string
+	nodeRng      *mathrand.Rand
+
+	// Realized depths of the computed paths
+	statePaths, stateDepthSum     atomic.Int64
+	storagePaths, storageDepthSum atomic.Int64
 }
 
+// Trie depth distributions around the average depth
+const (
+	TrieDepthUniform = "uniform" // uniform in [-variance, variance)
+	TrieDepthNormal  = "normal"  // approximately normal with standard deviation variance
+)
+
 // DatabaseOperation represents a single database operation with metadata
 type DatabaseOperation struct {
 	Type        string // "READ", "WRITE", "DELETE"
@@ -46,8 +72,78 @@ func NewTrieSimulation() *TrieSimulation {
 	return &TrieSimulation{
 		stateRoot:    stateRoot,
 		knownPaths:   make(map[string][]byte),
-		averageDepth: 6,  // Typical trie depth in Ethereum
-		maxDepth:     16, // Maximum practical depth
+		averageDepth:      defaultTrieAverageDepth,
+		maxDepth:          16, // Maximum practical depth
+		depthVariance:     2,
+		depthDistribution: TrieDepthUniform,
+		nodeEncoding:      TrieNodeEncodingRLP,
+		nodeRng:           mathrand.New(mathrand.NewSource(int64(binary.BigEndian.Uint64(stateRoot)))),
+	}
+}
+
+// configureDepth applies the trie depth settings of cfg. Zero depths and a nil
+// variance keep the defaults.
+func (ts *TrieSimulation) configureDepth(cfg WorkloadConfig) {
+	if cfg.TrieAverageDepth > 0 {
+		ts.averageDepth = cfg.TrieAverageDepth
+	}
+	if cfg.TrieMaxDepth > 0 {
+		ts.maxDepth = cfg.TrieMaxDepth
+	}
+	if cfg.TrieDepthVariance != nil {
+		ts.depthVariance = *cfg.TrieDepthVariance
+	}
+	if cfg.TrieDepthDistribution != "" {
+		ts.depthDistribution = cfg.TrieDepthDistribution
+	}
+	ts.storageDepth = cfg.StorageTrieDepth
+}
+
+// configureNodeEncoding applies the trie node value encoding of cfg, keeping
+// RLP when it is unset
+func (ts *TrieSimulation) configureNodeEncoding(cfg WorkloadConfig) {
+	if cfg.TrieNodeEncoding != "" {
+		ts.nodeEncoding = cfg.TrieNodeEncoding
+	}
+}
+
+// scaleDepth moves a depth drawn around the default average by the
+// configured average, keeping it between 1 and the maximum depth
+func (ts *TrieSimulation) scaleDepth(depth int) int {
+	depth += ts.averageDepth - defaultTrieAverageDepth
+	return maxInt(1, minInt(depth, ts.maxDepth))
+}
+
+// depthOffset derives a path's deviation from the average depth from its hash
+func (ts *TrieSimulation) depthOffset(hash []byte) int {
+	v := minInt(ts.depthVariance, 128)
+	if v <= 0 {
+		return 0
+	}
+
+	if ts.depthDistribution == TrieDepthNormal && len(hash) >= 2 {
+		// The set bits of 16 hash bits are Binomial(16, 0.5): mean 8, standard deviation 2
+		ones := bits.OnesCount16(binary.BigEndian.Uint16(hash))
+		return int(math.Round(float64(ones-8) / 2 * float64(v)))
+	}
+	return int(hash[0])%(2*v) - v
+}
+
+// depthStats reports the mean depth of the paths computed so far
+func (ts *TrieSimulation) depthStats() map[string]interface{} {
+	mean := func(sum, n int64) float64 {
+		if n == 0 {
+			return 0
+		}
+		return float64(sum) / float64(n)
+	}
+
+	statePaths, storagePaths := ts.statePaths.Load(), ts.storagePaths.Load()
+	return map[string]interface{}{
+		"trie_paths":              statePaths,
+		"trie_mean_depth":         mean(ts.stateDepthSum.Load(), statePaths),
+		"storage_trie_paths":      storagePaths,
+		"storage_trie_mean_depth": mean(ts.storageDepthSum.Load(), storagePaths),
 	}
 }
 
@@ -110,7 +206,7 @@ func (ts *TrieSimulation) SimulateAccountUpdate(address []byte, newAccountData [
 	path := ts.computeTriePath(addressHash)
 	for i := len(path) - 1; i >= 0; i-- {
 		// Simulate updating the node hash due to child changes
-		updatedNodeData := ts.generateUpdatedTrieNode(path[i], i)
+		updatedNodeData := ts.generateUpdatedTrieNode(i)
 		ops = append(ops, DatabaseOperation{
 			Type:        "WRITE",
 			Key:         path[i],
@@ -169,7 +265,7 @@ func (ts *TrieSimulation) SimulateStorageUpdate(address []byte, storageKey []byt
 	
 	// 4. Update storage trie nodes (bottom-up)
 	for i := len(storagePath) - 1; i >= 0; i-- {
-		updatedNodeData := ts.generateUpdatedTrieNode(storagePath[i], i)
+		updatedNodeData := ts.generateUpdatedTrieNode(i)
 		ops = append(ops, DatabaseOperation{
 			Type:        "WRITE",
 			Key:         storagePath[i],
@@ -192,7 +288,7 @@ func (ts *TrieSimulation) SimulateStorageUpdate(address []byte, storageKey []byt
 	// 6. Update state trie nodes due to account change
 	statePath := ts.computeTriePath(addressHash)
 	for i := len(statePath) - 1; i >= 0; i-- {
-		updatedNodeData := ts.generateUpdatedTrieNode(statePath[i], i)
+		updatedNodeData := ts.generateUpdatedTrieNode(i)
 		ops = append(ops, DatabaseOperation{
 			Type:        "WRITE",
 			Key:         statePath[i],
@@ -219,6 +315,11 @@ func (ts *TrieSimulation) SimulateStorageUpdate(address []byte, storageKey []byt
 	}
 }
 
+// trieSimulationPrefixes returns the prefixes of every key the simulation emits
+func trieSimulationPrefixes() [][]byte {
+	return [][]byte{[]byte("stateroot"), []byte("account"), []byte("storage"), []byte("trie")}
+}
+
 // computeTriePath simulates the path traversal through the trie
 func (ts *TrieSimulation) computeTriePath(hash []byte) [][]byte {
 	path := [][]byte{}
@@ -228,7 +329,7 @@ func (ts *TrieSimulation) computeTriePath(hash []byte) [][]byte {
 	depth := ts.averageDepth
 	if len(hash) > 0 {
 		// Use hash to determine depth variation
-		depth += int(hash[0]%4) - 2 // +/- 2 levels
+		depth += ts.depthOffset(hash)
 		if depth < 3 {
 			depth = 3
 		}
@@ -256,6 +357,8 @@ func (ts *TrieSimulation) computeTriePath(hash []byte) [][]byte {
 		path = append(path, nodeKey)
 	}
 	
+	ts.statePaths.Add(1)
+	ts.stateDepthSum.Add(int64(len(path)))
 	return path
 }
 
@@ -266,6 +369,10 @@ func (ts *TrieSimulation) computeStorageTriePath(accountHash, storageKeyHash []b
 	if depth < 2 {
 		depth = 2
 	}
+	if ts.storageDepth > 0 {
+		// A path can't be longer than the nibbles in the storage key hash
+		depth = min(ts.storageDepth, len(storageKeyHash)*2)
+	}
 	
 	path := [][]byte{}
 	currentPath := append([]byte("storage"), accountHash...)
@@ -286,6 +393,8 @@ func (ts *TrieSimulation) computeStorageTriePath(accountHash, storageKeyHash []b
 		path = append(path, nodeKey)
 	}
 	
+	ts.storagePaths.Add(1)
+	ts.storageDepthSum.Add(int64(len(path)))
 	return path
 }
 
@@ -301,37 +410,15 @@ func (ts *TrieSimulation) computeStorageValueKey(accountHash, storageKeyHash []b
 }
 
 // generateUpdatedTrieNode simulates creating updated trie node data
-func (ts *TrieSimulation) generateUpdatedTrieNode(nodeKey []byte, depth int) []byte {
-	// Simulate realistic trie node sizes (typically 32-200 bytes)
+func (ts *TrieSimulation) generateUpdatedTrieNode(depth int) []byte {
+	// Simulate realistic trie node sizes, larger towards the root
 	baseSize := 64
 	if depth == 0 {
 		baseSize = 128 // Root nodes tend to be larger
 	}
+	size := baseSize + 32*((depth+1)%8)
 	
-	// Create realistic node structure
-	node := struct {
-		NodeType uint8    // branch, extension, or leaf
-		Children [][32]byte // Child hashes
-		Value    []byte   // Node value
-		Path     []byte   // Path information
-	}{
-		NodeType: uint8(depth % 3), // Vary node types
-	}
-	
-	// Add some children (branch nodes have up to 16)
-	numChildren := (depth + 1) % 8
-	node.Children = make([][32]byte, numChildren)
-	for i := range node.Children {
-		rand.Read(node.Children[i][:])
-	}
-	
-	// Add path and value data
-	node.Path = nodeKey[min(len(nodeKey), 8):] // Use part of key as path
-	node.Value = make([]byte, baseSize)
-	rand.Read(node.Value)
-	
-	encoded, _ := rlp.EncodeToBytes(node)
-	return encoded
+	return trieNodeValue(ts.nodeRng, ts.nodeEncoding, size)
 }
 
 // generateNewStateRoot creates a new state root hash
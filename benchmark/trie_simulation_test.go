@@ -0,0 +1,32 @@
+package benchmark
+
+import "testing"
+
+func TestConfigureDepthDefaultsVariance(t *testing.T) {
+	ts := NewTrieSimulation()
+	ts.configureDepth(WorkloadConfig{})
+	if ts.depthVariance != 2 {
+		t.Errorf("zero-value config: variance %d, want the default 2", ts.depthVariance)
+	}
+
+	fixed := 0
+	ts.configureDepth(WorkloadConfig{TrieDepthVariance: &fixed})
+	if ts.depthVariance != 0 {
+		t.Errorf("variance %d, want the configured 0", ts.depthVariance)
+	}
+}
+
+func TestStateRealisticFollowsTrieDepth(t *testing.T) {
+	quietLogs(t)
+
+	cfg := blendTestConfig("")
+	cfg.TrieAverageDepth = 2
+	cfg.TrieMaxDepth = 3
+	w := NewRealisticPoSStateWorkload(cfg)
+	if got := w.trieSimulation.scaleDepth(12); got != 3 {
+		t.Errorf("scaleDepth(12) = %d, want it capped at --trie-max-depth 3", got)
+	}
+	if got := w.trieSimulation.scaleDepth(1); got != 1 {
+		t.Errorf("scaleDepth(1) = %d, want at least 1", got)
+	}
+}
@@ -0,0 +1,120 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// valueDupPoolSize is the number of distinct repeated values --value-dup-ratio
+// draws from, and valueDupPoolEntry the length of each before it repeats.
+// Entry 0 stays zeroed like an empty slot or a zero balance.
+const (
+	valueDupPoolSize  = 4
+	valueDupPoolEntry = 4 << 10
+)
+
+// valueShape holds the value options shared by every write path
+type valueShape struct {
+	align    int      // round sizes up to a multiple of align, <= 1 disables
+	dupRatio float64  // fraction of values replaced by a pooled value
+	pool     [][]byte // seeded pool of repeated contents
+
+	deterministic bool  // derive each value from its key and seed alone
+	seed          int64 // run seed mixed into the key of deterministic values
+
+	profile ValueProfile // generate values of this profile instead of the workload's
+	size    int          // size of profile values
+}
+
+// newValueShape builds the value shaping of cfg, seeding the dup pool from cfg.Seed
+func newValueShape(cfg Config) valueShape {
+	s := valueShape{
+		align:         cfg.ValueAlign,
+		dupRatio:      cfg.ValueDupRatio,
+		deterministic: cfg.DeterministicValues,
+		seed:          cfg.Seed,
+		profile:       cfg.ValueProfile,
+		size:          cfg.ValueSize,
+	}
+	if s.dupRatio > 0 || s.profile == ValueProfileDupHeavy {
+		rng := rand.New(rand.NewSource(cfg.Seed))
+		s.pool = make([][]byte, valueDupPoolSize)
+		for i := range s.pool {
+			s.pool[i] = make([]byte, valueDupPoolEntry)
+			if i > 0 {
+				rng.Read(s.pool[i])
+			}
+		}
+	}
+	return s
+}
+
+// apply aligns value and then, with probability dupRatio, replaces its
+// contents with a pooled value of the same length. Two duplicates of the same
+// length drawn from the same pool entry are byte-identical.
+func (s valueShape) apply(rng *rand.Rand, value []byte) []byte {
+	value = alignValue(rng, value, s.align)
+	if s.dupRatio <= 0 || rng.Float64() >= s.dupRatio {
+		return value
+	}
+
+	return repeatPoolEntry(s.pool[rng.Intn(len(s.pool))], len(value))
+}
+
+// generate returns a value of the shape's profile, or random bytes when it has
+// none, of size bytes
+func (s valueShape) generate(rng *rand.Rand, size int) []byte {
+	if s.profile == "" {
+		return generateValue(rng, size)
+	}
+	return s.profile.generate(rng, size, s.pool)
+}
+
+// generateWorkloadValue generates the value of key and shapes it. Every write
+// path draws its values from here so --value-profile, --value-align and
+// --value-dup-ratio apply to all of them. Deterministic shapes ignore rng and
+// generate from a generator seeded with the key, so a key gets the same value
+// from any worker.
+func generateWorkloadValue(rng *rand.Rand, workload Workload, key []byte, shape valueShape) []byte {
+	if shape.deterministic {
+		rng = keyRand(shape.seed, key)
+	}
+	if shape.profile != "" {
+		return shape.apply(rng, shape.profile.generate(rng, shape.size, shape.pool))
+	}
+	return shape.apply(rng, workload.GenerateValue(rng, key))
+}
+
+// keyRand returns a generator seeded from the hash of seed and key
+func keyRand(seed int64, key []byte) *rand.Rand {
+	h := fnv.New64a()
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(seed)))
+	h.Write(key)
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// alignValue pads value with random bytes up to the next multiple of align,
+// leaving it unchanged when align <= 1 or the size is already aligned
+func alignValue(rng *rand.Rand, value []byte, align int) []byte {
+	if align <= 1 || len(value)%align == 0 {
+		return value
+	}
+
+	padded := make([]byte, (len(value)/align+1)*align)
+	n := copy(padded, value)
+	rng.Read(padded[n:])
+	return padded
+}
+
+// maxTrackedValueSize bounds the value size histogram; larger values are
+// counted in ValueBytes but left out of the size distribution
+const maxTrackedValueSize = 1 << 30
+
+// newValueSizeHistogram merges the value sizes each write worker records into
+// the distribution reported after the write phase
+func newValueSizeHistogram() *workerHistogram {
+	return &workerHistogram{hist: hdrhistogram.New(1, maxTrackedValueSize, 3)}
+}
@@ -0,0 +1,32 @@
+package benchmark
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestDeterministicValuesIgnoreGeneratorState(t *testing.T) {
+	cfg := Config{Seed: 3, ValueSize: 100, ValueAlign: 64, DeterministicValues: true}
+	workload := NewGenericWorkload(WorkloadConfig{ValueSize: cfg.ValueSize})
+	shape := newValueShape(cfg)
+	key := []byte("account/1")
+
+	a := generateWorkloadValue(rand.New(rand.NewSource(1)), workload, key, shape)
+	b := generateWorkloadValue(rand.New(rand.NewSource(2)), workload, key, shape)
+	if !bytes.Equal(a, b) {
+		t.Fatal("same key generated different values from different generators")
+	}
+	if len(a) != 128 {
+		t.Fatalf("value of %d bytes, want 128 after alignment", len(a))
+	}
+
+	other := generateWorkloadValue(rand.New(rand.NewSource(1)), workload, []byte("account/2"), shape)
+	if bytes.Equal(a, other) {
+		t.Fatal("different keys generated the same value")
+	}
+	cfg.Seed = 4
+	if reseeded := generateWorkloadValue(rand.New(rand.NewSource(1)), workload, key, newValueShape(cfg)); bytes.Equal(a, reseeded) {
+		t.Fatal("different seeds generated the same value")
+	}
+}
@@ -0,0 +1,81 @@
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ValueProfile selects what values look like independently of the workload,
+// which then only decides the keys. The empty profile keeps each workload's
+// own values.
+type ValueProfile string
+
+const (
+	ValueProfileRandom       ValueProfile = "random"       // uniformly random bytes, incompressible
+	ValueProfileRLP          ValueProfile = "rlp"          // RLP lists of small integers and hashes, like encoded state
+	ValueProfileCompressible ValueProfile = "compressible" // a random half repeated, compressing to about 50%
+	ValueProfileDupHeavy     ValueProfile = "dup-heavy"    // a few distinct values repeated across keys
+)
+
+// rlpProfileHashSize is the size of the hash items of rlp profile values
+const rlpProfileHashSize = 32
+
+// validate reports whether p is a known value profile; empty keeps the workload's values
+func (p ValueProfile) validate() error {
+	switch p {
+	case "", ValueProfileRandom, ValueProfileRLP, ValueProfileCompressible, ValueProfileDupHeavy:
+		return nil
+	default:
+		return fmt.Errorf("invalid value profile %q: expected random, rlp, compressible or dup-heavy", p)
+	}
+}
+
+// generate returns a size byte value of profile p. pool holds the distinct
+// contents of dup-heavy values.
+func (p ValueProfile) generate(rng *rand.Rand, size int, pool [][]byte) []byte {
+	switch p {
+	case ValueProfileRLP:
+		return rlpProfileValue(rng, size)
+	case ValueProfileCompressible:
+		value := make([]byte, size)
+		half := (size + 1) / 2
+		rng.Read(value[:half])
+		copy(value[half:], value)
+		return value
+	case ValueProfileDupHeavy:
+		return repeatPoolEntry(pool[rng.Intn(len(pool))], size)
+	default:
+		return generateValue(rng, size)
+	}
+}
+
+// rlpProfileValue encodes a list alternating small integers, which RLP
+// stores in as few bytes as they need, and 32 byte hashes, until the encoding
+// reaches size
+func rlpProfileValue(rng *rand.Rand, size int) []byte {
+	var items []interface{}
+	for encoded := 3; encoded < size; {
+		if len(items)%2 == 0 {
+			items = append(items, uint64(rng.Intn(1<<16)))
+			encoded += 3
+			continue
+		}
+		hash := make([]byte, rlpProfileHashSize)
+		rng.Read(hash)
+		items = append(items, hash)
+		encoded += 1 + rlpProfileHashSize
+	}
+	encoded, _ := rlp.EncodeToBytes(items)
+	return encoded
+}
+
+// repeatPoolEntry fills a size byte value with repetitions of entry
+func repeatPoolEntry(entry []byte, size int) []byte {
+	value := make([]byte, size)
+	for n := 0; n < len(value); {
+		n += copy(value[n:], entry)
+	}
+	return value
+}
@@ -0,0 +1,125 @@
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// VerifyDBsConfig names the two existing databases the verify-dbs subcommand compares
+type VerifyDBsConfig struct {
+	PathA          string
+	DatabaseTypeA  string
+	PathB          string
+	DatabaseTypeB  string
+	BlockCacheSize int64
+	MDBXMapSize    int64
+	LogFormat      string
+}
+
+// dbMismatch describes the first difference found between two databases
+type dbMismatch struct {
+	key    []byte
+	reason string
+}
+
+// RunVerifyDBs opens two databases read-only, walks both in key order and
+// fails on the first key or value that differs between them. Two runs of the
+// same workload and seed should produce identical databases on any backend.
+func RunVerifyDBs(cfg VerifyDBsConfig) error {
+	setupLog(Config{LogFormat: cfg.LogFormat})
+
+	open := func(path, dbType string) (Database, error) {
+		db, err := createDatabase(Config{
+			DBPath:         path,
+			DatabaseType:   dbType,
+			BlockCacheSize: cfg.BlockCacheSize,
+			MDBXMapSize:    cfg.MDBXMapSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s database at %s: %w", dbType, path, err)
+		}
+		if !db.Capabilities().SupportsIterator {
+			db.Close()
+			return nil, fmt.Errorf("verify-dbs walks both databases with an iterator, which the %s backend does not support", dbType)
+		}
+		return db, nil
+	}
+	dbA, err := open(cfg.PathA, cfg.DatabaseTypeA)
+	if err != nil {
+		return err
+	}
+	defer dbA.Close()
+	dbB, err := open(cfg.PathB, cfg.DatabaseTypeB)
+	if err != nil {
+		return err
+	}
+	defer dbB.Close()
+
+	log.Info().
+		Str("a", cfg.DatabaseTypeA+":"+cfg.PathA).
+		Str("b", cfg.DatabaseTypeB+":"+cfg.PathB).
+		Msg("Comparing databases")
+
+	start := time.Now()
+	keys, mismatch, err := compareDatabases(dbA, dbB)
+	if err != nil {
+		return err
+	}
+	if mismatch != nil {
+		return fmt.Errorf("databases differ after %d matching keys: key %x %s", keys, mismatch.key, mismatch.reason)
+	}
+
+	log.Info().Uint64("keys", keys).Dur("elapsed", time.Since(start)).Msg("Databases hold identical keys and values")
+	return nil
+}
+
+// compareDatabases merge-walks a and b in byte order and returns the number of
+// matching keys before the first mismatch, or all keys when they are identical
+func compareDatabases(a, b Database) (uint64, *dbMismatch, error) {
+	itA, err := a.NewIterator(nil, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer itA.Close()
+	itB, err := b.NewIterator(nil, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer itB.Close()
+
+	var keys uint64
+	validA, validB := itA.First(), itB.First()
+	for validA && validB {
+		keyA, keyB := itA.Key(), itB.Key()
+		switch cmp := bytes.Compare(keyA, keyB); {
+		case cmp < 0:
+			return keys, &dbMismatch{key: bytes.Clone(keyA), reason: "is only in database a"}, nil
+		case cmp > 0:
+			return keys, &dbMismatch{key: bytes.Clone(keyB), reason: "is only in database b"}, nil
+		}
+		if valueA, valueB := itA.Value(), itB.Value(); !bytes.Equal(valueA, valueB) {
+			return keys, &dbMismatch{
+				key:    bytes.Clone(keyA),
+				reason: fmt.Sprintf("has a %d-byte value in a and a different %d-byte value in b", len(valueA), len(valueB)),
+			}, nil
+		}
+		keys++
+		validA, validB = itA.Next(), itB.Next()
+	}
+
+	for _, it := range []Iterator{itA, itB} {
+		if err := it.Error(); err != nil {
+			return keys, nil, fmt.Errorf("failed to iterate database: %w", err)
+		}
+	}
+	switch {
+	case validA:
+		return keys, &dbMismatch{key: bytes.Clone(itA.Key()), reason: "is only in database a"}, nil
+	case validB:
+		return keys, &dbMismatch{key: bytes.Clone(itB.Key()), reason: "is only in database b"}, nil
+	}
+	return keys, nil, nil
+}
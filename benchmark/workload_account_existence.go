@@ -0,0 +1,164 @@
+package benchmark
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// WorkloadAccountExistence reads a mix of existing and never written accounts
+const WorkloadAccountExistence WorkloadType = "account-existence"
+
+// existenceMissSeed is mixed into the seed of the universe fresh addresses are
+// drawn from, so they differ from the written accounts
+const existenceMissSeed = 0x6d697373
+
+// Key classes of the account-existence workload
+const (
+	keyClassHit  = "hit"
+	keyClassMiss = "miss"
+)
+
+// existenceClasses names the key classes for per-class read latencies
+var existenceClasses = []string{keyClassHit, keyClassMiss}
+
+// ReadKeyGenerator is implemented by workloads whose read phase reads other
+// keys than the ones they write. RunBenchmark reads the keys of
+// GenerateReadKeys instead of the written ones after a write phase.
+type ReadKeyGenerator interface {
+	GenerateReadKeys(seed int64, count int) iter.Seq[[]byte]
+}
+
+// AccountExistenceWorkload models the existence checks EVM execution makes
+// before touching an account: it writes count accounts and reads back a mix in
+// which ExistenceHitRatio of the reads target written accounts and the rest
+// fresh addresses that were never written, the lookups bloom filters answer
+// without reading a block. Account keys are "a" + keccak(address), written
+// accounts come from a universe of count addresses and fresh ones from a
+// universe seeded apart from it. The keys of the fresh addresses read are
+// remembered so every read is classified as a hit or a miss.
+type AccountExistenceWorkload struct {
+	config   WorkloadConfig
+	hitRatio float64
+	state    *PoSAccountWorkload // account encoding
+
+	mu     sync.Mutex
+	misses map[string]struct{} // keys of the fresh addresses read
+}
+
+// NewAccountExistenceWorkload creates an account existence check workload
+func NewAccountExistenceWorkload(cfg WorkloadConfig) *AccountExistenceWorkload {
+	return &AccountExistenceWorkload{
+		config:   cfg,
+		hitRatio: cfg.ExistenceHitRatio,
+		state:    NewPoSAccountWorkload(cfg),
+		misses:   make(map[string]struct{}),
+	}
+}
+
+func (w *AccountExistenceWorkload) Name() string {
+	return "Account-Existence"
+}
+
+func (w *AccountExistenceWorkload) GetDescription() string {
+	return fmt.Sprintf("Account existence checks: %.0f%% of reads hit written accounts, the rest miss on fresh addresses",
+		w.hitRatio*100)
+}
+
+// Stats reports the configured mix of the read phase
+func (w *AccountExistenceWorkload) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"hit_ratio":  w.hitRatio,
+		"miss_ratio": 1 - w.hitRatio,
+	}
+}
+
+// accountKey returns the snapshot key of address
+func (w *AccountExistenceWorkload) accountKey(address []byte) []byte {
+	return append([]byte("a"), crypto.Keccak256(address)...)
+}
+
+// GenerateKeys writes the count accounts of the universe of seed
+func (w *AccountExistenceWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		accounts := NewAccountUniverse(seed, count, 0)
+		for i := 0; i < count; i++ {
+			if !yield(w.accountKey(accounts.Address(i))) {
+				return
+			}
+		}
+	}
+}
+
+// GenerateReadKeys reads a random written account with probability
+// ExistenceHitRatio and the next fresh address otherwise. The written accounts
+// are the count accounts GenerateKeys writes for seed, which the runner
+// requires the write phase to write in full.
+func (w *AccountExistenceWorkload) GenerateReadKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+		accounts := NewAccountUniverse(seed, count, 0)
+		fresh := NewAccountUniverse(seed^existenceMissSeed, count, 0)
+		misses := 0
+		for i := 0; i < count; i++ {
+			var key []byte
+			if rng.Float64() < w.hitRatio {
+				key = w.accountKey(accounts.Address(rng.Intn(count)))
+			} else {
+				key = w.accountKey(fresh.Address(misses))
+				misses++
+				w.mu.Lock()
+				w.misses[string(key)] = struct{}{}
+				w.mu.Unlock()
+			}
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func (w *AccountExistenceWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	return w.state.generateAccountValue(rng)
+}
+
+// IgnoresValueSize reports true: values are encoded accounts
+func (w *AccountExistenceWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyClasses separates the latency of hits from that of misses
+func (w *AccountExistenceWorkload) KeyClasses() []string {
+	return existenceClasses
+}
+
+// KeyClass reports a miss for the fresh addresses GenerateReadKeys produced
+// and a hit for every other key
+func (w *AccountExistenceWorkload) KeyClass(key []byte) string {
+	if len(key) == 0 {
+		return ""
+	}
+	w.mu.Lock()
+	_, miss := w.misses[string(key)]
+	w.mu.Unlock()
+	if miss {
+		return keyClassMiss
+	}
+	return keyClassHit
+}
+
+// ShouldRead reports true: every check is a read
+func (w *AccountExistenceWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return true
+}
+
+func (w *AccountExistenceWorkload) SupportsRangeQueries() bool {
+	return false
+}
+
+func (w *AccountExistenceWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	return nil, nil, 0
+}
@@ -0,0 +1,43 @@
+package benchmark
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAccountExistenceReadsHitOnlyWrittenAccounts(t *testing.T) {
+	w := NewAccountExistenceWorkload(WorkloadConfig{Seed: 1, ExistenceHitRatio: 0.3})
+
+	accounts := NewAccountUniverse(1, 5000, 0)
+	written := make(map[string]bool)
+	i := 0
+	for key := range w.GenerateKeys(1, 5000) {
+		if want := append([]byte("a"), crypto.Keccak256(accounts.Address(i))...); !bytes.Equal(key, want) {
+			t.Fatalf("account %d written under %x, want a + keccak(address) %x", i, key, want)
+		}
+		if w.KeyClass(key) != keyClassHit {
+			t.Fatalf("written key %x classified as %s", key, w.KeyClass(key))
+		}
+		written[string(key)] = true
+		i++
+	}
+	if len(written) != 5000 {
+		t.Fatalf("%d distinct accounts written, want 5000", len(written))
+	}
+
+	var hits int
+	for key := range w.GenerateReadKeys(1, 5000) {
+		class := w.KeyClass(key)
+		if written[string(key)] != (class == keyClassHit) {
+			t.Fatalf("read key %x classified as %s, written: %v", key, class, written[string(key)])
+		}
+		if class == keyClassHit {
+			hits++
+		}
+	}
+	if hits < 1300 || hits > 1700 {
+		t.Fatalf("%d of 5000 reads hit, want about 1500", hits)
+	}
+}
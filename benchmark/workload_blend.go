@@ -0,0 +1,325 @@
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"iter"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WorkloadBlend composes other workloads by weight, configured with --blend
+const WorkloadBlend WorkloadType = "blend"
+
+// blendWeightTolerance is how far the blend weights may sum away from 1.0
+const blendWeightTolerance = 0.01
+
+// blendableWorkloads lists the workload types that may appear in a blend spec
+var blendableWorkloads = []WorkloadType{
+	WorkloadGeneric,
+	WorkloadPoSBlocks,
+	WorkloadPoSAccounts,
+	WorkloadPoSState,
+	WorkloadPoSMixed,
+	WorkloadPoSAccountsReal,
+	WorkloadPoSStateReal,
+	WorkloadTransactionExecution,
+	WorkloadUpdate,
+	WorkloadStorageTrie,
+	WorkloadMixedValues,
+	WorkloadReceiptIndex,
+	WorkloadGethSchema,
+	WorkloadMerkleProof,
+	WorkloadStorageDump,
+}
+
+// BlendComponent is one weighted workload of a blend
+type BlendComponent struct {
+	Type   WorkloadType
+	Weight float64
+}
+
+// ParseBlendSpec parses a spec like "pos-blocks:0.2,pos-accounts:0.5,pos-state:0.3"
+func ParseBlendSpec(spec string) ([]BlendComponent, error) {
+	var components []BlendComponent
+	total := 0.0
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, weightStr, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid blend entry %q: expected workload:weight", part)
+		}
+
+		workloadType := WorkloadType(strings.TrimSpace(name))
+		if !slices.Contains(blendableWorkloads, workloadType) {
+			return nil, fmt.Errorf("invalid blend entry %q: unknown workload %q", part, workloadType)
+		}
+
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blend entry %q: %w", part, err)
+		}
+		if weight <= 0 {
+			return nil, fmt.Errorf("invalid blend entry %q: weight must be positive", part)
+		}
+
+		components = append(components, BlendComponent{Type: workloadType, Weight: weight})
+		total += weight
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("blend spec %q has no workloads", spec)
+	}
+	if math.Abs(total-1.0) > blendWeightTolerance {
+		return nil, fmt.Errorf("blend weights sum to %.3f, expected 1.0", total)
+	}
+
+	return components, nil
+}
+
+// BlendWorkload interleaves keys from several workloads according to their weights.
+// Keys are streamed lazily from each sub-workload's iterator.
+type BlendWorkload struct {
+	config     WorkloadConfig
+	components []BlendComponent
+	workloads  []Workload
+	weights    []float64
+
+	// routes holds the KeyPrefixes of every component, longest first
+	routes []blendRoute
+	// unprefixed is the index of the only component without KeyPrefixes, the
+	// owner of keys matching no route, or -1
+	unprefixed int
+}
+
+// blendRoute sends keys starting with prefix to the component at index
+type blendRoute struct {
+	prefix    []byte
+	component int
+}
+
+// NewBlendWorkload creates a blend from cfg.Blend
+func NewBlendWorkload(cfg WorkloadConfig) (*BlendWorkload, error) {
+	components, err := ParseBlendSpec(cfg.Blend)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &BlendWorkload{
+		config:     cfg,
+		components: components,
+	}
+	for _, c := range components {
+		subCfg := cfg
+		subCfg.Type = c.Type
+		subCfg.Blend = ""
+		w.workloads = append(w.workloads, CreateWorkload(subCfg))
+		w.weights = append(w.weights, c.Weight)
+	}
+	if err := w.buildRoutes(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// buildRoutes registers the key prefixes of every component, rejecting blends
+// in which two components write keys under the same prefix
+func (w *BlendWorkload) buildRoutes() error {
+	w.unprefixed = -1
+	unprefixed := 0
+	owners := make(map[string]int)
+	for i, workload := range w.workloads {
+		prefixer, ok := workload.(KeyPrefixer)
+		if !ok {
+			w.unprefixed = i
+			unprefixed++
+			continue
+		}
+		for _, prefix := range prefixer.KeyPrefixes() {
+			if owner, ok := owners[string(prefix)]; ok {
+				if owner == i {
+					continue
+				}
+				return fmt.Errorf("blend workloads %s and %s both write keys under prefix %q",
+					w.components[owner].Type, w.components[i].Type, prefix)
+			}
+			owners[string(prefix)] = i
+			w.routes = append(w.routes, blendRoute{prefix: prefix, component: i})
+		}
+	}
+	if unprefixed > 1 {
+		// Keys of several unprefixed components cannot be told apart
+		w.unprefixed = -1
+	}
+
+	// Longest first, so "account_leaf" wins over "account"
+	slices.SortStableFunc(w.routes, func(a, b blendRoute) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+	return nil
+}
+
+func (w *BlendWorkload) Name() string {
+	return "Blend"
+}
+
+func (w *BlendWorkload) GetDescription() string {
+	parts := make([]string, len(w.components))
+	for i, c := range w.components {
+		parts[i] = fmt.Sprintf("%s %.0f%%", w.workloads[i].Name(), c.Weight*100)
+	}
+	return "Weighted blend of workloads: " + strings.Join(parts, ", ")
+}
+
+// GenerateKeys picks a sub-workload by weight for every key and pulls its next key.
+// Exhausted sub-workloads drop out and the remaining weights are renormalized.
+func (w *BlendWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+
+		nexts := make([]func() ([]byte, bool), len(w.workloads))
+		for i, workload := range w.workloads {
+			next, stop := iter.Pull(workload.GenerateKeys(seed+int64(i)+1, count))
+			defer stop()
+			nexts[i] = next
+		}
+		weights := slices.Clone(w.weights)
+
+		for keysGenerated := 0; keysGenerated < count; {
+			idx := selectWeightedIndex(rng, weights)
+			if idx < 0 {
+				return
+			}
+
+			key, ok := nexts[idx]()
+			if !ok {
+				weights[idx] = 0
+				continue
+			}
+
+			if !yield(key) {
+				return
+			}
+			keysGenerated++
+		}
+	}
+}
+
+// route returns the sub-workload registering the longest prefix of key, the
+// unprefixed component when no prefix matches, or nil when neither exists.
+// Keys of an unprefixed component that happen to start with a registered
+// prefix are routed to that prefix's owner.
+func (w *BlendWorkload) route(key []byte) Workload {
+	if len(key) == 0 {
+		return nil
+	}
+	for _, r := range w.routes {
+		if bytes.HasPrefix(key, r.prefix) {
+			return w.workloads[r.component]
+		}
+	}
+	if w.unprefixed >= 0 {
+		return w.workloads[w.unprefixed]
+	}
+	return nil
+}
+
+func (w *BlendWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	if workload := w.route(key); workload != nil {
+		return workload.GenerateValue(rng, key)
+	}
+
+	// Fallback to random value for keys not produced by this blend
+	value := make([]byte, w.config.ValueSize)
+	rng.Read(value)
+	return value
+}
+
+// IgnoresValueSize reports true only when every component ignores --value-size
+func (w *BlendWorkload) IgnoresValueSize() bool {
+	for _, workload := range w.workloads {
+		if ignorer, ok := workload.(ValueSizeIgnorer); !ok || !ignorer.IgnoresValueSize() {
+			return false
+		}
+	}
+	return true
+}
+
+// KeysIgnoreSeed reports true when any component generates the same keys for
+// every seed
+func (w *BlendWorkload) KeysIgnoreSeed() bool {
+	for _, workload := range w.workloads {
+		if fixed, ok := workload.(SeedIndependentKeys); ok && fixed.KeysIgnoreSeed() {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *BlendWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	if workload := w.route(key); workload != nil {
+		return workload.ShouldRead(key, rng)
+	}
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *BlendWorkload) SupportsRangeQueries() bool {
+	for _, workload := range w.workloads {
+		if workload.SupportsRangeQueries() {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateRangeQuery picks a range-capable sub-workload by weight
+func (w *BlendWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	weights := make([]float64, len(w.workloads))
+	for i, workload := range w.workloads {
+		if workload.SupportsRangeQueries() {
+			weights[i] = w.weights[i]
+		}
+	}
+
+	idx := selectWeightedIndex(rng, weights)
+	if idx < 0 {
+		return nil, nil, 0
+	}
+	return w.workloads[idx].GenerateRangeQuery(rng)
+}
+
+// selectWeightedIndex returns an index chosen proportionally to weights,
+// or -1 when every weight is zero
+func selectWeightedIndex(rng *rand.Rand, weights []float64) int {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	r := rng.Float64() * total
+	cumulative := 0.0
+	last := -1
+	for i, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		cumulative += weight
+		last = i
+		if r < cumulative {
+			return i
+		}
+	}
+	return last
+}
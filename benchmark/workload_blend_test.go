@@ -0,0 +1,90 @@
+package benchmark
+
+import (
+	"bytes"
+	"testing"
+)
+
+// blendTestConfig returns a workload config with every tunable at its default
+func blendTestConfig(blend string) WorkloadConfig {
+	return WorkloadConfig{
+		ValueSize:          64,
+		ReadRatio:          0.5,
+		Seed:               42,
+		Blend:              blend,
+		RecentBlockBias:    0.8,
+		HotAccountRatio:    0.1,
+		StateLocality:      0.5,
+		BlockRange:         1000,
+		AccountCount:       1000,
+		StorageSlotRatio:   2,
+		Keyspace:           500,
+		ContractCount:      10,
+		LargeValueRatio:    0.1,
+		NetworkType:        "ethereum",
+		TransactionMix:     "balanced",
+		TxHotAccountProb:   -1,
+		TxStorageLocality:  -1,
+		TxCacheHitRatio:    -1,
+		TxAccountTrieDepth: -1,
+		TxStorageTrieDepth: -1,
+		TxReadWriteRatio:   -1,
+		TxContractRatio:    -1,
+		TxPerBlock:         2,
+		GasTargetPerBlock:  15000000,
+	}
+}
+
+func TestKeyPrefixesCoverGeneratedKeys(t *testing.T) {
+	quietLogs(t)
+
+	for _, workloadType := range blendableWorkloads {
+		cfg := blendTestConfig("")
+		cfg.Type = workloadType
+		workload := CreateWorkload(cfg)
+		prefixer, ok := workload.(KeyPrefixer)
+		if !ok {
+			continue
+		}
+		prefixes := prefixer.KeyPrefixes()
+
+	keys:
+		for key := range workload.GenerateKeys(1, 2000) {
+			for _, prefix := range prefixes {
+				if bytes.HasPrefix(key, prefix) {
+					continue keys
+				}
+			}
+			t.Errorf("%s: key %q starts with none of %q", workloadType, key, prefixes)
+			break
+		}
+	}
+}
+
+func TestBlendRoutesKeysByPrefix(t *testing.T) {
+	quietLogs(t)
+
+	// Both write keys starting with "a", "s" and "t"
+	w, err := NewBlendWorkload(blendTestConfig("pos-accounts-realistic:0.5,pos-state-realistic:0.5"))
+	if err != nil {
+		t.Fatalf("NewBlendWorkload: %v", err)
+	}
+
+	// Keys generated by the components directly, not through the blend
+	for i, workload := range w.workloads {
+		for key := range workload.GenerateKeys(7, 2000) {
+			if got := w.route(key); got != workload {
+				t.Fatalf("key %q of component %d routed to %v", key, i, got)
+			}
+		}
+	}
+}
+
+func TestBlendRejectsSharedPrefixes(t *testing.T) {
+	if _, err := NewBlendWorkload(blendTestConfig("pos-blocks:0.5,geth-schema:0.5")); err == nil {
+		t.Fatal("pos-blocks and geth-schema share the header prefix, want an error")
+	}
+	if _, err := NewBlendWorkload(blendTestConfig("pos-blocks:0.2,pos-accounts:0.5,pos-state:0.3")); err != nil {
+		t.Fatalf("disjoint prefixes: %v", err)
+	}
+}
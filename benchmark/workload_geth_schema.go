@@ -0,0 +1,383 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/big"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// WorkloadGethSchema lays keys out exactly as geth's core/rawdb schema does
+const WorkloadGethSchema WorkloadType = "geth-schema"
+
+const (
+	// Transactions per block, each touching one account
+	gethSchemaMinTxs = 20
+	gethSchemaMaxTxs = 150
+
+	// gethSchemaMaxSlots is the most storage slots one transaction writes
+	gethSchemaMaxSlots = 3
+
+	// Depths of the path-scheme trie nodes rewritten for every touched leaf
+	gethSchemaAccountTrieDepth = 7
+	gethSchemaStorageTrieDepth = 3
+
+	// Chance that a transaction deploys code or records preimages
+	gethSchemaCodeProb     = 0.01
+	gethSchemaPreimageProb = 0.05
+
+	// gethSchemaMaxCodeSize is the EIP-170 contract size limit
+	gethSchemaMaxCodeSize = 24576
+
+	// gethSchemaMaxWindow is the widest block window a header scan covers
+	gethSchemaMaxWindow = 128
+)
+
+// Key categories of the geth schema, one per core/rawdb prefix
+const (
+	gethHeader          = iota // "h" + num + hash, and "h" + num + "n" for the canonical hash
+	gethHeaderNumber           // "H" + hash -> num
+	gethBody                   // "b" + num + hash
+	gethReceipts               // "r" + num + hash
+	gethTxLookup               // "l" + tx hash -> num
+	gethSnapshotAccount        // "a" + account hash
+	gethSnapshotStorage        // "o" + account hash + slot hash
+	gethAccountTrieNode        // "A" + nibble path
+	gethStorageTrieNode        // "O" + account hash + nibble path
+	gethCode                   // "c" + code hash
+	gethPreimage               // "secure-key-" + hash
+)
+
+// gethHeaderHashSuffix ends the canonical hash key of a block number
+const gethHeaderHashSuffix = 'n'
+
+// gethSchemaPrefixes mirrors the prefixes in geth's core/rawdb/schema.go
+var gethSchemaPrefixes = func() keyPrefixTable {
+	t, err := newKeyPrefixTable(
+		keyPrefix{gethHeader, []byte("h")},
+		keyPrefix{gethHeaderNumber, []byte("H")},
+		keyPrefix{gethBody, []byte("b")},
+		keyPrefix{gethReceipts, []byte("r")},
+		keyPrefix{gethTxLookup, []byte("l")},
+		keyPrefix{gethSnapshotAccount, []byte("a")},
+		keyPrefix{gethSnapshotStorage, []byte("o")},
+		keyPrefix{gethAccountTrieNode, []byte("A")},
+		keyPrefix{gethStorageTrieNode, []byte("O")},
+		keyPrefix{gethCode, []byte("c")},
+		keyPrefix{gethPreimage, []byte("secure-key-")},
+	)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
+
+// gethSchemaClasses names the key classes reported by GethSchemaWorkload.KeyClass,
+// indexed by key category
+var gethSchemaClasses = []string{
+	gethHeader:          "header",
+	gethHeaderNumber:    "header-number",
+	gethBody:            "body",
+	gethReceipts:        "receipts",
+	gethTxLookup:        "tx-lookup",
+	gethSnapshotAccount: "snapshot-account",
+	gethSnapshotStorage: "snapshot-storage",
+	gethAccountTrieNode: "account-trie",
+	gethStorageTrieNode: "storage-trie",
+	gethCode:            "code",
+	gethPreimage:        "preimage",
+}
+
+// GethSchemaWorkload imports a chain block by block into a database laid out
+// byte for byte like geth's: chain data under the same single-byte prefixes,
+// big-endian block numbers and 32-byte hashes, path-scheme trie nodes and
+// snapshot entries keyed by hashed accounts and slots. Compaction and scans
+// then see the same key distribution a production node produces.
+type GethSchemaWorkload struct {
+	config   WorkloadConfig
+	accounts *AccountUniverse
+	blocks   *PoSBlockWorkload   // header, body and receipt encoding
+	state    *PoSAccountWorkload // trie node encoding
+
+	imported atomic.Uint64 // blocks yielded by the last key generation
+	ended    atomic.Uint64 // blocks whose last trie node has been yielded
+}
+
+// NewGethSchemaWorkload creates a new geth on-disk schema workload
+func NewGethSchemaWorkload(cfg WorkloadConfig) *GethSchemaWorkload {
+	return &GethSchemaWorkload{
+		config:   cfg,
+		accounts: newStateAccountUniverse(cfg),
+		blocks:   NewPoSBlockWorkload(cfg),
+		state:    NewPoSAccountWorkload(cfg),
+	}
+}
+
+func (w *GethSchemaWorkload) Name() string {
+	return "Geth-Schema"
+}
+
+func (w *GethSchemaWorkload) GetDescription() string {
+	return fmt.Sprintf("Block-by-block import into geth's core/rawdb key layout (%d-%d txs per block, %d accounts)",
+		gethSchemaMinTxs, gethSchemaMaxTxs, w.accounts.Count())
+}
+
+// BlocksEnded counts the blocks imported up to their last trie node
+func (w *GethSchemaWorkload) BlocksEnded() uint64 {
+	return w.ended.Load()
+}
+
+// gethSchemaHash returns a 32-byte hash whose trailing 8 bytes are num, so
+// values that point back at a block number can be derived from the hash alone
+// while the leading bytes still scatter keys like a real hash
+func gethSchemaHash(num uint64, parts ...[]byte) []byte {
+	hash := crypto.Keccak256(append(parts, binary.BigEndian.AppendUint64(nil, num))...)
+	binary.BigEndian.PutUint64(hash[24:], num)
+	return hash
+}
+
+// gethNibbles returns the first n nibbles of hash, one per byte, the way
+// path-scheme trie node keys spell out their path
+func gethNibbles(hash []byte, n int) []byte {
+	nibbles := make([]byte, n)
+	for i := range nibbles {
+		if i%2 == 0 {
+			nibbles[i] = hash[i/2] >> 4
+		} else {
+			nibbles[i] = hash[i/2] & 0x0f
+		}
+	}
+	return nibbles
+}
+
+// GenerateKeys imports consecutive blocks starting at genesis. Each block
+// writes its chain data, a transaction lookup, snapshot account and storage
+// entries per transaction, and finally the trie nodes on the paths to every
+// leaf it touched, each node once per block like a path-scheme commit.
+func (w *GethSchemaWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+		seedBytes := binary.BigEndian.AppendUint64(nil, uint64(seed))
+		t := gethSchemaPrefixes
+		w.imported.Store(0)
+		w.ended.Store(0)
+
+		generated := 0
+		emit := func(key []byte) bool {
+			if generated >= count || !yield(key) {
+				return false
+			}
+			generated++
+			return true
+		}
+
+		for num := uint64(0); generated < count; num++ {
+			w.imported.Store(num + 1)
+			numBytes := binary.BigEndian.AppendUint64(nil, num)
+			hash := gethSchemaHash(num, seedBytes)
+
+			chain := [][]byte{
+				t.key(gethHeader, numBytes, []byte{gethHeaderHashSuffix}),
+				t.key(gethHeader, numBytes, hash),
+				t.key(gethHeaderNumber, hash),
+				t.key(gethBody, numBytes, hash),
+				t.key(gethReceipts, numBytes, hash),
+			}
+			for _, key := range chain {
+				if !emit(key) {
+					return
+				}
+			}
+
+			// Trie nodes are collected in first-touch order so the set is
+			// deterministic and each node is written once per block
+			var nodes [][]byte
+			seen := make(map[string]struct{})
+			touch := func(key []byte) {
+				if _, ok := seen[string(key)]; !ok {
+					seen[string(key)] = struct{}{}
+					nodes = append(nodes, key)
+				}
+			}
+
+			txs := gethSchemaMinTxs + rng.Intn(gethSchemaMaxTxs-gethSchemaMinTxs+1)
+			for i := 0; i < txs; i++ {
+				if !emit(t.key(gethTxLookup, gethSchemaHash(num, seedBytes, binary.BigEndian.AppendUint32(nil, uint32(i))))) {
+					return
+				}
+
+				address := w.accounts.Pick(rng, 0.8)
+				accountHash := crypto.Keccak256(address)
+				if !emit(t.key(gethSnapshotAccount, accountHash)) {
+					return
+				}
+				for depth := 0; depth <= gethSchemaAccountTrieDepth; depth++ {
+					touch(t.key(gethAccountTrieNode, gethNibbles(accountHash, depth)))
+				}
+				if rng.Float64() < gethSchemaPreimageProb && !emit(t.key(gethPreimage, accountHash)) {
+					return
+				}
+				if rng.Float64() < gethSchemaCodeProb && !emit(t.key(gethCode, crypto.Keccak256(address, numBytes))) {
+					return
+				}
+
+				for s := rng.Intn(gethSchemaMaxSlots + 1); s > 0; s-- {
+					slot := make([]byte, 32)
+					binary.BigEndian.PutUint64(slot[24:], uint64(rng.Intn(1000)))
+					slotHash := crypto.Keccak256(slot)
+					if !emit(t.key(gethSnapshotStorage, accountHash, slotHash)) {
+						return
+					}
+					for depth := 0; depth <= gethSchemaStorageTrieDepth; depth++ {
+						touch(t.key(gethStorageTrieNode, accountHash, gethNibbles(slotHash, depth)))
+					}
+				}
+			}
+
+			for i, key := range nodes {
+				if i == len(nodes)-1 {
+					w.ended.Store(num + 1)
+				}
+				if !emit(key) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *GethSchemaWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	category, payload := gethSchemaPrefixes.classifyKey(key)
+	switch category {
+	case gethHeader:
+		if len(payload) == 9 && payload[8] == gethHeaderHashSuffix {
+			// Canonical hash of the block number
+			return gethSchemaHash(binary.BigEndian.Uint64(payload), binary.BigEndian.AppendUint64(nil, uint64(w.config.Seed)))
+		}
+		return w.blocks.generateBlockHeaderValue(rng)
+	case gethHeaderNumber:
+		// 8-byte big-endian block number, carried in the hash's tail
+		return bytes.Clone(payload[24:])
+	case gethBody:
+		return w.blocks.generateBlockBodyValue(rng)
+	case gethReceipts:
+		return w.blocks.generateReceiptsValue(rng)
+	case gethTxLookup:
+		// Block number with leading zeros stripped, as geth writes it
+		return new(big.Int).SetBytes(payload[24:]).Bytes()
+	case gethSnapshotAccount:
+		return w.generateSlimAccount(rng)
+	case gethSnapshotStorage:
+		// RLP of the slot value with leading zeros stripped
+		value := make([]byte, 1+rng.Intn(32))
+		rng.Read(value)
+		encoded, _ := rlp.EncodeToBytes(bytes.TrimLeft(value, "\x00"))
+		return encoded
+	case gethAccountTrieNode, gethStorageTrieNode:
+		return w.state.generateTrieNodeValue(rng)
+	case gethCode:
+		code := make([]byte, 1+rng.Intn(gethSchemaMaxCodeSize))
+		rng.Read(code)
+		return code
+	case gethPreimage:
+		// Preimage of an address or a storage slot
+		preimage := make([]byte, 20)
+		if rng.Intn(2) == 0 {
+			preimage = make([]byte, 32)
+		}
+		rng.Read(preimage)
+		return preimage
+	}
+	return generateValue(rng, w.config.ValueSize)
+}
+
+// generateSlimAccount encodes an account in the snapshot's slim format, where
+// the empty storage root and code hash of a plain account are left empty
+func (w *GethSchemaWorkload) generateSlimAccount(rng *rand.Rand) []byte {
+	account := struct {
+		Nonce    uint64
+		Balance  *big.Int
+		Root     []byte
+		CodeHash []byte
+	}{
+		Nonce:   uint64(rng.Intn(1 << 20)),
+		Balance: big.NewInt(rng.Int63()),
+	}
+	if rng.Float64() < 0.2 {
+		account.Root = make([]byte, 32)
+		account.CodeHash = make([]byte, 32)
+		rng.Read(account.Root)
+		rng.Read(account.CodeHash)
+	}
+	encoded, _ := rlp.EncodeToBytes(account)
+	return encoded
+}
+
+// IgnoresValueSize reports true: every schema entry carries its own size
+func (w *GethSchemaWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the prefixes of geth's schema
+func (w *GethSchemaWorkload) KeyPrefixes() [][]byte {
+	return gethSchemaPrefixes.prefixes()
+}
+
+// HotKeyPrefixes returns the snapshot account and storage prefixes of the hot accounts
+func (w *GethSchemaWorkload) HotKeyPrefixes() [][]byte {
+	t := gethSchemaPrefixes
+	return hotAccountPrefixes(w.accounts, t.key(gethSnapshotAccount), t.key(gethSnapshotStorage))
+}
+
+// KeyClasses lists the schema tables, so reads are reported per table
+func (w *GethSchemaWorkload) KeyClasses() []string {
+	return gethSchemaClasses
+}
+
+func (w *GethSchemaWorkload) KeyClass(key []byte) string {
+	if category, _ := gethSchemaPrefixes.classifyKey(key); category != keyCategoryUnknown {
+		return gethSchemaClasses[category]
+	}
+	return ""
+}
+
+func (w *GethSchemaWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *GethSchemaWorkload) SupportsRangeQueries() bool {
+	return true
+}
+
+// GenerateRangeQuery either scans the headers of a window of imported blocks,
+// like a header sync serving peers, or iterates a run of snapshot accounts
+// from a random hash, like snap sync serving account ranges
+func (w *GethSchemaWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	t := gethSchemaPrefixes
+	if rng.Intn(2) == 0 {
+		origin := make([]byte, 32)
+		rng.Read(origin)
+		limit = rng.Intn(gethSchemaMaxWindow) + 1
+		return t.key(gethSnapshotAccount, origin), t.key(gethSnapshotAccount, bytes.Repeat([]byte{0xff}, 32)), limit
+	}
+
+	imported := w.imported.Load()
+	if imported == 0 {
+		imported = uint64(w.blocks.config.BlockRange)
+	}
+	window := uint64(rng.Intn(gethSchemaMaxWindow) + 1)
+	if window > imported {
+		window = imported
+	}
+	from := uint64(rng.Int63n(int64(imported - window + 1)))
+	start = t.key(gethHeader, binary.BigEndian.AppendUint64(nil, from))
+	end = t.key(gethHeader, binary.BigEndian.AppendUint64(nil, from+window))
+	// Each block holds a header and its canonical hash
+	return start, end, int(window) * 2
+}
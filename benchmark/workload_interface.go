@@ -29,6 +29,27 @@ type Workload interface {
 	GetDescription() string
 }
 
+// ValueSizeIgnorer is implemented by workloads that size their values by key
+// type rather than by WorkloadConfig.ValueSize. RunBenchmark warns that
+// --value-size has no effect when IgnoresValueSize returns true.
+type ValueSizeIgnorer interface {
+	IgnoresValueSize() bool
+}
+
+// KeyPrefixer is implemented by workloads whose keys all start with one of a
+// fixed set of prefixes. BlendWorkload routes a key to the component that
+// registered the longest prefix the key starts with.
+type KeyPrefixer interface {
+	KeyPrefixes() [][]byte
+}
+
+// WriteObserver is implemented by workloads that account for the writes the
+// write phase actually issued, rather than for the keys they generated.
+// Workers call ObserveWrite concurrently after every successful write.
+type WriteObserver interface {
+	ObserveWrite(key []byte)
+}
+
 // WorkloadType represents available workload types
 type WorkloadType string
 
@@ -41,6 +62,8 @@ const (
 	WorkloadPoSAccountsReal   WorkloadType = "pos-accounts-realistic"
 	WorkloadPoSStateReal      WorkloadType = "pos-state-realistic"
 	WorkloadTransactionExecution WorkloadType = "transaction-execution"
+	WorkloadUpdate            WorkloadType = "update"
+	WorkloadStorageTrie       WorkloadType = "storage-trie"
 )
 
 // WorkloadConfig contains configuration specific to workloads
@@ -49,15 +72,45 @@ type WorkloadConfig struct {
 	ValueSize       int     // Base value size in bytes
 	ReadRatio       float64 // Ratio of reads vs writes
 	Seed            int64   // RNG seed for deterministic behavior
+	Blend           string  // Weighted workload blend spec, e.g. "pos-blocks:0.2,pos-state:0.8"
 	
 	// PoS-specific configuration
 	RecentBlockBias  float64 // Probability of accessing recent blocks (0.0-1.0)
 	HotAccountRatio  float64 // Ratio of "hot" accounts that get most access
+	HotAccountCount  int     // Exact number of hot accounts, overrides the derived count when > 0
 	StateLocality    float64 // Probability of accessing related state
 	BlockRange       int     // Range of block numbers to simulate
+	BlockKeyLayout   string  // Block number encoding in block keys: "be" (default) or "le"
 	AccountCount     int     // Number of unique accounts to simulate
 	StorageSlotRatio float64 // Average storage slots per account
 	
+	// Update workload configuration
+	Keyspace int // Number of unique keys populated before overwrites begin
+
+	// Reorg workload configuration
+	ReorgInterval int // Blocks appended between reorgs, 0 for the default (100)
+	ReorgDepth    int // Most recent blocks each reorg replaces, 0 for the default (2)
+
+	// Account existence workload configuration
+	ExistenceHitRatio float64 // Fraction of reads targeting written accounts, the rest miss (0.0-1.0)
+
+	// Trie simulation depth, used by the pos-accounts-realistic and storage-trie workloads
+	TrieAverageDepth      int    // Average state trie depth, 0 for the default (6)
+	TrieMaxDepth          int    // Maximum state trie depth, 0 for the default (16)
+	TrieDepthVariance     *int   // Spread of the per-path depth, nil for the default (2)
+	TrieDepthDistribution string // Depth distribution: uniform or normal
+
+	// Trie node values: random or rlp, empty for each workload's default
+	TrieNodeEncoding string
+
+	// Storage trie workload configuration
+	StorageTrieDepth int // Storage trie depth, 0 for the simulation default
+	ContractCount    int // Number of contracts whose storage tries are traversed
+
+	// Mixed value sizes workload configuration
+	LargeValueRatio float64 // Fraction of keys holding large values (0.0-1.0)
+	LargeValueSize  int     // Size of large values in bytes, 0 for the default
+
 	// Transaction execution workload configuration
 	NetworkType              string  // Network type: ethereum, polygon, custom
 	TransactionMix           string  // Transaction mix: balanced, defi-heavy, transfer-heavy
@@ -68,6 +121,7 @@ type WorkloadConfig struct {
 	TxStorageTrieDepth       int     // Storage trie depth for transaction workload
 	TxReadWriteRatio         float64 // Read/write ratio for transaction workload
 	TxContractRatio          float64 // Contract ratio for transaction workload
+	TxStorageOpMultiplier    float64 // Scale of storage operations per transaction, 0 for 1
 	TxPerBlock               int     // Transactions per block
 	GasTargetPerBlock        uint64  // Target gas per block
 	TxSimpleTransferRatio    float64 // Simple transfer ratio in transaction mix
@@ -75,6 +129,22 @@ type WorkloadConfig struct {
 	TxUniswapSwapRatio       float64 // Uniswap swap ratio in transaction mix
 	TxComplexDeFiRatio       float64 // Complex DeFi ratio in transaction mix
 	TxContractDeployRatio    float64 // Contract deployment ratio in transaction mix
+
+	// Per key prefix read probabilities overriding pos-mixed's built-in ones
+	ReadRatios map[string]float64
+
+	// Block commit shape for the transaction workload, zero values use the defaults
+	CommitOpsMin    int    // fewest operations written per block commit
+	CommitOpsMax    int    // most operations written per block commit
+	CommitKeyPrefix string // key prefix of block commit operations
+	CommitValueMin  int    // smallest block commit value in bytes
+	CommitValueMax  int    // largest block commit value in bytes
+
+	// State commit batching for the pos-accounts-realistic workload
+	CommitInterval          int    // logical operations between commits, 0 commits at random
+	CommitNodesMin          int    // fewest dirty nodes written per commit, 0 for the default
+	CommitNodesMax          int    // most dirty nodes written per commit, 0 for the default
+	CommitNodesDistribution string // dirty node count distribution: uniform or normal
 }
 
 // CreateWorkload creates a workload instance based on the type
@@ -94,6 +164,24 @@ func CreateWorkload(cfg WorkloadConfig) Workload {
 		return NewRealisticPoSStateWorkload(cfg)
 	case WorkloadTransactionExecution:
 		return NewTransactionExecutionWorkload(cfg)
+	case WorkloadUpdate:
+		return NewUpdateWorkload(cfg)
+	case WorkloadStorageTrie:
+		return NewStorageTrieWorkload(cfg)
+	case WorkloadMixedValues:
+		return NewMixedValueWorkload(cfg)
+	case WorkloadReceiptIndex:
+		return NewReceiptIndexWorkload(cfg)
+	case WorkloadGethSchema:
+		return NewGethSchemaWorkload(cfg)
+	case WorkloadMerkleProof:
+		return NewMerkleProofWorkload(cfg)
+	case WorkloadStorageDump:
+		return NewStorageDumpWorkload(cfg)
+	case WorkloadReorg:
+		return NewReorgWorkload(cfg)
+	case WorkloadAccountExistence:
+		return NewAccountExistenceWorkload(cfg)
 	case WorkloadGeneric:
 		fallthrough
 	default:
@@ -0,0 +1,201 @@
+package benchmark
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// WorkloadMerkleProof reads the trie paths eth_getProof walks to prove accounts
+const WorkloadMerkleProof WorkloadType = "merkle-proof"
+
+// merkleProofSiblings is how many other children a full branch node holds
+// besides the one on the proven path
+const merkleProofSiblings = 15
+
+// Key categories of the merkle-proof workload
+const (
+	merkleProofTrieNode = iota // "trie" + nibble path, the empty path being the root
+	merkleProofAccount         // "account" + account hash
+)
+
+// merkleProofPrefixes are the prefixes TrieSimulation gives trie nodes and accounts
+var merkleProofPrefixes, _ = newKeyPrefixTable(
+	keyPrefix{merkleProofTrieNode, []byte("trie")},
+	keyPrefix{merkleProofAccount, []byte("account")},
+)
+
+// merkleProofClasses names the key categories for per-class read latencies
+var merkleProofClasses = []string{"trie_node", "account"}
+
+// ReadStatsReporter is implemented by workloads that derive statistics from
+// the read phase. RunBenchmark logs the stats after the read phase.
+type ReadStatsReporter interface {
+	ReadStats(read *PhaseResult) map[string]interface{}
+}
+
+// MerkleProofWorkload models serving eth_getProof to light clients. Every
+// proof reads the state root, then at each level of a randomly chosen
+// account's trie path the node on the path and all its siblings, and finally
+// the account itself: a burst of reads down one path rather than a point lookup.
+type MerkleProofWorkload struct {
+	config         WorkloadConfig
+	trieSimulation *TrieSimulation
+	accounts       *AccountUniverse
+	state          *PoSAccountWorkload // trie node and account encoding
+
+	// Proofs, trie nodes and reads emitted by the last key generation
+	proofs atomic.Uint64
+	nodes  atomic.Uint64
+	reads  atomic.Uint64
+}
+
+// NewMerkleProofWorkload creates a proof generation workload
+func NewMerkleProofWorkload(cfg WorkloadConfig) *MerkleProofWorkload {
+	trieSimulation := NewTrieSimulation()
+	trieSimulation.configureDepth(cfg)
+	trieSimulation.configureNodeEncoding(cfg)
+
+	return &MerkleProofWorkload{
+		config:         cfg,
+		trieSimulation: trieSimulation,
+		accounts:       newStateAccountUniverse(cfg),
+		state:          NewPoSAccountWorkload(cfg),
+	}
+}
+
+func (w *MerkleProofWorkload) Name() string {
+	return "Merkle-Proof"
+}
+
+func (w *MerkleProofWorkload) GetDescription() string {
+	return fmt.Sprintf("eth_getProof account proofs: root, path and %d siblings per level, then the account (%d accounts, average depth %d)",
+		merkleProofSiblings, w.accounts.Count(), w.trieSimulation.averageDepth)
+}
+
+// Stats reports the shape of the generated proofs
+func (w *MerkleProofWorkload) Stats() map[string]interface{} {
+	stats := w.trieSimulation.depthStats()
+	proofs := w.proofs.Load()
+	stats["proofs"] = proofs
+	if proofs > 0 {
+		stats["nodes_per_proof"] = float64(w.nodes.Load()) / float64(proofs)
+		stats["reads_per_proof"] = float64(w.reads.Load()) / float64(proofs)
+	}
+	return stats
+}
+
+// ReadStats reports the proofs served by the read phase, which reads the
+// generated proofs back in order
+func (w *MerkleProofWorkload) ReadStats(read *PhaseResult) map[string]interface{} {
+	proofs := w.proofs.Load()
+	stats := map[string]interface{}{"proofs": proofs}
+	if proofs > 0 {
+		stats["nodes_read_per_proof"] = float64(w.nodes.Load()) / float64(proofs)
+	}
+	if read.Elapsed > 0 {
+		stats["proofs_per_sec"] = float64(proofs) / read.Elapsed.Seconds()
+	}
+	return stats
+}
+
+// proofKeys returns the keys read to prove the account at address: the root,
+// then level by level the node on the path and its siblings, then the account
+func (w *MerkleProofWorkload) proofKeys(address []byte) (keys [][]byte, nodes int) {
+	t := merkleProofPrefixes
+	addressHash := crypto.Keccak256(address)
+
+	keys = append(keys, t.key(merkleProofTrieNode))
+	for _, node := range w.trieSimulation.computeTriePath(addressHash) {
+		keys = append(keys, node)
+		parent, nibble := node[:len(node)-1], node[len(node)-1]
+		for sibling := byte(0); sibling < 16; sibling++ {
+			if sibling != nibble {
+				keys = append(keys, append(parent[:len(parent):len(parent)], sibling))
+			}
+		}
+	}
+	nodes = len(keys)
+	keys = append(keys, w.trieSimulation.computeAccountKey(addressHash))
+	return keys, nodes
+}
+
+// GenerateKeys emits the keys of one proof after another, so the read phase
+// replays each proof as a burst
+func (w *MerkleProofWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+		w.proofs.Store(0)
+		w.nodes.Store(0)
+		w.reads.Store(0)
+
+		generated := 0
+		for generated < count {
+			keys, nodes := w.proofKeys(w.accounts.Any(rng))
+			w.proofs.Add(1)
+			w.nodes.Add(uint64(nodes))
+			w.reads.Add(uint64(len(keys)))
+
+			for _, key := range keys {
+				if generated >= count || !yield(key) {
+					return
+				}
+				generated++
+			}
+		}
+	}
+}
+
+func (w *MerkleProofWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	switch category, _ := merkleProofPrefixes.classifyKey(key); category {
+	case merkleProofTrieNode:
+		return w.state.generateTrieNodeValue(rng)
+	case merkleProofAccount:
+		return w.state.generateAccountValue(rng)
+	}
+	return generateValue(rng, w.config.ValueSize)
+}
+
+// IgnoresValueSize reports true: values are trie nodes and accounts
+func (w *MerkleProofWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the trie node and account prefixes
+func (w *MerkleProofWorkload) KeyPrefixes() [][]byte {
+	return merkleProofPrefixes.prefixes()
+}
+
+// KeyClasses separates trie node reads from the final account reads
+func (w *MerkleProofWorkload) KeyClasses() []string {
+	return merkleProofClasses
+}
+
+func (w *MerkleProofWorkload) KeyClass(key []byte) string {
+	if category, _ := merkleProofPrefixes.classifyKey(key); category != keyCategoryUnknown {
+		return merkleProofClasses[category]
+	}
+	return ""
+}
+
+// ShouldRead reports true: a proof needs every node on its path
+func (w *MerkleProofWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return true
+}
+
+func (w *MerkleProofWorkload) SupportsRangeQueries() bool {
+	return true
+}
+
+// GenerateRangeQuery scans the nodes of a random subtrie, as a node syncing
+// state from a proof-serving peer would
+func (w *MerkleProofWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	b := byte(rng.Intn(256))
+	start = merkleProofPrefixes.key(merkleProofTrieNode, []byte{b >> 4, b & 0x0f})
+	end = prefixUpperBound(start)
+	limit = rng.Intn(100) + 10
+	return start, end, limit
+}
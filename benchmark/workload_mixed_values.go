@@ -0,0 +1,146 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// WorkloadMixedValues interleaves tiny storage slot values with large block bodies
+const WorkloadMixedValues WorkloadType = "mixed-values"
+
+const (
+	// mixedSmallValueSize matches a 32-byte storage slot
+	mixedSmallValueSize = 32
+	// defaultLargeValueSize is a typical block body when --large-value-size is unset
+	defaultLargeValueSize = 16 << 10
+
+	mixedSmallPrefix = "slot"
+	mixedLargePrefix = "body"
+
+	// Key classes reported by MixedValueWorkload.KeyClass
+	keyClassSmall = "small"
+	keyClassLarge = "large"
+)
+
+// KeyClassifier is implemented by workloads whose keys fall into classes with
+// different performance characteristics. The read phase reports latencies
+// separately for each class.
+type KeyClassifier interface {
+	// KeyClasses lists every class KeyClass may return
+	KeyClasses() []string
+	// KeyClass returns the class of a key generated by the workload
+	KeyClass(key []byte) string
+}
+
+// MixedValueWorkload interleaves small storage slot values and large block body
+// values at a configurable ratio to exercise value separation
+type MixedValueWorkload struct {
+	config     WorkloadConfig
+	largeRatio float64
+	largeSize  int
+}
+
+// NewMixedValueWorkload creates a new mixed value size workload
+func NewMixedValueWorkload(cfg WorkloadConfig) *MixedValueWorkload {
+	largeSize := cfg.LargeValueSize
+	if largeSize <= 0 {
+		largeSize = defaultLargeValueSize
+	}
+
+	return &MixedValueWorkload{
+		config:     cfg,
+		largeRatio: cfg.LargeValueRatio,
+		largeSize:  largeSize,
+	}
+}
+
+func (w *MixedValueWorkload) Name() string {
+	return "Mixed-Values"
+}
+
+func (w *MixedValueWorkload) GetDescription() string {
+	return fmt.Sprintf("Interleaves %d-byte storage slots with %d-byte block bodies (%.0f%% large)",
+		mixedSmallValueSize, w.largeSize, w.largeRatio*100)
+}
+
+// GenerateKeys yields a large value key with probability largeRatio and a
+// small value key otherwise. Small keys are hashed so they scatter across the
+// keyspace, while large keys are sequential block numbers.
+func (w *MixedValueWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+		var blocks, slots uint64
+
+		for i := 0; i < count; i++ {
+			var key []byte
+			if rng.Float64() < w.largeRatio {
+				key = binary.BigEndian.AppendUint64([]byte(mixedLargePrefix), blocks)
+				blocks++
+			} else {
+				var raw [16]byte
+				binary.BigEndian.PutUint64(raw[:8], uint64(w.config.Seed))
+				binary.BigEndian.PutUint64(raw[8:], slots)
+				key = append([]byte(mixedSmallPrefix), crypto.Keccak256(raw[:])...)
+				slots++
+			}
+
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// KeysIgnoreSeed reports true: block numbers and slot indexes count from zero
+func (w *MixedValueWorkload) KeysIgnoreSeed() bool {
+	return true
+}
+
+func (w *MixedValueWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	size := mixedSmallValueSize
+	if w.KeyClass(key) == keyClassLarge {
+		size = w.largeSize
+	}
+
+	value := make([]byte, size)
+	rng.Read(value)
+	return value
+}
+
+// IgnoresValueSize reports true: values are either small slots or --large-value-size bodies
+func (w *MixedValueWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the small slot and large body prefixes
+func (w *MixedValueWorkload) KeyPrefixes() [][]byte {
+	return [][]byte{[]byte(mixedSmallPrefix), []byte(mixedLargePrefix)}
+}
+
+func (w *MixedValueWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *MixedValueWorkload) SupportsRangeQueries() bool {
+	return false
+}
+
+func (w *MixedValueWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	return nil, nil, 0
+}
+
+func (w *MixedValueWorkload) KeyClasses() []string {
+	return []string{keyClassSmall, keyClassLarge}
+}
+
+func (w *MixedValueWorkload) KeyClass(key []byte) string {
+	if bytes.HasPrefix(key, []byte(mixedLargePrefix)) {
+		return keyClassLarge
+	}
+	return keyClassSmall
+}
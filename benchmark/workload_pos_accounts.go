@@ -13,8 +13,8 @@ import (
 // PoSAccountWorkload simulates account state access patterns
 // This includes account data, storage slots, and state trie access
 type PoSAccountWorkload struct {
-	config      WorkloadConfig
-	hotAccounts [][]byte // Pre-generated "hot" accounts that get frequent access
+	config   WorkloadConfig
+	accounts *AccountUniverse // Shared account set; its hot accounts get frequent access
 }
 
 // NewPoSAccountWorkload creates a new PoS account-focused workload
@@ -32,9 +32,13 @@ func NewPoSAccountWorkload(cfg WorkloadConfig) *PoSAccountWorkload {
 	if cfg.StateLocality == 0 {
 		cfg.StateLocality = 0.3 // 30% chance to access related state
 	}
+	if cfg.TrieNodeEncoding == "" {
+		cfg.TrieNodeEncoding = TrieNodeEncodingRLP // Structured trie nodes by default
+	}
 	
 	return &PoSAccountWorkload{
-		config: cfg,
+		config:   cfg,
+		accounts: newStateAccountUniverse(cfg),
 	}
 }
 
@@ -47,21 +51,10 @@ func (w *PoSAccountWorkload) GetDescription() string {
 		w.config.AccountCount, w.config.HotAccountRatio*100, w.config.StorageSlotRatio)
 }
 
-// initHotAccounts pre-generates the hot accounts for consistent access patterns
-func (w *PoSAccountWorkload) initHotAccounts(rng *rand.Rand) {
-	hotCount := int(float64(w.config.AccountCount) * w.config.HotAccountRatio)
-	w.hotAccounts = make([][]byte, hotCount)
-	
-	for i := range w.hotAccounts {
-		w.hotAccounts[i] = w.generateAccountAddress(rng)
-	}
-}
-
 // GenerateKeys creates realistic account and storage keys
 func (w *PoSAccountWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
 	return func(yield func([]byte) bool) {
 		rng := rand.New(rand.NewSource(seed))
-		w.initHotAccounts(rng)
 		
 		keysGenerated := 0
 		
@@ -96,14 +89,8 @@ func (w *PoSAccountWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte
 func (w *PoSAccountWorkload) generateAccountKey(rng *rand.Rand) []byte {
 	prefix := []byte("a")
 	
-	var accountAddr []byte
-	
-	// Use hot account bias
-	if rng.Float64() < 0.8 && len(w.hotAccounts) > 0 { // 80% chance to use hot account
-		accountAddr = w.hotAccounts[rng.Intn(len(w.hotAccounts))]
-	} else {
-		accountAddr = w.generateAccountAddress(rng)
-	}
+	// Use hot account bias: 80% chance to use a hot account
+	accountAddr := w.accounts.Pick(rng, 0.8)
 	
 	// Hash the account address for the key
 	accountHash := crypto.Keccak256(accountAddr)
@@ -115,14 +102,8 @@ func (w *PoSAccountWorkload) generateAccountKey(rng *rand.Rand) []byte {
 func (w *PoSAccountWorkload) generateStorageKey(rng *rand.Rand) []byte {
 	prefix := []byte("o")
 	
-	var accountAddr []byte
-	
 	// Use hot account bias for storage access too
-	if rng.Float64() < 0.8 && len(w.hotAccounts) > 0 {
-		accountAddr = w.hotAccounts[rng.Intn(len(w.hotAccounts))]
-	} else {
-		accountAddr = w.generateAccountAddress(rng)
-	}
+	accountAddr := w.accounts.Pick(rng, 0.8)
 	
 	accountHash := crypto.Keccak256(accountAddr)
 	
@@ -157,7 +138,7 @@ func (w *PoSAccountWorkload) generateStorageTrieNodeKey(rng *rand.Rand) []byte {
 	prefix := []byte("O")
 	
 	// Generate account hash
-	accountAddr := w.generateAccountAddress(rng)
+	accountAddr := w.accounts.Any(rng)
 	accountHash := crypto.Keccak256(accountAddr)
 	
 	// Generate hex path
@@ -174,12 +155,6 @@ func (w *PoSAccountWorkload) generateStorageTrieNodeKey(rng *rand.Rand) []byte {
 	return key
 }
 
-// generateAccountAddress creates a realistic 20-byte Ethereum address
-func (w *PoSAccountWorkload) generateAccountAddress(rng *rand.Rand) []byte {
-	addr := make([]byte, 20)
-	rng.Read(addr)
-	return addr
-}
 
 func (w *PoSAccountWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
 	if len(key) == 0 {
@@ -234,62 +209,24 @@ func (w *PoSAccountWorkload) generateStorageValue(rng *rand.Rand) []byte {
 }
 
 func (w *PoSAccountWorkload) generateTrieNodeValue(rng *rand.Rand) []byte {
-	// Simulate trie node structure (simplified)
-	// Trie nodes can be leaf nodes, extension nodes, or branch nodes
-	nodeType := rng.Intn(3)
-	
-	switch nodeType {
-	case 0: // Leaf node
-		keyEnd := make([]byte, rng.Intn(32)+1)
-		rng.Read(keyEnd)
-		
-		value := make([]byte, rng.Intn(1024)+1) // Variable size value
-		rng.Read(value)
-		
-		node := []interface{}{keyEnd, value}
-		encoded, _ := rlp.EncodeToBytes(node)
-		return encoded
-		
-	case 1: // Extension node
-		sharedKey := make([]byte, rng.Intn(16)+1)
-		rng.Read(sharedKey)
-		
-		nextHash := make([]byte, 32)
-		rng.Read(nextHash)
-		
-		node := []interface{}{sharedKey, nextHash}
-		encoded, _ := rlp.EncodeToBytes(node)
-		return encoded
-		
-	case 2: // Branch node
-		branches := make([]interface{}, 17) // 16 hex + value
-		for i := 0; i < 16; i++ {
-			if rng.Float64() < 0.3 { // 30% chance of having a branch
-				hash := make([]byte, 32)
-				rng.Read(hash)
-				branches[i] = hash
-			} else {
-				branches[i] = []byte{}
-			}
-		}
-		
-		// Value at this node (optional)
-		if rng.Float64() < 0.1 { // 10% chance of having value
-			nodeValue := make([]byte, rng.Intn(256))
-			rng.Read(nodeValue)
-			branches[16] = nodeValue
-		} else {
-			branches[16] = []byte{}
-		}
-		
-		encoded, _ := rlp.EncodeToBytes(branches)
-		return encoded
-		
-	default:
-		value := make([]byte, w.config.ValueSize)
-		rng.Read(value)
-		return value
-	}
+	// Trie nodes: 64-512 bytes typically
+	size := rng.Intn(450) + 64
+	return trieNodeValue(rng, w.config.TrieNodeEncoding, size)
+}
+
+// IgnoresValueSize reports true: accounts, storage slots and code carry their own sizes
+func (w *PoSAccountWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the account, storage and trie node prefixes
+func (w *PoSAccountWorkload) KeyPrefixes() [][]byte {
+	return [][]byte{[]byte("a"), []byte("o"), []byte("A"), []byte("O")}
+}
+
+// HotKeyPrefixes returns the account and storage prefixes of the hot accounts
+func (w *PoSAccountWorkload) HotKeyPrefixes() [][]byte {
+	return hotAccountPrefixes(w.accounts, []byte("a"), []byte("o"))
 }
 
 func (w *PoSAccountWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
@@ -352,12 +289,7 @@ func (w *PoSAccountWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []by
 		prefix := []byte("o")
 		
 		// Select account (prefer hot accounts)
-		var accountAddr []byte
-		if rng.Float64() < 0.8 && len(w.hotAccounts) > 0 {
-			accountAddr = w.hotAccounts[rng.Intn(len(w.hotAccounts))]
-		} else {
-			accountAddr = w.generateAccountAddress(rng)
-		}
+		accountAddr := w.accounts.Pick(rng, 0.8)
 		
 		accountHash := crypto.Keccak256(accountAddr)
 		
@@ -3,7 +3,22 @@ package benchmark
 import (
 	"fmt"
 	"iter"
+	"math"
 	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// Dirty node count distributions of a state commit
+const (
+	CommitNodesUniform = "uniform" // uniform between the min and max
+	CommitNodesNormal  = "normal"  // centred between the min and max, standard deviation a sixth of the range
+)
+
+// Dirty nodes written per state commit when unconfigured
+const (
+	defaultCommitNodesMin = 10
+	defaultCommitNodesMax = 59
 )
 
 // RealisticPoSAccountWorkload simulates actual blockchain account operations
@@ -11,11 +26,22 @@ import (
 type RealisticPoSAccountWorkload struct {
 	config         WorkloadConfig
 	trieSimulation *TrieSimulation
-	hotAccounts    [][]byte // Pre-generated hot accounts
+	accounts       *AccountUniverse // Shared account set with its hot accounts
 	
 	// Batch tracking for commit simulation
 	pendingBatches []TrieBatch
 	commitCounter  int
+
+	// State commit batching, see WorkloadConfig.CommitInterval
+	commitInterval    int
+	nodesMin          int
+	nodesMax          int
+	nodesDistribution string
+	commitNodes       int       // dirty nodes written by all commits
+	genStart, genEnd  time.Time // span of the last key generation
+
+	// Commits whose last dirty node has been yielded, for --apply-batch
+	blocksEnded atomic.Uint64
 }
 
 // NewRealisticPoSAccountWorkload creates a workload that properly simulates trie operations
@@ -30,11 +56,36 @@ func NewRealisticPoSAccountWorkload(cfg WorkloadConfig) *RealisticPoSAccountWork
 		cfg.StorageSlotRatio = 3.0 // Fewer slots due to higher per-slot cost
 	}
 	
-	return &RealisticPoSAccountWorkload{
+	w := &RealisticPoSAccountWorkload{
 		config:         cfg,
+		accounts:       newStateAccountUniverse(cfg),
 		trieSimulation: NewTrieSimulation(),
 		pendingBatches: make([]TrieBatch, 0),
+		commitInterval: cfg.CommitInterval,
+		nodesMin:       defaultCommitNodesMin,
+		nodesMax:       defaultCommitNodesMax,
+	}
+	w.nodesDistribution = cfg.CommitNodesDistribution
+	if cfg.CommitNodesMax > 0 {
+		w.nodesMin, w.nodesMax = cfg.CommitNodesMin, cfg.CommitNodesMax
 	}
+	w.trieSimulation.configureDepth(cfg)
+	w.trieSimulation.configureNodeEncoding(cfg)
+	return w
+}
+
+// Stats reports the realized trie depths and the commit rate and size. The
+// commit rate is measured over key generation, which the write phase paces.
+func (w *RealisticPoSAccountWorkload) Stats() map[string]interface{} {
+	stats := w.trieSimulation.depthStats()
+	stats["commits"] = w.commitCounter
+	if w.commitCounter > 0 {
+		stats["commit_nodes_mean"] = float64(w.commitNodes) / float64(w.commitCounter)
+		if elapsed := w.genEnd.Sub(w.genStart); elapsed > 0 {
+			stats["commits_per_sec"] = float64(w.commitCounter) / elapsed.Seconds()
+		}
+	}
+	return stats
 }
 
 func (w *RealisticPoSAccountWorkload) Name() string {
@@ -50,9 +101,11 @@ func (w *RealisticPoSAccountWorkload) GetDescription() string {
 func (w *RealisticPoSAccountWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
 	return func(yield func([]byte) bool) {
 		rng := rand.New(rand.NewSource(seed))
-		w.initHotAccounts(rng)
+		w.genStart = time.Now()
+		defer func() { w.genEnd = time.Now() }()
 		
 		keysGenerated := 0
+		sinceCommit := 0
 		batchOperations := []DatabaseOperation{}
 		
 		// Operation mix that reflects real blockchain usage
@@ -60,7 +113,21 @@ func (w *RealisticPoSAccountWorkload) GenerateKeys(seed int64, count int) iter.S
 		operationWeights := []float64{0.4, 0.15, 0.3, 0.1, 0.05} // Reads dominate, commits are periodic
 		
 		for keysGenerated < count {
-			operationType := selectWeightedChoice(rng, operationTypes, operationWeights)
+			var operationType string
+			switch {
+			case w.commitInterval == 0:
+				operationType = selectWeightedChoice(rng, operationTypes, operationWeights)
+			case sinceCommit >= w.commitInterval:
+				// Commit at the block boundary instead of at random
+				operationType = "commit_flush"
+			default:
+				operationType = selectWeightedChoice(rng, operationTypes[:4], operationWeights[:4])
+			}
+			if operationType == "commit_flush" {
+				sinceCommit = 0
+			} else {
+				sinceCommit++
+			}
 			
 			var batch TrieBatch
 			
@@ -99,8 +166,11 @@ func (w *RealisticPoSAccountWorkload) GenerateKeys(seed int64, count int) iter.S
 			}
 			
 			// Add all database operations from this logical operation
-			for _, op := range batch.DatabaseOps {
+			for i, op := range batch.DatabaseOps {
 				batchOperations = append(batchOperations, op)
+				if operationType == "commit_flush" && i == len(batch.DatabaseOps)-1 {
+					w.blocksEnded.Add(1)
+				}
 				
 				// Each database operation becomes a key in our benchmark
 				if !yield(op.Key) {
@@ -122,28 +192,14 @@ func (w *RealisticPoSAccountWorkload) GenerateKeys(seed int64, count int) iter.S
 	}
 }
 
-// initHotAccounts creates the frequently accessed accounts
-func (w *RealisticPoSAccountWorkload) initHotAccounts(rng *rand.Rand) {
-	hotCount := int(float64(w.config.AccountCount) * w.config.HotAccountRatio)
-	w.hotAccounts = make([][]byte, hotCount)
-	
-	for i := range w.hotAccounts {
-		addr := make([]byte, 20)
-		rng.Read(addr)
-		w.hotAccounts[i] = addr
-	}
+// BlocksEnded counts the state commits whose dirty nodes have all been yielded
+func (w *RealisticPoSAccountWorkload) BlocksEnded() uint64 {
+	return w.blocksEnded.Load()
 }
 
 // selectAccount chooses an account with hot account bias
 func (w *RealisticPoSAccountWorkload) selectAccount(rng *rand.Rand) []byte {
-	if rng.Float64() < 0.8 && len(w.hotAccounts) > 0 {
-		return w.hotAccounts[rng.Intn(len(w.hotAccounts))]
-	}
-	
-	// Generate random account
-	addr := make([]byte, 20)
-	rng.Read(addr)
-	return addr
+	return w.accounts.Pick(rng, 0.8)
 }
 
 // simulateStorageRead simulates reading a storage slot (separate from account read)
@@ -177,11 +233,11 @@ func (w *RealisticPoSAccountWorkload) simulateCommitFlush(rng *rand.Rand) TrieBa
 	})
 	
 	// Simulate writing multiple dirty nodes to disk
-	numDirtyNodes := rng.Intn(50) + 10 // 10-60 dirty nodes per commit
+	numDirtyNodes := w.dirtyNodeCount(rng)
 	for i := 0; i < numDirtyNodes; i++ {
 		nodeKey := make([]byte, 40)
 		rng.Read(nodeKey)
-		nodeValue := w.trieSimulation.generateUpdatedTrieNode(nodeKey, i%8)
+		nodeValue := w.trieSimulation.generateUpdatedTrieNode(i % 8)
 		
 		ops = append(ops, DatabaseOperation{
 			Type:        "WRITE",
@@ -203,6 +259,7 @@ func (w *RealisticPoSAccountWorkload) simulateCommitFlush(rng *rand.Rand) TrieBa
 	// Clear pending batches
 	w.pendingBatches = w.pendingBatches[:0]
 	w.commitCounter++
+	w.commitNodes += numDirtyNodes
 	
 	return TrieBatch{
 		LogicalOperation: "commit_flush",
@@ -211,6 +268,16 @@ func (w *RealisticPoSAccountWorkload) simulateCommitFlush(rng *rand.Rand) TrieBa
 	}
 }
 
+// dirtyNodeCount draws the number of dirty nodes one commit writes
+func (w *RealisticPoSAccountWorkload) dirtyNodeCount(rng *rand.Rand) int {
+	span := w.nodesMax - w.nodesMin
+	if w.nodesDistribution == CommitNodesNormal {
+		n := float64(w.nodesMin) + float64(span)/2 + rng.NormFloat64()*float64(span)/6
+		return max(w.nodesMin, minInt(w.nodesMax, int(math.Round(n))))
+	}
+	return w.nodesMin + rng.Intn(span+1)
+}
+
 // GenerateValue creates realistic values based on the operation type
 func (w *RealisticPoSAccountWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
 	if len(key) == 0 {
@@ -248,6 +315,11 @@ func (w *RealisticPoSAccountWorkload) GenerateValue(rng *rand.Rand, key []byte)
 	return value
 }
 
+// KeyPrefixes returns the trie simulation prefixes and the commit node prefix
+func (w *RealisticPoSAccountWorkload) KeyPrefixes() [][]byte {
+	return append(trieSimulationPrefixes(), []byte("commit_node"))
+}
+
 // generateAccountData creates realistic account state data
 func (w *RealisticPoSAccountWorkload) generateAccountData(rng *rand.Rand) []byte {
 	// Realistic account: nonce + balance + storage root + code hash
@@ -272,9 +344,7 @@ func (w *RealisticPoSAccountWorkload) generateStorageValue(rng *rand.Rand) []byt
 
 // generateTrieNodeValue creates realistic trie node data
 func (w *RealisticPoSAccountWorkload) generateTrieNodeValue(rng *rand.Rand, size int) []byte {
-	value := make([]byte, size)
-	rng.Read(value)
-	return value
+	return trieNodeValue(rng, w.config.TrieNodeEncoding, size)
 }
 
 // ShouldRead determines read vs write based on realistic trie operation patterns
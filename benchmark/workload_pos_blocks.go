@@ -9,6 +9,12 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
+// Block key layouts select how block numbers are encoded in block keys
+const (
+	BlockKeyLayoutBigEndian    = "be"
+	BlockKeyLayoutLittleEndian = "le"
+)
+
 // PoSBlockWorkload simulates blockchain block storage patterns
 // This includes block headers, block bodies, and transaction lookups
 type PoSBlockWorkload struct {
@@ -73,6 +79,17 @@ func (w *PoSBlockWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte]
 	}
 }
 
+// putBlockNumber encodes a block number into a key in the configured layout.
+// The little-endian layout scatters consecutive blocks under bytewise ordering
+// and is meant to be paired with the blocknum Pebble comparer.
+func (w *PoSBlockWorkload) putBlockNumber(dst []byte, blockNum uint64) {
+	if w.config.BlockKeyLayout == BlockKeyLayoutLittleEndian {
+		binary.LittleEndian.PutUint64(dst, blockNum)
+		return
+	}
+	binary.BigEndian.PutUint64(dst, blockNum)
+}
+
 // generateHeaderKey creates a header key: "h" + blockNumber + blockHash
 func (w *PoSBlockWorkload) generateHeaderKey(rng *rand.Rand) []byte {
 	prefix := []byte("h")
@@ -90,7 +107,7 @@ func (w *PoSBlockWorkload) generateHeaderKey(rng *rand.Rand) []byte {
 	
 	// Encode block number (8 bytes)
 	blockNumBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(blockNumBytes, blockNum)
+	w.putBlockNumber(blockNumBytes, blockNum)
 	
 	// Generate block hash (32 bytes)
 	blockHash := make([]byte, 32)
@@ -117,7 +134,7 @@ func (w *PoSBlockWorkload) generateBodyKey(rng *rand.Rand) []byte {
 	}
 	
 	blockNumBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(blockNumBytes, blockNum)
+	w.putBlockNumber(blockNumBytes, blockNum)
 	
 	blockHash := make([]byte, 32)
 	rng.Read(blockHash)
@@ -141,7 +158,7 @@ func (w *PoSBlockWorkload) generateReceiptsKey(rng *rand.Rand) []byte {
 	}
 	
 	blockNumBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(blockNumBytes, blockNum)
+	w.putBlockNumber(blockNumBytes, blockNum)
 	
 	blockHash := make([]byte, 32)
 	rng.Read(blockHash)
@@ -276,8 +293,11 @@ func (w *PoSBlockWorkload) generateBlockBodyValue(rng *rand.Rand) []byte {
 
 func (w *PoSBlockWorkload) generateReceiptsValue(rng *rand.Rand) []byte {
 	// Simulate transaction receipts
-	receiptCount := rng.Intn(200) + 1
-	
+	return w.generateReceipts(rng, rng.Intn(200)+1)
+}
+
+// generateReceipts RLP-encodes receiptCount random receipts, roughly 550 bytes each
+func (w *PoSBlockWorkload) generateReceipts(rng *rand.Rand, receiptCount int) []byte {
 	type Receipt struct {
 		Status            uint64
 		CumulativeGasUsed uint64
@@ -320,6 +340,16 @@ func (w *PoSBlockWorkload) generateTxLookupValue(rng *rand.Rand) []byte {
 	return encoded
 }
 
+// IgnoresValueSize reports true: headers, bodies and receipts carry their own sizes
+func (w *PoSBlockWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the header, body, receipts and tx lookup prefixes
+func (w *PoSBlockWorkload) KeyPrefixes() [][]byte {
+	return [][]byte{[]byte("h"), []byte("b"), []byte("r"), []byte("l")}
+}
+
 func (w *PoSBlockWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
 	return rng.Float64() < w.config.ReadRatio
 }
@@ -349,13 +379,13 @@ func (w *PoSBlockWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte
 	
 	// Create start key
 	startBlockBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(startBlockBytes, startBlock)
+	w.putBlockNumber(startBlockBytes, startBlock)
 	start = append(prefix, startBlockBytes...)
 	
 	// Create end key
 	endBlock := startBlock + rangeSize
 	endBlockBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(endBlockBytes, endBlock)
+	w.putBlockNumber(endBlockBytes, endBlock)
 	end = append(prefix, endBlockBytes...)
 	
 	limit = int(rangeSize)
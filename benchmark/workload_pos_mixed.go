@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"iter"
 	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // PoSMixedWorkload combines all PoS workload types for comprehensive testing
@@ -128,6 +132,23 @@ func (w *PoSMixedWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
 	}
 }
 
+// IgnoresValueSize reports true: values are sized by the block, account or state workload owning the key
+func (w *PoSMixedWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the prefixes of the block, account and state workloads
+func (w *PoSMixedWorkload) KeyPrefixes() [][]byte {
+	prefixes := w.blockWorkload.KeyPrefixes()
+	prefixes = append(prefixes, w.accountWorkload.KeyPrefixes()...)
+	return append(prefixes, w.stateWorkload.KeyPrefixes()...)
+}
+
+// HotKeyPrefixes returns the hot prefixes of the account and state workloads
+func (w *PoSMixedWorkload) HotKeyPrefixes() [][]byte {
+	return append(w.accountWorkload.HotKeyPrefixes(), w.stateWorkload.HotKeyPrefixes()...)
+}
+
 func (w *PoSMixedWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
 	if len(key) == 0 {
 		return rng.Float64() < w.config.ReadRatio
@@ -135,6 +156,11 @@ func (w *PoSMixedWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
 	
 	// Route to appropriate workload based on key prefix
 	prefix := string(key[0:1])
+
+	// Configured per-prefix ratios take precedence over the built-in ones
+	if ratio, ok := w.config.ReadRatios[prefix]; ok {
+		return rng.Float64() < ratio
+	}
 	
 	switch prefix {
 	case "h", "b", "r", "l":
@@ -171,13 +197,15 @@ func (w *PoSMixedWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte
 
 // PoSStateWorkload simulates state trie and snapshot access patterns
 type PoSStateWorkload struct {
-	config WorkloadConfig
+	config   WorkloadConfig
+	accounts *AccountUniverse // Snapshot entries belong to the shared account set
 }
 
 // NewPoSStateWorkload creates a new PoS state-focused workload
 func NewPoSStateWorkload(cfg WorkloadConfig) *PoSStateWorkload {
 	return &PoSStateWorkload{
-		config: cfg,
+		config:   cfg,
+		accounts: newStateAccountUniverse(cfg),
 	}
 }
 
@@ -221,15 +249,13 @@ func (w *PoSStateWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte]
 
 func (w *PoSStateWorkload) generateSnapshotAccountKey(rng *rand.Rand) []byte {
 	prefix := []byte("s") // Snapshot prefix
-	accountHash := make([]byte, 32)
-	rng.Read(accountHash)
+	accountHash := crypto.Keccak256(w.accounts.Pick(rng, 0.8))
 	return append(prefix, accountHash...)
 }
 
 func (w *PoSStateWorkload) generateSnapshotStorageKey(rng *rand.Rand) []byte {
 	prefix := []byte("S") // Snapshot storage prefix
-	accountHash := make([]byte, 32)
-	rng.Read(accountHash)
+	accountHash := crypto.Keccak256(w.accounts.Pick(rng, 0.8))
 	storageHash := make([]byte, 32)
 	rng.Read(storageHash)
 	
@@ -269,6 +295,21 @@ func (w *PoSStateWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
 	return value
 }
 
+// IgnoresValueSize reports true: snapshot, trie and account values carry their own sizes
+func (w *PoSStateWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the snapshot account, snapshot storage and trie prefixes
+func (w *PoSStateWorkload) KeyPrefixes() [][]byte {
+	return [][]byte{[]byte("s"), []byte("S"), []byte("t")}
+}
+
+// HotKeyPrefixes returns the snapshot account and storage prefixes of the hot accounts
+func (w *PoSStateWorkload) HotKeyPrefixes() [][]byte {
+	return hotAccountPrefixes(w.accounts, []byte("s"), []byte("S"))
+}
+
 func (w *PoSStateWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
 	// State data is read much more than written
 	return rng.Float64() < 0.95
@@ -324,4 +365,42 @@ func (w *PoSStateWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte
 	}
 	
 	return start, end, limit
-}
\ No newline at end of file
+}
+
+// ParseReadRatios parses a spec like "h=0.9,a=0.85,o=0.95" mapping single-byte
+// key prefixes to the probability that an operation on such a key is a read
+func ParseReadRatios(spec string) (map[string]float64, error) {
+	ratios := make(map[string]float64)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		prefix, ratioStr, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid read ratio %q: expected prefix=ratio", part)
+		}
+		prefix = strings.TrimSpace(prefix)
+		if len(prefix) != 1 {
+			return nil, fmt.Errorf("invalid read ratio %q: prefix must be a single byte", part)
+		}
+		if _, dup := ratios[prefix]; dup {
+			return nil, fmt.Errorf("invalid read ratio %q: prefix %q given twice", part, prefix)
+		}
+
+		ratio, err := strconv.ParseFloat(strings.TrimSpace(ratioStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid read ratio %q: %w", part, err)
+		}
+		if ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid read ratio %q: ratio must be between 0 and 1", part)
+		}
+		ratios[prefix] = ratio
+	}
+
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("read ratio spec %q has no prefixes", spec)
+	}
+	return ratios, nil
+}
@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"iter"
 	"math/rand"
+
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // RealisticPoSStateWorkload simulates state trie operations with proper traversal patterns
 type RealisticPoSStateWorkload struct {
 	config         WorkloadConfig
 	trieSimulation *TrieSimulation
+	accounts       *AccountUniverse // Leaf reads belong to the shared account set
 	
 	// Track common trie paths for spatial locality
 	commonPaths   [][]byte
@@ -26,9 +29,13 @@ func NewRealisticPoSStateWorkload(cfg WorkloadConfig) *RealisticPoSStateWorkload
 	w := &RealisticPoSStateWorkload{
 		config:         cfg,
 		trieSimulation: NewTrieSimulation(),
+		accounts:       newStateAccountUniverse(cfg),
 		commonPaths:    make([][]byte, 0),
 	}
 	
+	w.trieSimulation.configureDepth(cfg)
+	w.trieSimulation.configureNodeEncoding(cfg)
+
 	// Pre-populate some common paths for spatial locality
 	w.initCommonPaths()
 	
@@ -44,6 +51,11 @@ func (w *RealisticPoSStateWorkload) GetDescription() string {
 		w.config.StateLocality*100)
 }
 
+// HotKeyPrefixes returns the account and storage leaf prefixes of the hot accounts
+func (w *RealisticPoSStateWorkload) HotKeyPrefixes() [][]byte {
+	return hotAccountPrefixes(w.accounts, []byte("account_leaf"), []byte("storage_leaf"))
+}
+
 // initCommonPaths creates frequently accessed paths for spatial locality
 func (w *RealisticPoSStateWorkload) initCommonPaths() {
 	// Simulate common prefixes that get accessed together
@@ -171,7 +183,7 @@ func (w *RealisticPoSStateWorkload) generateTrieTraversal(rng *rand.Rand) []Data
 		}
 	} else {
 		// Generate random path
-		pathLen := rng.Intn(8) + 4 // 4-12 nibbles
+		pathLen := w.trieSimulation.scaleDepth(rng.Intn(8) + 4) // 4-12 nibbles at the default depth
 		path = make([]byte, pathLen)
 		for i := range path {
 			path[i] = byte(rng.Intn(16))
@@ -207,15 +219,13 @@ func (w *RealisticPoSStateWorkload) generateLeafRead(rng *rand.Rand) []DatabaseO
 	
 	if rng.Float64() < 0.6 {
 		// Account leaf
-		accountHash := make([]byte, 32)
-		rng.Read(accountHash)
+		accountHash := crypto.Keccak256(w.accounts.Pick(rng, 0.8))
 		key = append([]byte("account_leaf"), accountHash...)
 		description = "Read account leaf node"
 	} else {
 		// Storage leaf
-		accountHash := make([]byte, 32)
+		accountHash := crypto.Keccak256(w.accounts.Pick(rng, 0.8))
 		storageHash := make([]byte, 32)
-		rng.Read(accountHash)
 		rng.Read(storageHash)
 		key = append([]byte("storage_leaf"), accountHash...)
 		key = append(key, storageHash...)
@@ -234,7 +244,7 @@ func (w *RealisticPoSStateWorkload) generateLeafRead(rng *rand.Rand) []DatabaseO
 // generateBranchRead simulates reading branch nodes (intermediate trie nodes)
 func (w *RealisticPoSStateWorkload) generateBranchRead(rng *rand.Rand) []DatabaseOperation {
 	// Branch nodes at different depths have different access patterns
-	depth := rng.Intn(8) + 1
+	depth := w.trieSimulation.scaleDepth(rng.Intn(8) + 1)
 	
 	// Shallow branch nodes are accessed more frequently
 	var accessMultiplier int
@@ -286,7 +296,7 @@ func (w *RealisticPoSStateWorkload) generateNodeUpdate(rng *rand.Rand) []Databas
 	ops := []DatabaseOperation{}
 	
 	// Updates typically happen in cascades (bottom-up)
-	updateDepth := rng.Intn(6) + 2 // 2-8 levels
+	updateDepth := w.trieSimulation.scaleDepth(rng.Intn(6) + 2) // 2-8 levels at the default depth
 	
 	path := make([]byte, updateDepth)
 	for i := range path {
@@ -334,7 +344,7 @@ func (w *RealisticPoSStateWorkload) generateStateCommit(rng *rand.Rand) []Databa
 	numDirtyNodes := rng.Intn(100) + 20 // 20-120 dirty nodes
 	for i := 0; i < numDirtyNodes; i++ {
 		// Generate realistic node distribution
-		depth := w.selectNodeDepthForCommit(rng)
+		depth := w.trieSimulation.scaleDepth(w.selectNodeDepthForCommit(rng))
 		path := make([]byte, depth)
 		for j := range path {
 			path[j] = byte(rng.Intn(16))
@@ -416,9 +426,7 @@ func (w *RealisticPoSStateWorkload) generateRealisticTrieNodeValue(rng *rand.Ran
 	}
 	
 	size := baseSize + rng.Intn(baseSize/2)
-	value := make([]byte, size)
-	rng.Read(value)
-	return value
+	return trieNodeValue(rng, w.config.TrieNodeEncoding, size)
 }
 
 func (w *RealisticPoSStateWorkload) selectNodeDepthForCommit(rng *rand.Rand) int {
@@ -479,6 +487,16 @@ func (w *RealisticPoSStateWorkload) GenerateValue(rng *rand.Rand, key []byte) []
 	}
 }
 
+// IgnoresValueSize reports true: roots, trie nodes and leaves carry their own sizes
+func (w *RealisticPoSStateWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the state root, trie node, leaf and snapshot prefixes
+func (w *RealisticPoSStateWorkload) KeyPrefixes() [][]byte {
+	return [][]byte{[]byte("state_root"), []byte("trie_node"), []byte("account_leaf"), []byte("storage_leaf"), []byte("snapshot_")}
+}
+
 func (w *RealisticPoSStateWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
 	if len(key) == 0 {
 		return rng.Float64() < w.config.ReadRatio
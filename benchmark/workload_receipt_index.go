@@ -0,0 +1,112 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/rand"
+	"sync/atomic"
+)
+
+// WorkloadReceiptIndex writes large receipt blobs once per block and scans them by block window
+const WorkloadReceiptIndex WorkloadType = "receipt-index"
+
+const (
+	receiptIndexPrefix = "rcpt"
+
+	// Receipts per block, sized so values land between roughly 1KB and 10KB
+	receiptIndexMinReceipts = 2
+	receiptIndexMaxReceipts = 18
+
+	// receiptIndexMaxWindow is the widest block window a range read scans
+	receiptIndexMaxWindow = 64
+)
+
+// ReceiptIndexWorkload models a log indexer: every block's receipts are written
+// once as a single large value, then read back repeatedly by scanning windows
+// of consecutive blocks
+type ReceiptIndexWorkload struct {
+	config WorkloadConfig
+	blocks *PoSBlockWorkload // receipt encoding shared with the block workload
+
+	written atomic.Uint64 // blocks yielded by the last key generation
+}
+
+// NewReceiptIndexWorkload creates a new write-once, scan-many receipt workload
+func NewReceiptIndexWorkload(cfg WorkloadConfig) *ReceiptIndexWorkload {
+	return &ReceiptIndexWorkload{
+		config: cfg,
+		blocks: NewPoSBlockWorkload(cfg),
+	}
+}
+
+func (w *ReceiptIndexWorkload) Name() string {
+	return "Receipt-Index"
+}
+
+func (w *ReceiptIndexWorkload) GetDescription() string {
+	return fmt.Sprintf("Write-once receipt blobs of %d-%d receipts keyed by block, scanned in windows of up to %d blocks (use --range-queries)",
+		receiptIndexMinReceipts, receiptIndexMaxReceipts, receiptIndexMaxWindow)
+}
+
+// receiptKey returns the receipts key of block number n
+func receiptKey(n uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte(receiptIndexPrefix), n)
+}
+
+// GenerateKeys yields one receipts key per block in ascending block order, the
+// way an indexer appends blocks as they arrive
+func (w *ReceiptIndexWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		w.written.Store(0)
+		for n := uint64(0); n < uint64(count); n++ {
+			w.written.Store(n + 1)
+			if !yield(receiptKey(n)) {
+				return
+			}
+		}
+	}
+}
+
+// KeysIgnoreSeed reports true: blocks are numbered from zero
+func (w *ReceiptIndexWorkload) KeysIgnoreSeed() bool {
+	return true
+}
+
+func (w *ReceiptIndexWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	receipts := receiptIndexMinReceipts + rng.Intn(receiptIndexMaxReceipts-receiptIndexMinReceipts+1)
+	return w.blocks.generateReceipts(rng, receipts)
+}
+
+// IgnoresValueSize reports true: receipt blobs are sized by their receipt count
+func (w *ReceiptIndexWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the receipts prefix
+func (w *ReceiptIndexWorkload) KeyPrefixes() [][]byte {
+	return [][]byte{[]byte(receiptIndexPrefix)}
+}
+
+func (w *ReceiptIndexWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *ReceiptIndexWorkload) SupportsRangeQueries() bool {
+	return true
+}
+
+// GenerateRangeQuery scans a window of consecutive blocks among those written
+func (w *ReceiptIndexWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	written := w.written.Load()
+	if written == 0 {
+		written = uint64(w.blocks.config.BlockRange)
+	}
+
+	window := uint64(rng.Intn(receiptIndexMaxWindow) + 1)
+	if window > written {
+		window = written
+	}
+	from := uint64(rng.Int63n(int64(written - window + 1)))
+	return receiptKey(from), receiptKey(from + window), int(window)
+}
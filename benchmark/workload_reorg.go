@@ -0,0 +1,212 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// WorkloadReorg appends blocks and periodically reorganizes the most recent ones
+const WorkloadReorg WorkloadType = "reorg"
+
+// Reorg defaults, used when the interval or depth is not set
+const (
+	defaultReorgInterval = 100
+	defaultReorgDepth    = 2
+)
+
+// reorgCanonicalSuffix ends the canonical hash key of a block number, as in
+// geth's headerPrefix + num + 'n'
+const reorgCanonicalSuffix = 'n'
+
+// DeletionReporter is implemented by workloads whose key sequence deletes
+// keys as well as writing them
+type DeletionReporter interface {
+	// LastKeyDeleted reports whether the key GenerateKeys yielded last is to
+	// be deleted rather than written. It is read right after each key, while
+	// generation is paused.
+	LastKeyDeleted() bool
+}
+
+// ReorgWorkload appends blocks, each a header, body, receipts and canonical
+// hash key, and every ReorgInterval blocks replaces the last ReorgDepth blocks
+// with a competing fork: the old header, body and receipts are deleted, the
+// new ones written under the new block hash and the canonical hash keys
+// overwritten. The churn lands in the newest, hottest part of the keyspace,
+// where an LSM holds the tombstones and overwrites in its upper levels.
+// Reads of blocks replaced by a fork do not find their keys.
+type ReorgWorkload struct {
+	config   WorkloadConfig
+	blocks   *PoSBlockWorkload // header, body and receipt encoding shared with the block workload
+	interval int
+	depth    int
+
+	lastDeleted bool
+
+	appended  atomic.Uint64 // blocks appended by the last key generation
+	reorgs    atomic.Uint64 // reorgs of the last key generation
+	rewritten atomic.Uint64 // blocks replaced by reorgs
+	deleted   atomic.Uint64 // keys deleted by reorgs
+}
+
+// NewReorgWorkload creates a chain reorg workload
+func NewReorgWorkload(cfg WorkloadConfig) *ReorgWorkload {
+	interval := cfg.ReorgInterval
+	if interval <= 0 {
+		interval = defaultReorgInterval
+	}
+	depth := cfg.ReorgDepth
+	if depth <= 0 {
+		depth = defaultReorgDepth
+	}
+
+	return &ReorgWorkload{
+		config:   cfg,
+		blocks:   NewPoSBlockWorkload(cfg),
+		interval: interval,
+		depth:    depth,
+	}
+}
+
+func (w *ReorgWorkload) Name() string {
+	return "Reorg"
+}
+
+func (w *ReorgWorkload) GetDescription() string {
+	return fmt.Sprintf("Appends blocks and replaces the last %d blocks with a fork every %d blocks, deleting and rewriting their keys",
+		w.depth, w.interval)
+}
+
+// blockHash returns the hash of block n on its fork-th fork
+func (w *ReorgWorkload) blockHash(n uint64, fork uint32) []byte {
+	var raw [20]byte
+	binary.BigEndian.PutUint64(raw[:8], uint64(w.config.Seed))
+	binary.BigEndian.PutUint64(raw[8:16], n)
+	binary.BigEndian.PutUint32(raw[16:], fork)
+	return crypto.Keccak256(raw[:])
+}
+
+// blockKey returns the key of block n under prefix: prefix + number + hash
+func (w *ReorgWorkload) blockKey(prefix byte, n uint64, hash []byte) []byte {
+	key := make([]byte, 9, 9+len(hash))
+	key[0] = prefix
+	w.blocks.putBlockNumber(key[1:9], n)
+	return append(key, hash...)
+}
+
+// canonicalKey returns the key holding the canonical hash of block n
+func (w *ReorgWorkload) canonicalKey(n uint64) []byte {
+	return append(w.blockKey('h', n, nil), reorgCanonicalSuffix)
+}
+
+// GenerateKeys appends blocks, reorganizing the chain every interval blocks.
+// A reorg deletes the header, body and receipts of each replaced block before
+// writing its replacement. Deletes count towards count.
+func (w *ReorgWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		w.appended.Store(0)
+		w.reorgs.Store(0)
+		w.rewritten.Store(0)
+		w.deleted.Store(0)
+
+		forks := make(map[uint64]uint32) // forks of the blocks replaced so far
+		generated := 0
+		emit := func(key []byte, deleted bool) bool {
+			if generated >= count {
+				return false
+			}
+			generated++
+			w.lastDeleted = deleted
+			if deleted {
+				w.deleted.Add(1)
+			}
+			return yield(key)
+		}
+		writeBlock := func(n uint64, hash []byte) bool {
+			return emit(w.blockKey('h', n, hash), false) &&
+				emit(w.blockKey('b', n, hash), false) &&
+				emit(w.blockKey('r', n, hash), false) &&
+				emit(w.canonicalKey(n), false)
+		}
+
+		for n := uint64(0); ; n++ {
+			if !writeBlock(n, w.blockHash(n, 0)) {
+				return
+			}
+			w.appended.Add(1)
+
+			head := n + 1
+			if head%uint64(w.interval) != 0 || head < uint64(w.depth) {
+				continue
+			}
+			for r := head - uint64(w.depth); r < head; r++ {
+				old := w.blockHash(r, forks[r])
+				for _, prefix := range []byte{'h', 'b', 'r'} {
+					if !emit(w.blockKey(prefix, r, old), true) {
+						return
+					}
+				}
+				forks[r]++
+				if !writeBlock(r, w.blockHash(r, forks[r])) {
+					return
+				}
+				w.rewritten.Add(1)
+			}
+			w.reorgs.Add(1)
+		}
+	}
+}
+
+// KeysIgnoreSeed reports true: the chain is appended from block zero
+func (w *ReorgWorkload) KeysIgnoreSeed() bool {
+	return true
+}
+
+// LastKeyDeleted reports whether the last key belongs to a replaced block
+func (w *ReorgWorkload) LastKeyDeleted() bool {
+	return w.lastDeleted
+}
+
+// GenerateValue returns the block workload's header, body and receipt values,
+// and a block hash for canonical hash keys
+func (w *ReorgWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	if len(key) == 10 && key[9] == reorgCanonicalSuffix {
+		value := make([]byte, 32)
+		rng.Read(value)
+		return value
+	}
+	return w.blocks.GenerateValue(rng, key)
+}
+
+// IgnoresValueSize reports true: block values carry their own sizes
+func (w *ReorgWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+func (w *ReorgWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *ReorgWorkload) SupportsRangeQueries() bool {
+	return false
+}
+
+func (w *ReorgWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	return nil, nil, 0
+}
+
+// Stats reports the reorgs of the last key generation
+func (w *ReorgWorkload) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"blocks_appended":  w.appended.Load(),
+		"reorg_interval":   w.interval,
+		"reorg_depth":      w.depth,
+		"reorgs":           w.reorgs.Load(),
+		"blocks_rewritten": w.rewritten.Load(),
+		"keys_deleted":     w.deleted.Load(),
+	}
+}
@@ -0,0 +1,34 @@
+package benchmark
+
+import "testing"
+
+func TestReorgWorkloadDeletesWrittenBlocks(t *testing.T) {
+	w := NewReorgWorkload(WorkloadConfig{Seed: 1, ReorgInterval: 5, ReorgDepth: 2})
+
+	live := make(map[string]bool)
+	var deletes int
+	for key := range w.GenerateKeys(1, 1000) {
+		if w.LastKeyDeleted() {
+			if !live[string(key)] {
+				t.Fatalf("deleted key %x that is not live", key)
+			}
+			delete(live, string(key))
+			deletes++
+			continue
+		}
+		live[string(key)] = true
+	}
+
+	stats := w.Stats()
+	reorgs := stats["reorgs"].(uint64)
+	if reorgs == 0 {
+		t.Fatal("no reorgs in 1000 keys")
+	}
+	// Every reorg replaces the header, body and receipts of depth blocks
+	if got := stats["keys_deleted"].(uint64); got != uint64(deletes) || deletes < int(reorgs)*2*3 {
+		t.Fatalf("deleted %d keys, stats report %d, for %d reorgs", deletes, got, reorgs)
+	}
+	if got := stats["blocks_rewritten"].(uint64); got < reorgs*2 {
+		t.Fatalf("rewrote %d blocks in %d reorgs of depth 2", got, reorgs)
+	}
+}
@@ -0,0 +1,155 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// WorkloadStorageDump enumerates whole contract storages with prefix scans
+const WorkloadStorageDump WorkloadType = "storage-dump"
+
+// storageDumpMaxSlots is the storage size of the largest contract. Contract i
+// holds storageDumpMaxSlots/(i+1) slots, so a few contracts dominate the
+// storage like popular tokens do while most hold a handful of slots.
+const storageDumpMaxSlots = 10000
+
+// storageDumpPrefix starts every storage slot key, followed by the contract
+// address and the slot hash
+var storageDumpPrefix = []byte("storage:")
+
+// StorageDumpWorkload writes the storage of a --contract-count pool of
+// contracts and scans each contract's storage in full with a bounded prefix
+// scan, the access pattern of debug_storageRangeAt and of serving storage
+// ranges to snap-syncing peers. The range phase (--range-queries) measures
+// the dumps.
+type StorageDumpWorkload struct {
+	config    WorkloadConfig
+	contracts [][]byte
+	slots     []int // slot count of each contract
+}
+
+// NewStorageDumpWorkload creates a contract storage dump workload
+func NewStorageDumpWorkload(cfg WorkloadConfig) *StorageDumpWorkload {
+	if cfg.ContractCount <= 0 {
+		cfg.ContractCount = 100
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	contracts := make([][]byte, cfg.ContractCount)
+	slots := make([]int, cfg.ContractCount)
+	for i := range contracts {
+		contracts[i] = make([]byte, 20)
+		rng.Read(contracts[i])
+		slots[i] = max(1, storageDumpMaxSlots/(i+1))
+	}
+
+	return &StorageDumpWorkload{
+		config:    cfg,
+		contracts: contracts,
+		slots:     slots,
+	}
+}
+
+func (w *StorageDumpWorkload) Name() string {
+	return "Storage-Dump"
+}
+
+func (w *StorageDumpWorkload) GetDescription() string {
+	return fmt.Sprintf("Full prefix scans of contract storage across %d contracts (%d slots in total, %d in the largest)",
+		len(w.contracts), w.totalSlots(), w.slots[0])
+}
+
+// totalSlots returns the number of storage slots across all contracts
+func (w *StorageDumpWorkload) totalSlots() int {
+	total := 0
+	for _, n := range w.slots {
+		total += n
+	}
+	return total
+}
+
+// Stats reports the storage sizes a full dump returns
+func (w *StorageDumpWorkload) Stats() map[string]interface{} {
+	total := w.totalSlots()
+	return map[string]interface{}{
+		"contracts":                  len(w.contracts),
+		"storage_slots":              total,
+		"slots_per_contract_mean":    float64(total) / float64(len(w.contracts)),
+		"slots_in_largest_contract":  w.slots[0],
+		"slots_in_smallest_contract": w.slots[len(w.slots)-1],
+	}
+}
+
+// slotKey returns the key of slot j of contract i
+func (w *StorageDumpWorkload) slotKey(i, j int) []byte {
+	slotHash := crypto.Keccak256(binary.BigEndian.AppendUint64(nil, uint64(j)))
+	key := make([]byte, 0, len(storageDumpPrefix)+len(w.contracts[i])+len(slotHash))
+	key = append(key, storageDumpPrefix...)
+	key = append(key, w.contracts[i]...)
+	return append(key, slotHash...)
+}
+
+// GenerateKeys fills the storage of every contract, interleaving contracts
+// the way blocks touch many of them at once. Past the last slot it starts
+// over, overwriting slots with new values.
+func (w *StorageDumpWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		generated := 0
+		for generated < count {
+			for j := 0; j < w.slots[0] && generated < count; j++ {
+				// Contracts are ordered by size, so only a prefix of them has slot j
+				for i := 0; i < len(w.contracts) && w.slots[i] > j && generated < count; i++ {
+					if !yield(w.slotKey(i, j)) {
+						return
+					}
+					generated++
+				}
+			}
+		}
+	}
+}
+
+// KeysIgnoreSeed reports true: every generator fills the same contracts
+func (w *StorageDumpWorkload) KeysIgnoreSeed() bool {
+	return true
+}
+
+// GenerateValue returns a slot value RLP-encoded with leading zeros stripped,
+// as stored by Ethereum clients
+func (w *StorageDumpWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	value := make([]byte, 1+rng.Intn(32))
+	rng.Read(value)
+	encoded, _ := rlp.EncodeToBytes(bytes.TrimLeft(value, "\x00"))
+	return encoded
+}
+
+// IgnoresValueSize reports true: values are encoded storage slots
+func (w *StorageDumpWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the storage slot prefix
+func (w *StorageDumpWorkload) KeyPrefixes() [][]byte {
+	return [][]byte{storageDumpPrefix}
+}
+
+func (w *StorageDumpWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *StorageDumpWorkload) SupportsRangeQueries() bool {
+	return true
+}
+
+// GenerateRangeQuery dumps the whole storage of a random contract
+func (w *StorageDumpWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	contract := w.contracts[rng.Intn(len(w.contracts))]
+	start = append(bytes.Clone(storageDumpPrefix), contract...)
+	return start, prefixUpperBound(start), 0
+}
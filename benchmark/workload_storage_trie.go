@@ -0,0 +1,162 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// storageTrieUpdateRatio is the share of operations that update a slot
+	// instead of only reading it
+	storageTrieUpdateRatio = 0.1
+
+	// storageTrieSlotsPerContract bounds the slots touched per contract so
+	// reads revisit the same trie paths
+	storageTrieSlotsPerContract = 4096
+)
+
+// StorageTrieWorkload isolates storage-trie traversal for a fixed set of
+// contracts: reads emit the full storage-trie node path followed by the slot
+// value, and occasional updates emit the bottom-up rewrite of that path
+type StorageTrieWorkload struct {
+	config         WorkloadConfig
+	trieSimulation *TrieSimulation
+	contracts      [][]byte
+}
+
+// NewStorageTrieWorkload creates a storage-trie focused workload
+func NewStorageTrieWorkload(cfg WorkloadConfig) *StorageTrieWorkload {
+	if cfg.ContractCount <= 0 {
+		cfg.ContractCount = 100
+	}
+
+	trieSimulation := NewTrieSimulation()
+	trieSimulation.configureDepth(cfg)
+	trieSimulation.configureNodeEncoding(cfg)
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	contracts := make([][]byte, cfg.ContractCount)
+	for i := range contracts {
+		contracts[i] = make([]byte, 20)
+		rng.Read(contracts[i])
+	}
+
+	return &StorageTrieWorkload{
+		config:         cfg,
+		trieSimulation: trieSimulation,
+		contracts:      contracts,
+	}
+}
+
+func (w *StorageTrieWorkload) Name() string {
+	return "Storage-Trie"
+}
+
+func (w *StorageTrieWorkload) GetDescription() string {
+	depth := "default"
+	if w.config.StorageTrieDepth > 0 {
+		depth = fmt.Sprintf("%d", w.config.StorageTrieDepth)
+	}
+	return fmt.Sprintf("Storage trie traversal across %d contracts (depth %s, %.0f%% updates)",
+		len(w.contracts), depth, storageTrieUpdateRatio*100)
+}
+
+// Stats reports the realized trie depths
+func (w *StorageTrieWorkload) Stats() map[string]interface{} {
+	return w.trieSimulation.depthStats()
+}
+
+// slotKey returns the storage key of one of a contract's slots
+func (w *StorageTrieWorkload) slotKey(contract []byte, slot int) []byte {
+	raw := make([]byte, len(contract)+8)
+	copy(raw, contract)
+	binary.BigEndian.PutUint64(raw[len(contract):], uint64(slot))
+	return crypto.Keccak256(raw)
+}
+
+// GenerateKeys emits the database keys touched by each storage read or update
+func (w *StorageTrieWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+		keysGenerated := 0
+
+		for keysGenerated < count {
+			contract := w.contracts[rng.Intn(len(w.contracts))]
+			storageKey := w.slotKey(contract, rng.Intn(storageTrieSlotsPerContract))
+
+			var keys [][]byte
+			if rng.Float64() < storageTrieUpdateRatio {
+				value := make([]byte, 32)
+				rng.Read(value)
+				batch := w.trieSimulation.SimulateStorageUpdate(contract, storageKey, value)
+				for _, op := range batch.DatabaseOps {
+					keys = append(keys, op.Key)
+				}
+			} else {
+				accountHash := crypto.Keccak256(contract)
+				storageKeyHash := crypto.Keccak256(storageKey)
+				keys = w.trieSimulation.computeStorageTriePath(accountHash, storageKeyHash)
+				keys = append(keys, w.trieSimulation.computeStorageValueKey(accountHash, storageKeyHash))
+			}
+
+			for _, key := range keys {
+				if !yield(key) {
+					return
+				}
+				keysGenerated++
+				if keysGenerated >= count {
+					break
+				}
+			}
+		}
+	}
+}
+
+// storageValueKeyLen is the length of "storage" + account hash + slot hash
+const storageValueKeyLen = len("storage") + 32 + 32
+
+func (w *StorageTrieWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	var size int
+	switch {
+	case len(key) == storageValueKeyLen && string(key[:7]) == "storage":
+		size = 32 // Slot value
+	case len(key) >= 7 && string(key[:7]) == "storage":
+		size = rng.Intn(200) + 32 // Storage trie node
+	case len(key) >= 7 && string(key[:7]) == "account":
+		size = 128 // Account with updated storage root
+	case len(key) >= 4 && string(key[:4]) == "trie":
+		size = rng.Intn(200) + 32 // State trie node
+	default:
+		size = w.config.ValueSize
+	}
+
+	value := make([]byte, size)
+	rng.Read(value)
+	return value
+}
+
+// KeyPrefixes returns the trie simulation prefixes
+func (w *StorageTrieWorkload) KeyPrefixes() [][]byte {
+	return trieSimulationPrefixes()
+}
+
+func (w *StorageTrieWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *StorageTrieWorkload) SupportsRangeQueries() bool {
+	return true
+}
+
+// GenerateRangeQuery scans the storage of one contract
+func (w *StorageTrieWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	contract := w.contracts[rng.Intn(len(w.contracts))]
+	start = append([]byte("storage"), crypto.Keccak256(contract)...)
+	end = prefixUpperBound(start)
+	limit = rng.Intn(100) + 10
+	return start, end, limit
+}
@@ -5,8 +5,61 @@ import (
 	"fmt"
 	"iter"
 	"math/rand"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Block commit defaults, used when WorkloadConfig leaves the commit shape unset
+const (
+	defaultCommitOpsMin    = 5
+	defaultCommitOpsMax    = 14
+	defaultCommitKeyPrefix = "block:"
+	defaultCommitValueMin  = 500
+	defaultCommitValueMax  = 5499
 )
 
+// blockCommitShape is the number, key prefix and value size of the operations
+// written when a block commits
+type blockCommitShape struct {
+	opsMin, opsMax     int
+	prefix             []byte
+	valueMin, valueMax int
+}
+
+// transactionKeyPrefixes registers the key prefix of every operation category
+// the transaction workload emits, with commitPrefix for block commits
+func transactionKeyPrefixes(commitPrefix []byte) (keyPrefixTable, error) {
+	return newKeyPrefixTable(
+		keyPrefix{opCategoryAccount, []byte("account:")},
+		keyPrefix{opCategoryStorage, []byte("storage:")},
+		keyPrefix{opCategoryTrie, []byte("trie:")},
+		keyPrefix{opCategoryPersistence, []byte("wal:")},
+		keyPrefix{opCategoryBlockCommit, commitPrefix},
+	)
+}
+
+// newBlockCommitShape resolves the commit shape from cfg, filling in defaults
+func newBlockCommitShape(cfg WorkloadConfig) blockCommitShape {
+	shape := blockCommitShape{
+		opsMin:   cfg.CommitOpsMin,
+		opsMax:   cfg.CommitOpsMax,
+		prefix:   []byte(cfg.CommitKeyPrefix),
+		valueMin: cfg.CommitValueMin,
+		valueMax: cfg.CommitValueMax,
+	}
+	if shape.opsMax == 0 {
+		shape.opsMin, shape.opsMax = defaultCommitOpsMin, defaultCommitOpsMax
+	}
+	if len(shape.prefix) == 0 {
+		shape.prefix = []byte(defaultCommitKeyPrefix)
+	}
+	if shape.valueMax == 0 {
+		shape.valueMin, shape.valueMax = defaultCommitValueMin, defaultCommitValueMax
+	}
+	return shape
+}
+
 // TransactionExecutionWorkload implements realistic transaction execution patterns
 type TransactionExecutionWorkload struct {
 	config      WorkloadConfig
@@ -23,8 +76,24 @@ type TransactionExecutionWorkload struct {
 	gasInBlock    uint64
 	gasTarget     uint64
 
-	// Hot account tracking for spatial locality
-	hotAccounts [][]byte
+	// Shared account set; hot accounts provide spatial locality
+	accounts *AccountUniverse
+
+	// Predicted against executed operations per transaction
+	amplification amplificationStats
+
+	// Key prefix of every operation category
+	prefixes keyPrefixTable
+
+	// Block commit operations and their realized size
+	commit           blockCommitShape
+	commits          uint64
+	commitOps        uint64
+	commitValueBytes atomic.Uint64
+
+	// Blocks whose last commit key has been yielded, for --apply-batch and
+	// --block-time
+	blocksEnded atomic.Uint64
 }
 
 // NewTransactionExecutionWorkload creates the new workload type
@@ -33,6 +102,7 @@ func NewTransactionExecutionWorkload(cfg WorkloadConfig) *TransactionExecutionWo
 		config:        cfg,
 		maxTxPerBlock: cfg.TxPerBlock,
 		gasTarget:     cfg.GasTargetPerBlock,
+		commit:        newBlockCommitShape(cfg),
 	}
 
 	// Configure model based on network type and user overrides
@@ -44,7 +114,16 @@ func NewTransactionExecutionWorkload(cfg WorkloadConfig) *TransactionExecutionWo
 	workload.txGenerator = NewTransactionGenerator(workload.txModel, workload.transactionMix, cfg.Seed+1)
 
 	// Initialize hot accounts for spatial locality
-	workload.initHotAccounts(cfg.Seed + 2)
+	workload.initAccounts()
+
+	prefixes, err := transactionKeyPrefixes(workload.commit.prefix)
+	if err != nil {
+		// Runs validate the commit prefix up front, so only direct callers get here
+		log.Warn().Err(err).Str("default", defaultCommitKeyPrefix).Msg("Invalid block commit key prefix, using the default")
+		workload.commit.prefix = []byte(defaultCommitKeyPrefix)
+		prefixes, _ = transactionKeyPrefixes(workload.commit.prefix)
+	}
+	workload.prefixes = prefixes
 
 	return workload
 }
@@ -86,6 +165,9 @@ func (w *TransactionExecutionWorkload) buildModelConfig(cfg WorkloadConfig) Tran
 	if cfg.TxContractRatio >= 0 {
 		modelConfig.ContractRatio = cfg.TxContractRatio
 	}
+	if cfg.TxStorageOpMultiplier > 0 {
+		modelConfig.StorageOpMultiplier = cfg.TxStorageOpMultiplier
+	}
 
 	return modelConfig
 }
@@ -127,28 +209,33 @@ func (w *TransactionExecutionWorkload) buildTransactionMix(cfg WorkloadConfig) T
 	}
 
 	// Validate and normalize the mix
-	if !ValidateTransactionMix(mixConfig) {
+	valid := ValidateTransactionMix(mixConfig)
+	if !valid {
 		// Fallback to balanced mix if invalid
 		mixConfig = BalancedTransactionMix
 	}
 
+	log.Debug().
+		Str("profile", cfg.TransactionMix).
+		Bool("fell_back_to_balanced", !valid).
+		Float64("simple_transfer", mixConfig.SimpleTransferRatio).
+		Float64("erc20_transfer", mixConfig.ERC20TransferRatio).
+		Float64("uniswap_swap", mixConfig.UniswapSwapRatio).
+		Float64("complex_defi", mixConfig.ComplexDeFiRatio).
+		Float64("contract_deploy", mixConfig.ContractDeployRatio).
+		Msg("Transaction mix")
+
 	return mixConfig
 }
 
-// initHotAccounts creates the frequently accessed accounts for spatial locality
-func (w *TransactionExecutionWorkload) initHotAccounts(seed int64) {
-	rng := rand.New(rand.NewSource(seed))
-	hotCount := int(float64(w.config.AccountCount) * w.txModel.config.HotAccountProbability)
+// initAccounts builds the shared account universe with the model's hot set size
+func (w *TransactionExecutionWorkload) initAccounts() {
+	hotCount := hotAccountCount(w.config, w.config.AccountCount, w.txModel.config.HotAccountProbability)
 	if hotCount == 0 {
 		hotCount = 10 // Minimum hot accounts
 	}
 
-	w.hotAccounts = make([][]byte, hotCount)
-	for i := range w.hotAccounts {
-		addr := make([]byte, 20)
-		rng.Read(addr)
-		w.hotAccounts[i] = addr
-	}
+	w.accounts = NewAccountUniverse(w.config.Seed, w.config.AccountCount, hotCount)
 }
 
 // Name returns workload identifier
@@ -162,11 +249,34 @@ func (w *TransactionExecutionWorkload) GetDescription() string {
 		w.config.NetworkType, w.config.TransactionMix, w.maxTxPerBlock, float64(w.gasTarget))
 }
 
+// Stats reports the blocks produced and the model's predicted operation
+// amplification against the writes the write phase issued
+func (w *TransactionExecutionWorkload) Stats() map[string]interface{} {
+	stats := map[string]interface{}{
+		"blocks": w.blocksEnded.Load(),
+	}
+	for k, v := range w.amplification.stats() {
+		stats[k] = v
+	}
+	if m := w.txModel.config.StorageOpMultiplier; m > 0 {
+		stats["storage_op_multiplier"] = m
+	}
+	if w.commits > 0 {
+		commits := float64(w.commits)
+		stats["commit_ops_mean"] = float64(w.commitOps) / commits
+		stats["commit_bytes_mean"] = float64(w.commitValueBytes.Load()) / commits
+	}
+	return stats
+}
+
 // GenerateKeys produces database keys representing transaction execution operations
 func (w *TransactionExecutionWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
 	return func(yield func([]byte) bool) {
 		rng := rand.New(rand.NewSource(seed))
 		keysGenerated := 0
+		w.amplification = amplificationStats{}
+		w.commits, w.commitOps = 0, 0
+		w.commitValueBytes.Store(0)
 
 		for keysGenerated < count {
 			// Generate a transaction
@@ -174,6 +284,7 @@ func (w *TransactionExecutionWorkload) GenerateKeys(seed int64, count int) iter.
 
 			// Calculate database operations for this transaction
 			breakdown := w.txModel.CalculateDatabaseOperations(txChars)
+			w.amplification.beginTx(breakdown)
 
 			// Generate keys for each operation type
 			keysGenerated += w.generateOperationKeys(yield, rng, txChars, breakdown, keysGenerated, count)
@@ -253,44 +364,60 @@ func (w *TransactionExecutionWorkload) generateOperationKeys(yield func([]byte)
 func (w *TransactionExecutionWorkload) generateBlockCommitKeys(yield func([]byte) bool, rng *rand.Rand, keysGenerated, maxKeys int) int {
 	generated := 0
 	
-	// Block commit involves several operations
-	blockCommitOps := 5 + rng.Intn(10) // 5-15 operations per block commit
+	// Block commit involves several operations, the state diff of the block
+	blockCommitOps := w.commit.opsMin + rng.Intn(w.commit.opsMax-w.commit.opsMin+1)
+	w.commits++
 	
 	for i := 0; i < blockCommitOps && keysGenerated+generated < maxKeys; i++ {
 		key := w.generateBlockCommitKey(rng)
+		if i == blockCommitOps-1 {
+			w.blocksEnded.Add(1)
+		}
 		if !yield(key) {
 			return generated
 		}
 		generated++
+		w.commitOps++
 	}
 	
 	return generated
 }
 
+// ObserveWrite counts a write the write phase issued against the operation
+// category of its key
+func (w *TransactionExecutionWorkload) ObserveWrite(key []byte) {
+	if category, _ := w.prefixes.classifyKey(key); category != keyCategoryUnknown {
+		w.amplification.execute(category)
+	}
+}
+
+// BlocksEnded counts the blocks whose commit has been fully yielded
+func (w *TransactionExecutionWorkload) BlocksEnded() uint64 {
+	return w.blocksEnded.Load()
+}
+
+// HotKeyPrefixes returns the account and storage prefixes of the hot accounts,
+// which are also the hot contracts. Keys hold raw addresses, not their hashes.
+func (w *TransactionExecutionWorkload) HotKeyPrefixes() [][]byte {
+	var prefixes [][]byte
+	for _, address := range w.accounts.HotAddresses() {
+		prefixes = append(prefixes, w.prefixes.key(opCategoryAccount, address), w.prefixes.key(opCategoryStorage, address))
+	}
+	return prefixes
+}
+
 // Helper methods for generating different operation types
 
 func (w *TransactionExecutionWorkload) generateAccountOperationKey(rng *rand.Rand, tx TransactionCharacteristics) []byte {
 	// Use hot accounts with high probability for spatial locality
-	var accountAddr []byte
-	if rng.Float64() < w.txModel.config.HotAccountProbability && len(w.hotAccounts) > 0 {
-		accountAddr = w.hotAccounts[rng.Intn(len(w.hotAccounts))]
-	} else {
-		accountAddr = make([]byte, 20)
-		rng.Read(accountAddr)
-	}
+	accountAddr := w.accounts.Pick(rng, w.txModel.config.HotAccountProbability)
 	
-	return append([]byte("account:"), accountAddr...)
+	return w.prefixes.key(opCategoryAccount, accountAddr)
 }
 
 func (w *TransactionExecutionWorkload) generateStorageOperationKey(rng *rand.Rand, tx TransactionCharacteristics) []byte {
 	// Generate realistic storage key with contract address + storage slot
-	var contractAddr []byte
-	if rng.Float64() < w.txModel.config.HotAccountProbability && len(w.hotAccounts) > 0 {
-		contractAddr = w.hotAccounts[rng.Intn(len(w.hotAccounts))]
-	} else {
-		contractAddr = make([]byte, 20)
-		rng.Read(contractAddr)
-	}
+	contractAddr := w.accounts.Pick(rng, w.txModel.config.HotAccountProbability)
 	
 	// Generate storage slot with locality (related slots accessed together)
 	storageSlot := make([]byte, 32)
@@ -312,8 +439,7 @@ func (w *TransactionExecutionWorkload) generateStorageOperationKey(rng *rand.Ran
 		rng.Read(storageSlot)
 	}
 
-	key := append([]byte("storage:"), contractAddr...)
-	return append(key, storageSlot...)
+	return w.prefixes.key(opCategoryStorage, contractAddr, storageSlot)
 }
 
 func (w *TransactionExecutionWorkload) generateTrieOperationKey(rng *rand.Rand, tx TransactionCharacteristics) []byte {
@@ -327,7 +453,7 @@ func (w *TransactionExecutionWorkload) generateTrieOperationKey(rng *rand.Rand,
 	nodeKey := make([]byte, depth)
 	rng.Read(nodeKey)
 	
-	return append([]byte("trie:"), nodeKey...)
+	return w.prefixes.key(opCategoryTrie, nodeKey)
 }
 
 func (w *TransactionExecutionWorkload) generatePersistenceOperationKey(rng *rand.Rand, tx TransactionCharacteristics) []byte {
@@ -335,7 +461,7 @@ func (w *TransactionExecutionWorkload) generatePersistenceOperationKey(rng *rand
 	txHash := make([]byte, 32)
 	rng.Read(txHash)
 	
-	return append([]byte("wal:"), txHash...)
+	return w.prefixes.key(opCategoryPersistence, txHash)
 }
 
 func (w *TransactionExecutionWorkload) generateBlockCommitKey(rng *rand.Rand) []byte {
@@ -343,34 +469,27 @@ func (w *TransactionExecutionWorkload) generateBlockCommitKey(rng *rand.Rand) []
 	blockHash := make([]byte, 32)
 	rng.Read(blockHash)
 	
-	return append([]byte("block:"), blockHash...)
+	return w.prefixes.key(opCategoryBlockCommit, blockHash)
 }
 
 // GenerateValue creates realistic values based on operation type
 func (w *TransactionExecutionWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
-	if len(key) < 8 {
-		// Default value
-		value := make([]byte, w.config.ValueSize)
-		rng.Read(value)
-		return value
-	}
+	category, _ := w.prefixes.classifyKey(key)
 
-	prefix := string(key[:minInt(8, len(key))])
-
-	switch {
-	case prefix == "account:":
+	switch category {
+	case opCategoryAccount:
 		// Account data: nonce + balance + storage root + code hash
 		return w.generateAccountValue(rng)
-	case prefix == "storage:":
+	case opCategoryStorage:
 		// Storage slot value: 32 bytes
 		return w.generateStorageValue(rng)
-	case prefix == "trie:___":
+	case opCategoryTrie:
 		// Trie node: variable size RLP-encoded data
 		return w.generateTrieNodeValue(rng)
-	case prefix == "wal:____":
+	case opCategoryPersistence:
 		// WAL entry: transaction data
 		return w.generateWALValue(rng)
-	case prefix == "block:__":
+	case opCategoryBlockCommit:
 		// Block data: block header and metadata
 		return w.generateBlockValue(rng)
 	default:
@@ -381,6 +500,16 @@ func (w *TransactionExecutionWorkload) GenerateValue(rng *rand.Rand, key []byte)
 	}
 }
 
+// IgnoresValueSize reports true: every operation category carries its own size
+func (w *TransactionExecutionWorkload) IgnoresValueSize() bool {
+	return true
+}
+
+// KeyPrefixes returns the prefixes of the registered key table
+func (w *TransactionExecutionWorkload) KeyPrefixes() [][]byte {
+	return w.prefixes.prefixes()
+}
+
 // Helper methods for generating realistic values
 
 func (w *TransactionExecutionWorkload) generateAccountValue(rng *rand.Rand) []byte {
@@ -400,9 +529,7 @@ func (w *TransactionExecutionWorkload) generateStorageValue(rng *rand.Rand) []by
 func (w *TransactionExecutionWorkload) generateTrieNodeValue(rng *rand.Rand) []byte {
 	// Trie nodes: 64-512 bytes typically, RLP encoded
 	size := rng.Intn(450) + 64
-	value := make([]byte, size)
-	rng.Read(value)
-	return value
+	return trieNodeValue(rng, w.config.TrieNodeEncoding, size)
 }
 
 func (w *TransactionExecutionWorkload) generateWALValue(rng *rand.Rand) []byte {
@@ -415,7 +542,8 @@ func (w *TransactionExecutionWorkload) generateWALValue(rng *rand.Rand) []byte {
 
 func (w *TransactionExecutionWorkload) generateBlockValue(rng *rand.Rand) []byte {
 	// Block data: block header + transaction list + metadata
-	size := rng.Intn(5000) + 500 // 500-5500 bytes
+	size := w.commit.valueMin + rng.Intn(w.commit.valueMax-w.commit.valueMin+1)
+	w.commitValueBytes.Add(uint64(size))
 	value := make([]byte, size)
 	rng.Read(value)
 	return value
@@ -423,28 +551,24 @@ func (w *TransactionExecutionWorkload) generateBlockValue(rng *rand.Rand) []byte
 
 // ShouldRead determines read vs write based on operation type and realistic ratios
 func (w *TransactionExecutionWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
-	if len(key) < 8 {
-		return rng.Float64() < w.config.ReadRatio
-	}
-
-	prefix := string(key[:minInt(8, len(key))])
+	category, _ := w.prefixes.classifyKey(key)
 
-	switch {
-	case prefix == "account:":
+	switch category {
+	case opCategoryAccount:
 		// Account operations: mostly reads for balance/nonce checks
 		readProbability := w.txModel.config.ReadWriteRatio / (w.txModel.config.ReadWriteRatio + 1.0)
 		return rng.Float64() < readProbability
-	case prefix == "storage:":
+	case opCategoryStorage:
 		// Storage operations: based on configured read/write ratio
 		readProbability := w.txModel.config.ReadWriteRatio / (w.txModel.config.ReadWriteRatio + 1.0)
 		return rng.Float64() < readProbability
-	case prefix == "trie:___":
+	case opCategoryTrie:
 		// Trie operations: many reads for traversal, some writes for updates
 		return rng.Float64() < 0.7
-	case prefix == "wal:____":
+	case opCategoryPersistence:
 		// WAL operations: mostly writes for transaction logging
 		return rng.Float64() < 0.1
-	case prefix == "block:__":
+	case opCategoryBlockCommit:
 		// Block operations: mostly writes for block commits
 		return rng.Float64() < 0.2
 	default:
@@ -467,49 +591,41 @@ func (w *TransactionExecutionWorkload) GenerateRangeQuery(rng *rand.Rand) (start
 	switch queryType {
 	case "account_range":
 		// Range over accounts (e.g., for state sync)
-		start = append([]byte("account:"), make([]byte, 20)...)
-		end = append([]byte("account:"), bytes.Repeat([]byte{0xFF}, 20)...)
+		start = w.prefixes.key(opCategoryAccount, make([]byte, 20))
+		end = w.prefixes.key(opCategoryAccount, bytes.Repeat([]byte{0xFF}, 20))
 
 	case "storage_range":
 		// Range over contract storage (e.g., contract state dump)
-		var contractAddr []byte
-		if len(w.hotAccounts) > 0 {
-			contractAddr = w.hotAccounts[rng.Intn(len(w.hotAccounts))]
-		} else {
-			contractAddr = make([]byte, 20)
-			rng.Read(contractAddr)
-		}
-		start = append([]byte("storage:"), contractAddr...)
-		start = append(start, make([]byte, 32)...)
-		end = append([]byte("storage:"), contractAddr...)
-		end = append(end, bytes.Repeat([]byte{0xFF}, 32)...)
+		contractAddr := w.accounts.Pick(rng, 1)
+		start = w.prefixes.key(opCategoryStorage, contractAddr, make([]byte, 32))
+		end = w.prefixes.key(opCategoryStorage, contractAddr, bytes.Repeat([]byte{0xFF}, 32))
 
 	case "trie_range":
 		// Range over trie nodes at specific depth
 		depth := rng.Intn(8) + 1
 		prefix := make([]byte, depth)
 		rng.Read(prefix)
-		start = append([]byte("trie:"), prefix...)
-		end = append([]byte("trie:"), prefix...)
-		if len(end) > 5 {
-			end[len(end)-1] = 0xFF
-		}
+		start = w.prefixes.key(opCategoryTrie, prefix)
+		end = prefixUpperBound(start)
 
 	case "wal_range":
-		// Range over WAL entries for a transaction batch
-		start = append([]byte("wal:"), make([]byte, 32)...)
-		end = append([]byte("wal:"), make([]byte, 32)...)
-		// Set a reasonable range for recent transactions
-		for i := 16; i < 32; i++ {
-			end[5+i] = 0xFF
-		}
+		// Range over the WAL entries whose transaction hashes share a
+		// random leading byte, so the scan lands among written entries
+		prefix := make([]byte, 1)
+		rng.Read(prefix)
+		start = w.prefixes.key(opCategoryPersistence, prefix)
+		end = prefixUpperBound(start)
 
 	case "block_range":
 		// Range over recent blocks
 		limit = rng.Intn(50) + 5 // 5-50 blocks
-		blockStart := w.blockNumber - uint64(rng.Intn(100)) // Recent blocks
-		start = append([]byte("block:"), uint64ToBytes(blockStart)...)
-		end = append([]byte("block:"), uint64ToBytes(blockStart+uint64(limit))...)
+		blockOffset := uint64(rng.Intn(100))
+		if blockOffset > w.blockNumber {
+			blockOffset = w.blockNumber // avoid wrapping below block 0
+		}
+		blockStart := w.blockNumber - blockOffset // Recent blocks
+		start = w.prefixes.key(opCategoryBlockCommit, uint64ToBytes(blockStart))
+		end = w.prefixes.key(opCategoryBlockCommit, uint64ToBytes(blockStart+uint64(limit)))
 	}
 
 	return start, end, limit
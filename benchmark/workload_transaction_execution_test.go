@@ -0,0 +1,167 @@
+package benchmark
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// testTransactionWorkload returns a transaction-execution workload on the
+// balanced Ethereum mix with every tunable at its default
+func testTransactionWorkload() *TransactionExecutionWorkload {
+	return NewTransactionExecutionWorkload(WorkloadConfig{
+		Type:               WorkloadTransactionExecution,
+		ValueSize:          7, // distinct from every per-type size, so a fallthrough shows
+		Seed:               42,
+		NetworkType:        "ethereum",
+		TransactionMix:     "balanced",
+		TxHotAccountProb:   -1,
+		TxStorageLocality:  -1,
+		TxCacheHitRatio:    -1,
+		TxAccountTrieDepth: -1,
+		TxStorageTrieDepth: -1,
+		TxReadWriteRatio:   -1,
+		TxContractRatio:    -1,
+		TxPerBlock:         2,
+		GasTargetPerBlock:  15000000,
+	})
+}
+
+func TestTransactionExecutionRoutesKeysByPrefix(t *testing.T) {
+	quietLogs(t)
+
+	w := testTransactionWorkload()
+
+	// Value size bounds per key prefix
+	sizes := map[string][2]int{
+		"account:": {104, 104},
+		"storage:": {32, 32},
+		"trie:":    {64, 513},
+		"wal:":     {100, 2099},
+		"block:":   {defaultCommitValueMin, defaultCommitValueMax},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	seen := make(map[string]int)
+	for key := range w.GenerateKeys(42, 5000) {
+		prefix := literalPrefix(key, sizes)
+		if prefix == "" {
+			t.Fatalf("key %q matches no key type", key)
+		}
+		bounds := sizes[prefix]
+		seen[prefix]++
+
+		if n := len(w.GenerateValue(rng, key)); n < bounds[0] || n > bounds[1] {
+			t.Errorf("key %q got a %d-byte value, want %d-%d", key, n, bounds[0], bounds[1])
+		}
+	}
+
+	for prefix := range sizes {
+		if seen[prefix] == 0 {
+			t.Errorf("no %q keys generated", prefix)
+		}
+	}
+}
+
+// literalPrefix returns the key of prefixes that key starts with, "" for none
+func literalPrefix[V any](key []byte, prefixes map[string]V) string {
+	for prefix := range prefixes {
+		if bytes.HasPrefix(key, []byte(prefix)) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+func TestTransactionExecutionShouldReadByPrefix(t *testing.T) {
+	quietLogs(t)
+
+	w := testTransactionWorkload()
+	rw := w.txModel.config.ReadWriteRatio
+	accountReads := rw / (rw + 1)
+
+	// Read probability per key prefix
+	want := map[string]float64{
+		"account:": accountReads,
+		"storage:": accountReads,
+		"trie:":    0.7,
+		"wal:":     0.1,
+		"block:":   0.2,
+	}
+
+	// One generated key of each type, asked draws times
+	samples := make(map[string][]byte)
+	for key := range w.GenerateKeys(42, 5000) {
+		if prefix := literalPrefix(key, want); prefix != "" && samples[prefix] == nil {
+			samples[prefix] = key
+		}
+	}
+
+	const draws = 4000
+	rng := rand.New(rand.NewSource(1))
+	for prefix, p := range want {
+		key := samples[prefix]
+		if key == nil {
+			t.Errorf("no %q keys generated", prefix)
+			continue
+		}
+		reads := 0
+		for i := 0; i < draws; i++ {
+			if w.ShouldRead(key, rng) {
+				reads++
+			}
+		}
+		if got := float64(reads) / draws; got < p-0.05 || got > p+0.05 {
+			t.Errorf("%q keys read with probability %.2f, want %.2f", prefix, got, p)
+		}
+	}
+}
+
+func TestTransactionExecutionWALRangesReturnItems(t *testing.T) {
+	quietLogs(t)
+
+	db, err := NewMemoryDatabase(DatabaseConfig{Type: DatabaseTypeMemory})
+	if err != nil {
+		t.Fatalf("NewMemoryDatabase: %v", err)
+	}
+	defer db.Close()
+
+	w := testTransactionWorkload()
+	for key := range w.GenerateKeys(42, 20000) {
+		if err := db.Set(key, []byte("value")); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+
+	walPrefix := w.prefixes.key(opCategoryPersistence)
+	rng := rand.New(rand.NewSource(1))
+	var ranges, empty int
+	for ranges < 100 {
+		start, end, limit := w.GenerateRangeQuery(rng)
+		if !bytes.HasPrefix(start, walPrefix) {
+			continue
+		}
+		ranges++
+		timing, err := scanRange(db, start, end, limit, false)
+		if err != nil {
+			t.Fatalf("scanRange(%x, %x): %v", start, end, err)
+		}
+		if timing.items == 0 {
+			empty++
+		}
+	}
+	if empty > 5 {
+		t.Fatalf("%d of %d wal ranges were empty after the write phase", empty, ranges)
+	}
+}
+
+func TestKeyPrefixTableRejectsCollisions(t *testing.T) {
+	if _, err := transactionKeyPrefixes([]byte("block:")); err != nil {
+		t.Fatalf("default prefixes: %v", err)
+	}
+	for _, prefix := range []string{"", "wal:x", "trie"} {
+		if _, err := transactionKeyPrefixes([]byte(prefix)); err == nil {
+			t.Errorf("commit prefix %q: want a collision error", prefix)
+		}
+	}
+}
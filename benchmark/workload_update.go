@@ -0,0 +1,144 @@
+package benchmark
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"sync"
+)
+
+// updateZipfSkew is the Zipf exponent used to pick which existing key to overwrite.
+// Values just above 1 concentrate most updates on a small hot set.
+const updateZipfSkew = 1.1
+
+// StatsReporter is implemented by workloads that track statistics about the
+// keys they generate. RunBenchmark logs the stats after the write phase.
+type StatsReporter interface {
+	Stats() map[string]interface{}
+}
+
+// UpdateWorkload populates a fixed keyspace and then overwrites existing keys
+// with Zipfian skew, exercising the LSM overwrite path instead of unique inserts
+type UpdateWorkload struct {
+	config   WorkloadConfig
+	keyspace int
+
+	mu       sync.Mutex
+	versions []uint32 // writes per key index during the last key generation
+	updates  int
+}
+
+// NewUpdateWorkload creates a new update-heavy workload
+func NewUpdateWorkload(cfg WorkloadConfig) *UpdateWorkload {
+	keyspace := cfg.Keyspace
+	if keyspace <= 0 {
+		keyspace = 100000
+	}
+
+	return &UpdateWorkload{
+		config:   cfg,
+		keyspace: keyspace,
+	}
+}
+
+func (w *UpdateWorkload) Name() string {
+	return "Update"
+}
+
+func (w *UpdateWorkload) GetDescription() string {
+	return fmt.Sprintf("Populates %d unique keys, then overwrites existing keys with Zipfian skew (s=%.1f)",
+		w.keyspace, updateZipfSkew)
+}
+
+// keyForIndex returns the 32-byte hashed key for keyspace index i
+func (w *UpdateWorkload) keyForIndex(i uint64) []byte {
+	return keyForIndex(w.config.Seed, i)
+}
+
+// GenerateKeys yields every keyspace key once, then spends the rest of count
+// on overwrites of existing keys chosen by a Zipf distribution
+func (w *UpdateWorkload) GenerateKeys(seed int64, count int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		rng := rand.New(rand.NewSource(seed))
+		zipf := rand.NewZipf(rng, updateZipfSkew, 1, uint64(w.keyspace-1))
+
+		w.mu.Lock()
+		w.versions = make([]uint32, w.keyspace)
+		w.updates = 0
+		w.mu.Unlock()
+
+		for i := 0; i < count; i++ {
+			idx := uint64(i)
+			if i >= w.keyspace {
+				idx = zipf.Uint64()
+			}
+
+			w.mu.Lock()
+			w.versions[idx]++
+			if i >= w.keyspace {
+				w.updates++
+			}
+			w.mu.Unlock()
+
+			if !yield(w.keyForIndex(idx)) {
+				return
+			}
+		}
+	}
+}
+
+// KeysIgnoreSeed reports true: keyspace keys are addressed by index
+func (w *UpdateWorkload) KeysIgnoreSeed() bool {
+	return true
+}
+
+func (w *UpdateWorkload) GenerateValue(rng *rand.Rand, key []byte) []byte {
+	value := make([]byte, w.config.ValueSize)
+	rng.Read(value)
+	return value
+}
+
+func (w *UpdateWorkload) ShouldRead(key []byte, rng *rand.Rand) bool {
+	return rng.Float64() < w.config.ReadRatio
+}
+
+func (w *UpdateWorkload) SupportsRangeQueries() bool {
+	return false
+}
+
+func (w *UpdateWorkload) GenerateRangeQuery(rng *rand.Rand) (start, end []byte, limit int) {
+	return nil, nil, 0
+}
+
+// Stats reports key-version churn from the last key generation
+func (w *UpdateWorkload) Stats() map[string]interface{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var written, updated, updatedVersions int
+	var maxVersions uint32
+	for _, v := range w.versions {
+		if v > 0 {
+			written++
+		}
+		if v > 1 {
+			updated++
+			updatedVersions += int(v)
+		}
+		maxVersions = max(maxVersions, v)
+	}
+
+	avgVersions := 0.0
+	if updated > 0 {
+		avgVersions = float64(updatedVersions) / float64(updated)
+	}
+
+	return map[string]interface{}{
+		"keyspace":                 w.keyspace,
+		"keys_written":             written,
+		"overwrites":               w.updates,
+		"keys_overwritten":         updated,
+		"max_versions_per_key":     maxVersions,
+		"avg_versions_overwritten": avgVersions,
+	}
+}
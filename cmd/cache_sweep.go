@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var (
+	sweepSizes  []int
+	sweepReads  int
+	sweepDBPath string
+	sweepKeep   bool
+	sweepOutput string
+)
+
+// cacheSweepCmd represents the cache-sweep command
+var cacheSweepCmd = &cobra.Command{
+	Use:   "cache-sweep",
+	Short: "Populate increasing keyspace sizes at a fixed block cache and report read latency at each, as CSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := benchmark.CacheSweepConfig{
+			Sizes:          sweepSizes,
+			Reads:          sweepReads,
+			DBPath:         sweepDBPath,
+			Keep:           sweepKeep,
+			Output:         sweepOutput,
+			DatabaseType:   databaseType,
+			BlockCacheSize: blockCacheSize,
+			WorkloadType:   workloadType,
+			ValueSize:      valueSize,
+			Concurrency:    concurrency,
+			Seed:           seed,
+			LogFormat:      logFormat,
+		}
+
+		if err := benchmark.RunCacheSweep(cfg); err != nil {
+			log.Fatalf("Cache sweep failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheSweepCmd)
+
+	cacheSweepCmd.Flags().IntSliceVar(&sweepSizes, "sizes", []int{1000000, 10000000, 100000000}, "Comma-separated populated keyspace sizes to sweep, smallest first")
+	cacheSweepCmd.Flags().IntVar(&sweepReads, "reads", 1000000, "Reads measured at every size")
+	cacheSweepCmd.Flags().StringVar(&sweepDBPath, "db-path", "dbs/pebble/cache-sweep", "Directory holding one fresh database per size")
+	cacheSweepCmd.Flags().BoolVar(&sweepKeep, "keep", false, "Keep each size's database instead of removing it once measured")
+	cacheSweepCmd.Flags().StringVar(&sweepOutput, "output", "cache-sweep.csv", "CSV file for the per-size results")
+	cacheSweepCmd.Flags().StringVar(&databaseType, "database", "pebble", "Database backend: 'pebble', 'mdbx' or 'memory'")
+	cacheSweepCmd.Flags().Int64Var(&blockCacheSize, "block-cache-size", 8<<20, "Block cache size in bytes held fixed across sizes (negative for disabled, default 8MB)")
+	cacheSweepCmd.Flags().StringVar(&workloadType, "workload", "generic", "Workload whose read pattern is mapped onto the populated keys, e.g. 'update' for Zipfian reads")
+	cacheSweepCmd.Flags().IntVar(&valueSize, "value-size", 256, "Size of each populated value in bytes")
+	cacheSweepCmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of concurrent workers")
+	cacheSweepCmd.Flags().Int64Var(&seed, "seed", 42, "Seed for deterministic key/value generation")
+	cacheSweepCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var (
+	diffResultsFiles []string
+	diffThreshold    float64
+	diffNoColor      bool
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two runs written with --results-file and print the change in each metric",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(diffResultsFiles) != 2 {
+			log.Fatalf("diff needs exactly two --results-file paths, base then head, got %d", len(diffResultsFiles))
+		}
+		cfg := benchmark.DiffConfig{
+			Base:      diffResultsFiles[0],
+			Head:      diffResultsFiles[1],
+			Threshold: diffThreshold,
+			NoColor:   diffNoColor,
+		}
+
+		if err := benchmark.RunDiff(cfg); err != nil {
+			log.Fatalf("Diff failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringArrayVar(&diffResultsFiles, "results-file", nil, "Results file to compare, given twice: the baseline run first, then the run compared against it")
+	diffCmd.Flags().Float64Var(&diffThreshold, "threshold", 5, "Percentage change below which a difference is not flagged as a regression or improvement")
+	diffCmd.Flags().BoolVar(&diffNoColor, "no-color", false, "Disable colored output")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var (
+	dumpKeysOutput string
+	dumpKeysFormat string
+	dumpKeysLimit  int
+)
+
+// dumpKeysCmd represents the dump-keys command
+var dumpKeysCmd = &cobra.Command{
+	Use:   "dump-keys",
+	Short: "Write the keys of an existing database as a keys file (binary) or one key per line (hex, base64)",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := benchmark.DumpKeysConfig{
+			DBPath:         dbPath,
+			DatabaseType:   databaseType,
+			BlockCacheSize: blockCacheSize,
+			LogFormat:      logFormat,
+			PebbleComparer: pebbleComparer,
+			Output:         dumpKeysOutput,
+			Format:         benchmark.KeyFormat(dumpKeysFormat),
+			Limit:          dumpKeysLimit,
+		}
+
+		if err := benchmark.RunDumpKeys(cfg); err != nil {
+			log.Fatalf("Dump keys failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dumpKeysCmd)
+
+	dumpKeysCmd.Flags().StringVar(&dbPath, "db-path", "dbs/pebble/pebble-test-db", "Path to the existing database")
+	dumpKeysCmd.Flags().StringVar(&databaseType, "database", "pebble", "Database backend: 'pebble' or 'mdbx'")
+	dumpKeysCmd.Flags().Int64Var(&blockCacheSize, "block-cache-size", 8<<20, "Block cache size in bytes (negative for disabled, default 8MB)")
+	dumpKeysCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+	dumpKeysCmd.Flags().StringVar(&pebbleComparer, "pebble-comparer", "default", "Pebble: Key ordering the database was created with, 'default' or 'blocknum'")
+	dumpKeysCmd.Flags().StringVar(&dumpKeysOutput, "output", "keys.bin", "File to write the keys to ('-' for standard output, logging goes to standard error)")
+	dumpKeysCmd.Flags().StringVar(&dumpKeysFormat, "format", "binary", "Key encoding: 'binary' ([uvarint length][key], readable by run --keys-file), 'hex' or 'base64' (one key per line)")
+	dumpKeysCmd.Flags().IntVar(&dumpKeysLimit, "limit", 0, "Only dump the first N keys in key order (0 dumps every key)")
+}
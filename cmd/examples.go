@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// example is a ready-to-run command line for a common scenario
+type example struct {
+	name        string
+	description string
+	args        []string // arguments after the binary name, starting with the subcommand
+}
+
+var examples = []example{
+	{
+		name:        "cold-read",
+		description: "Populate a database, then read every key once in random order with the block cache disabled",
+		args:        []string{"run", "--write", "--key-count", "1000000", "--block-cache-size", "-1", "--read-order", "shuffled", "--db-path", "dbs/pebble/cold-read", "--summary"},
+	},
+	{
+		name:        "durable-writes",
+		description: "Fsync the WAL on every write and report WAL append/fsync metrics",
+		args:        []string{"run", "--write", "--sync-writes", "--key-count", "200000", "--db-path", "dbs/pebble/durable-writes", "--summary"},
+	},
+	{
+		name:        "no-wal",
+		description: "Write with Pebble's WAL disabled; rerun without --pebble-disable-wal to measure the WAL's cost",
+		args:        []string{"run", "--write", "--pebble-disable-wal", "--key-count", "1000000", "--db-path", "dbs/pebble/no-wal", "--summary"},
+	},
+	{
+		name:        "pos-mixed-pebble",
+		description: "Mixed PoS workload on Pebble; compare with pos-mixed-mdbx",
+		args:        []string{"run", "--write", "--workload", "pos-mixed", "--key-count", "500000", "--concurrency", "4", "--database", "pebble", "--db-path", "dbs/pebble/pos-mixed", "--summary"},
+	},
+	{
+		name:        "pos-mixed-mdbx",
+		description: "Mixed PoS workload on MDBX; compare with pos-mixed-pebble",
+		args:        []string{"run", "--write", "--workload", "pos-mixed", "--key-count", "500000", "--concurrency", "4", "--database", "mdbx", "--db-path", "dbs/mdbx/pos-mixed", "--summary"},
+	},
+	{
+		name:        "tx-custom-mix",
+		description: "Transaction execution with a custom transfer/DeFi mix",
+		args: []string{"run", "--write", "--workload", "transaction-execution", "--key-count", "500000",
+			"--tx-simple-transfer-ratio", "0.5", "--tx-erc20-transfer-ratio", "0.3", "--tx-uniswap-swap-ratio", "0.1",
+			"--tx-complex-defi-ratio", "0.05", "--tx-contract-deploy-ratio", "0.05", "--db-path", "dbs/pebble/tx-custom-mix"},
+	},
+	{
+		name:        "block-range-scans",
+		description: "Block workload with range scans in both directions",
+		args:        []string{"run", "--write", "--workload", "pos-blocks", "--key-count", "500000", "--range-queries", "1000", "--scan-direction", "both", "--db-path", "dbs/pebble/block-range-scans"},
+	},
+}
+
+var exampleRun int
+
+// examplesCmd represents the examples command
+var examplesCmd = &cobra.Command{
+	Use:   "examples",
+	Short: "Print ready-to-run command lines for common scenarios, or run one with --run N",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, e := range examples {
+			if err := validateExample(e); err != nil {
+				log.Fatalf("Example %s is out of date: %v", e.name, err)
+			}
+		}
+
+		if exampleRun == 0 {
+			for i, e := range examples {
+				fmt.Printf("%d. %s: %s\n   %s %s\n\n", i+1, e.name, e.description, rootCmd.Name(), strings.Join(e.args, " "))
+			}
+			return
+		}
+
+		if exampleRun < 1 || exampleRun > len(examples) {
+			log.Fatalf("Invalid --run %d: expected 1-%d", exampleRun, len(examples))
+		}
+		e := examples[exampleRun-1]
+
+		self, err := os.Executable()
+		if err != nil {
+			log.Fatalf("Failed to locate executable: %v", err)
+		}
+		fmt.Printf("Running example %s: %s %s\n", e.name, rootCmd.Name(), strings.Join(e.args, " "))
+
+		run := exec.Command(self, e.args...)
+		run.Stdin, run.Stdout, run.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := run.Run(); err != nil {
+			log.Fatalf("Example %s failed: %v", e.name, err)
+		}
+	},
+}
+
+// validateExample checks that an example's subcommand and flags exist, so the
+// gallery fails loudly instead of printing stale command lines
+func validateExample(e example) error {
+	sub, _, err := rootCmd.Find(e.args[:1])
+	if err != nil || sub == rootCmd {
+		return fmt.Errorf("unknown subcommand %q", e.args[0])
+	}
+	for _, arg := range e.args[1:] {
+		name, ok := strings.CutPrefix(arg, "--")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, "=")
+		if sub.Flags().Lookup(name) == nil {
+			return fmt.Errorf("unknown flag --%s for %s", name, sub.Name())
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(examplesCmd)
+
+	examplesCmd.Flags().IntVar(&exampleRun, "run", 0, "Run the Nth example instead of listing them (0 lists)")
+}
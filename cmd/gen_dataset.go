@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var genDatasetOutput string
+
+// genDatasetCmd represents the gen-dataset command
+var genDatasetCmd = &cobra.Command{
+	Use:   "gen-dataset",
+	Short: "Write the key/value pairs a run would generate to a file that run --load-dataset loads into any backend",
+	Long: `gen-dataset takes the workload flags of run and writes the pairs the write
+phase would generate, as [uvarint key length][key][uvarint value length][value]
+records after a magic header. Loading the file with run --write --load-dataset
+writes the identical keys and values to every backend, taking generation out of
+cross-backend comparisons.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := runConfig()
+		cfg.GenDataset = genDatasetOutput
+
+		if err := benchmark.RunGenDataset(cfg); err != nil {
+			log.Fatalf("Generate dataset failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(genDatasetCmd)
+
+	// The workload flags are run's, added by its init
+	genDatasetCmd.Flags().StringVar(&genDatasetOutput, "output", "dataset.bin", "File to write the key/value pairs to")
+}
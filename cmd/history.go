@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var (
+	historyResultsDB string
+	historyWorkload  string
+	historyBackend   string
+	historyLast      int
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Print recent runs recorded with --results-db and their throughput trend",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := benchmark.HistoryConfig{
+			ResultsDB: historyResultsDB,
+			Workload:  historyWorkload,
+			Backend:   historyBackend,
+			Last:      historyLast,
+		}
+
+		if err := benchmark.RunHistory(cfg); err != nil {
+			log.Fatalf("History failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historyResultsDB, "results-db", "results.sqlite", "SQLite database the runs were recorded in")
+	historyCmd.Flags().StringVar(&historyWorkload, "workload", "", "Only show runs of this workload type (empty for all)")
+	historyCmd.Flags().StringVar(&historyBackend, "database", "", "Only show runs against this backend (empty for all)")
+	historyCmd.Flags().IntVar(&historyLast, "last", 30, "Number of most recent runs to show")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var inspectLimit int
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Report key count, sizes and a key prefix histogram of an existing database",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := benchmark.InspectConfig{
+			DBPath:         dbPath,
+			DatabaseType:   databaseType,
+			BlockCacheSize: blockCacheSize,
+			LogFormat:      logFormat,
+			PebbleComparer: pebbleComparer,
+			Limit:          inspectLimit,
+		}
+
+		if err := benchmark.RunInspect(cfg); err != nil {
+			log.Fatalf("Inspect failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+
+	inspectCmd.Flags().StringVar(&dbPath, "db-path", "dbs/pebble/pebble-test-db", "Path to the existing database")
+	inspectCmd.Flags().StringVar(&databaseType, "database", "pebble", "Database backend: 'pebble' or 'mdbx'")
+	inspectCmd.Flags().Int64Var(&blockCacheSize, "block-cache-size", 8<<20, "Block cache size in bytes (negative for disabled, default 8MB)")
+	inspectCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+	inspectCmd.Flags().StringVar(&pebbleComparer, "pebble-comparer", "default", "Pebble: Key ordering the database was created with, 'default' or 'blocknum'")
+	inspectCmd.Flags().IntVar(&inspectLimit, "limit", 0, "Only inspect the first N keys in key order (0 walks the whole database)")
+}
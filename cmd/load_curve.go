@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var (
+	curveRates        []float64
+	curveOps          int
+	curveMaxP99Factor float64
+	curveSaturation   float64
+	curveDBPath       string
+	curveKeep         bool
+	curveOutput       string
+)
+
+// loadCurveCmd represents the load-curve command
+var loadCurveCmd = &cobra.Command{
+	Use:   "load-curve",
+	Short: "Step the target ops/sec through a series of rates and report throughput and p99 latency at each, as CSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := benchmark.LoadCurveConfig{
+			Rates:          curveRates,
+			Ops:            curveOps,
+			MaxP99Factor:   curveMaxP99Factor,
+			Saturation:     curveSaturation,
+			DBPath:         curveDBPath,
+			Keep:           curveKeep,
+			Output:         curveOutput,
+			DatabaseType:   databaseType,
+			BlockCacheSize: blockCacheSize,
+			WorkloadType:   workloadType,
+			ValueSize:      valueSize,
+			Concurrency:    concurrency,
+			Seed:           seed,
+			LogFormat:      logFormat,
+		}
+
+		if err := benchmark.RunLoadCurve(cfg); err != nil {
+			log.Fatalf("Load curve failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loadCurveCmd)
+
+	loadCurveCmd.Flags().Float64SliceVar(&curveRates, "rates", []float64{10000, 50000, 100000, 200000, 400000, 800000}, "Comma-separated target ops/sec to step through, lowest first")
+	loadCurveCmd.Flags().IntVar(&curveOps, "ops", 1000000, "Writes, then reads, measured at every rate")
+	loadCurveCmd.Flags().Float64Var(&curveMaxP99Factor, "max-p99-factor", 10, "Stop once a phase's p99 exceeds this multiple of its p99 at the first rate (0 disables)")
+	loadCurveCmd.Flags().Float64Var(&curveSaturation, "saturation", 0.9, "Stop once a phase achieves less than this fraction of the target rate")
+	loadCurveCmd.Flags().StringVar(&curveDBPath, "db-path", "dbs/pebble/load-curve", "Directory holding one fresh database per rate")
+	loadCurveCmd.Flags().BoolVar(&curveKeep, "keep", false, "Keep each rate's database instead of removing it once measured")
+	loadCurveCmd.Flags().StringVar(&curveOutput, "output", "load-curve.csv", "CSV file for the per-rate results")
+	loadCurveCmd.Flags().StringVar(&databaseType, "database", "pebble", "Database backend: 'pebble', 'mdbx' or 'memory'")
+	loadCurveCmd.Flags().Int64Var(&blockCacheSize, "block-cache-size", 8<<20, "Block cache size in bytes (negative for disabled, default 8MB)")
+	loadCurveCmd.Flags().StringVar(&workloadType, "workload", "generic", "Workload type")
+	loadCurveCmd.Flags().IntVar(&valueSize, "value-size", 256, "Size of each value in bytes")
+	loadCurveCmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of concurrent workers")
+	loadCurveCmd.Flags().Int64Var(&seed, "seed", 42, "Seed for deterministic key/value generation")
+	loadCurveCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tclemos/pebble-bench/benchmark"
@@ -13,16 +14,178 @@ var (
 	valueSize      int
 	seed           int64
 	dbPath         string
+	readDBPath     string
 	benchmarkID    string
 	writeEnabled   bool
 	keysFile       string
+	keysFormat     string
+	useExistingDB  bool
 	concurrency    int
+	queueDepth     int
+	readOrder      string
+	shuffleSeed    int64
 	logFormat      string
+	logLevel       string
 	blockCacheSize int64 // in bytes, negative means disabled (nil)
+	hdrOutput      string
+	latencyCSV     string
+	syncWrites     bool
+	summary        bool
+	quiet          bool
+
+	// Load pacing
+	targetOpsPerSec float64
+
+	// Key generation
+	generatorWorkers int
+
+	// Batched writes
+	applyBatch int
+
+	// Value shaping
+	valueAlign    int
+	valueDupRatio float64
+
+	// Value profile
+	valueProfile string
+
+	// Value determinism
+	deterministicValues bool
+
+	// Fill curve
+	fillCurve       string
+	fillCurveBucket int64
+
+	// Phase separation
+	flushBetweenPhases bool
+	quiesceTimeout     time.Duration
+
+	// Flush settling
+	flushWaitCompaction bool
+
+	// Compaction debt
+	l0FilesTarget int
+
+	// Byte limits
+	maxBytesWritten int64
+	maxBytesRead    int64
+
+	// Disk sizing
+	fillDisk float64
+
+	// Key analysis
+	analyzeKeys bool
+
+	// Run estimation
+	estimate bool
+
+	// Populated keyspace
+	populate int
+
+	// Historical tracking
+	resultsDB   string
+	resultsFile string
+
+	// Operation log
+	recordOps string
+	replayOps string
+
+	// Key capture
+	captureKeys string
+
+	// Latency injection
+	injectReadLatency   time.Duration
+	injectWriteLatency  time.Duration
+	injectLatencyJitter time.Duration
+
+	// Profiling
+	cpuProfile string
+	memProfile string
+
+	// Error handling
+	failOnError  bool
+	maxErrorRate float64
+
+	// Latency SLA
+	p99BudgetMs float64
+
+	// Instrumentation
+	measureGeneration bool
+	pregenerateValues bool
+	metricsInterval   time.Duration
+	spaceAmpInterval  time.Duration
+	verifyChecksums   bool
+
+	// Write order
+	sortKeys bool
+
+	// Cache warming
+	warmHotKeys bool
+
+	// Datasets
+	loadDataset string
+
+	// CPU scheduling
+	gomaxprocs   int
+	lockOSThread bool
+
+	// Range query phase
+	rangeQueries  int
+	scanDirection string
+	rangeLimit    int
+
+	// Read-after-write freshness phase
+	freshnessProbe bool
+	freshnessKeys  int
+	freshnessLags  string
+	freshnessDelay time.Duration
+
+	// Tombstone scan phase
+	tombstoneScan bool
+	tombstoneKeys int
 	
+	// Mixed read/write phase
+	mixed        bool
+	readWorkers  int
+	writeWorkers int
+
+	// Snapshot phase
+	snapshotReaders    int
+	snapshotsPerReader int
+	snapshotReads      int
+
+	// Write contention phase
+	contention        bool
+	contentionOps     int
+	collisionRate     float64
+	contentionHotKeys int
+
+	// Mixed value sizes
+	largeValueRatio float64
+	largeValueSize  int
+
+	// Trie simulation depth
+	trieAverageDepth      int
+	trieMaxDepth          int
+	trieDepthVariance     int
+	trieDepthDistribution string
+
+	// Trie node values
+	trieNodeEncoding string
+
 	// Database backend configuration
 	databaseType   string
 	qmdbLibraryPath string
+	pebbleComparer  string
+	pebbleDisableWAL bool
+	pebbleCopyValues bool
+
+	// Key namespace
+	keyPrefix string
+
+	// Pebble open file limits
+	pebbleMaxOpenFiles   int
+	pebbleTableCacheSize int
 	
 	// MDBX-specific configuration
 	mdbxMapSize     int64
@@ -35,12 +198,26 @@ var (
 	
 	// Workload configuration
 	workloadType     string
+	blend            string
+	readRatios       string
 	recentBlockBias  float64
 	hotAccountRatio  float64
+	hotAccountCount  int
 	stateLocality    float64
 	blockRange       int
+	blockKeyLayout   string
 	accountCount     int
 	storageSlotRatio float64
+	keyspace         int
+	storageTrieDepth int
+	contractCount    int
+
+	// Reorg workload configuration
+	reorgInterval int
+	reorgDepth    int
+
+	// Account existence workload configuration
+	existenceHitRatio float64
 	
 	// Transaction execution workload configuration
 	networkType              string
@@ -52,6 +229,7 @@ var (
 	txStorageTrieDepth       int
 	txReadWriteRatio         float64
 	txContractRatio          float64
+	txStorageOpMultiplier    float64
 	txPerBlock               int
 	gasTargetPerBlock        uint64
 	txSimpleTransferRatio    float64
@@ -59,6 +237,20 @@ var (
 	txUniswapSwapRatio       float64
 	txComplexDeFiRatio       float64
 	txContractDeployRatio    float64
+	blockTime                time.Duration
+
+	// Block commit shape
+	commitOpsMin    int
+	commitOpsMax    int
+	commitKeyPrefix string
+	commitValueMin  int
+	commitValueMax  int
+
+	// State commit batching
+	commitInterval          int
+	commitNodesMin          int
+	commitNodesMax          int
+	commitNodesDistribution string
 )
 
 // runCmd represents the run command
@@ -66,76 +258,294 @@ var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run database benchmark (Pebble, QMDB, or MDBX)",
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg := benchmark.Config{
-			KeyCount:         keyCount,
-			ReadRatio:        readRatio,
-			ValueSize:        valueSize,
-			Seed:             seed,
-			DBPath:           dbPath,
-			BenchmarkID:      benchmarkID,
-			WriteEnabled:     writeEnabled,
-			KeysFile:         keysFile,
-			Concurrency:      concurrency,
-			LogFormat:        logFormat,
-			BlockCacheSize:   blockCacheSize,
-			DatabaseType:     databaseType,
-			QMDBLibraryPath:  qmdbLibraryPath,
-			MDBXMapSize:      mdbxMapSize,
-			MDBXMaxDbs:       mdbxMaxDbs,
-			MDBXMaxReaders:   mdbxMaxReaders,
-			MDBXNoSync:       mdbxNoSync,
-			MDBXNoMetaSync:   mdbxNoMetaSync,
-			MDBXWriteMap:     mdbxWriteMap,
-			MDBXNoReadahead:  mdbxNoReadahead,
-			WorkloadType:     workloadType,
-			RecentBlockBias:  recentBlockBias,
-			HotAccountRatio:  hotAccountRatio,
-			StateLocality:    stateLocality,
-			BlockRange:       blockRange,
-			AccountCount:     accountCount,
-			StorageSlotRatio: storageSlotRatio,
-			// Transaction execution workload parameters
-			NetworkType:              networkType,
-			TransactionMix:           transactionMix,
-			TxHotAccountProb:         txHotAccountProb,
-			TxStorageLocality:        txStorageLocality,
-			TxCacheHitRatio:          txCacheHitRatio,
-			TxAccountTrieDepth:       txAccountTrieDepth,
-			TxStorageTrieDepth:       txStorageTrieDepth,
-			TxReadWriteRatio:         txReadWriteRatio,
-			TxContractRatio:          txContractRatio,
-			TxPerBlock:               txPerBlock,
-			GasTargetPerBlock:        gasTargetPerBlock,
-			TxSimpleTransferRatio:    txSimpleTransferRatio,
-			TxERC20TransferRatio:     txERC20TransferRatio,
-			TxUniswapSwapRatio:       txUniswapSwapRatio,
-			TxComplexDeFiRatio:       txComplexDeFiRatio,
-			TxContractDeployRatio:    txContractDeployRatio,
-		}
-		if err := benchmark.RunBenchmark(cfg); err != nil {
+		if err := benchmark.RunBenchmark(runConfig()); err != nil {
 			log.Fatalf("Benchmark failed: %v", err)
 		}
 	},
 }
 
+// runConfig builds the benchmark configuration from the run flags, which
+// gen-dataset shares
+func runConfig() benchmark.Config {
+	// A negative --trie-depth-variance keeps the default
+	var variance *int
+	if trieDepthVariance >= 0 {
+		variance = &trieDepthVariance
+	}
+
+	return benchmark.Config{
+		KeyCount:         keyCount,
+		ReadRatio:        readRatio,
+		ValueSize:        valueSize,
+		Seed:             seed,
+		DBPath:           dbPath,
+		ReadDBPath:       readDBPath,
+		BenchmarkID:      benchmarkID,
+		WriteEnabled:     writeEnabled,
+		KeysFile:         keysFile,
+		KeysFormat:       benchmark.KeyFormat(keysFormat),
+		UseExistingDB:    useExistingDB,
+		Concurrency:      concurrency,
+		QueueDepth:       queueDepth,
+		GeneratorWorkers: generatorWorkers,
+		ApplyBatch:       applyBatch,
+		SortKeys:         sortKeys,
+		LoadDataset:      loadDataset,
+		WarmHotKeys:      warmHotKeys,
+		GOMAXPROCS:       gomaxprocs,
+		LockOSThread:     lockOSThread,
+		TargetOpsPerSec:  targetOpsPerSec,
+		ReadOrder:        benchmark.ReadOrder(readOrder),
+		ShuffleSeed:      shuffleSeed,
+		ValueAlign:       valueAlign,
+		ValueDupRatio:    valueDupRatio,
+		LogFormat:        logFormat,
+		LogLevel:         logLevel,
+		BlockCacheSize:   blockCacheSize,
+		HDROutput:        hdrOutput,
+		LatencyCSV:       latencyCSV,
+		SyncWrites:       syncWrites,
+		Summary:          summary,
+		Quiet:            quiet,
+		FillDisk:         fillDisk,
+		AnalyzeKeys:      analyzeKeys,
+		Populate:         populate,
+		ResultsDB:        resultsDB,
+		ResultsFile:      resultsFile,
+		RecordOps:        recordOps,
+		ReplayOps:        replayOps,
+		CaptureKeys:      captureKeys,
+		CPUProfile:       cpuProfile,
+		MemProfile:       memProfile,
+		VerifyChecksums:  verifyChecksums,
+		FailOnError:      failOnError,
+		MaxErrorRate:     maxErrorRate,
+		P99BudgetMs:      p99BudgetMs,
+		MeasureGeneration: measureGeneration,
+		PregenerateValues: pregenerateValues,
+		MetricsInterval:   metricsInterval,
+		SpaceAmpInterval:  spaceAmpInterval,
+		RangeQueries:     rangeQueries,
+		ScanDirection:    benchmark.ScanDirection(scanDirection),
+		RangeLimit:       rangeLimit,
+		FreshnessProbe:   freshnessProbe,
+		FreshnessKeys:    freshnessKeys,
+		FreshnessLags:    freshnessLags,
+		FreshnessDelay:   freshnessDelay,
+		TombstoneScan:    tombstoneScan,
+		TombstoneKeys:    tombstoneKeys,
+		Mixed:             mixed,
+		ReadWorkers:       readWorkers,
+		WriteWorkers:      writeWorkers,
+		Contention:        contention,
+		ContentionOps:     contentionOps,
+		CollisionRate:     collisionRate,
+		ContentionHotKeys: contentionHotKeys,
+		DatabaseType:     databaseType,
+		QMDBLibraryPath:  qmdbLibraryPath,
+		PebbleComparer:   pebbleComparer,
+		PebbleDisableWAL: pebbleDisableWAL,
+		PebbleCopyValues: pebbleCopyValues,
+		// Key namespace
+		KeyPrefix: keyPrefix,
+		// Pebble open file limits
+		PebbleMaxOpenFiles:   pebbleMaxOpenFiles,
+		PebbleTableCacheSize: pebbleTableCacheSize,
+		MDBXMapSize:      mdbxMapSize,
+		MDBXMaxDbs:       mdbxMaxDbs,
+		MDBXMaxReaders:   mdbxMaxReaders,
+		MDBXNoSync:       mdbxNoSync,
+		MDBXNoMetaSync:   mdbxNoMetaSync,
+		MDBXWriteMap:     mdbxWriteMap,
+		MDBXNoReadahead:  mdbxNoReadahead,
+		WorkloadType:     workloadType,
+		Blend:            blend,
+		ReadRatios:       readRatios,
+		RecentBlockBias:  recentBlockBias,
+		HotAccountRatio:  hotAccountRatio,
+		HotAccountCount:  hotAccountCount,
+		StateLocality:    stateLocality,
+		BlockRange:       blockRange,
+		BlockKeyLayout:   blockKeyLayout,
+		AccountCount:     accountCount,
+		StorageSlotRatio: storageSlotRatio,
+		Keyspace:         keyspace,
+		StorageTrieDepth: storageTrieDepth,
+		ContractCount:    contractCount,
+		// Reorg workload configuration
+		ReorgInterval: reorgInterval,
+		ReorgDepth:    reorgDepth,
+		// Account existence workload configuration
+		ExistenceHitRatio: existenceHitRatio,
+		// Latency injection
+		InjectReadLatency:   injectReadLatency,
+		InjectWriteLatency:  injectWriteLatency,
+		InjectLatencyJitter: injectLatencyJitter,
+		// Value determinism
+		DeterministicValues: deterministicValues,
+		// Fill curve
+		FillCurve:       fillCurve,
+		FillCurveBucket: fillCurveBucket,
+		// Snapshot phase
+		SnapshotReaders:    snapshotReaders,
+		SnapshotsPerReader: snapshotsPerReader,
+		SnapshotReads:      snapshotReads,
+		// Run estimation
+		Estimate: estimate,
+		// Value profile
+		ValueProfile: benchmark.ValueProfile(valueProfile),
+		// Phase separation
+		FlushBetweenPhases: flushBetweenPhases,
+		QuiesceTimeout:     quiesceTimeout,
+		// Flush settling
+		FlushWaitCompaction: flushWaitCompaction,
+		// Compaction debt
+		L0FilesTarget: l0FilesTarget,
+		// Byte limits
+		MaxBytesWritten: maxBytesWritten,
+		MaxBytesRead:    maxBytesRead,
+		// Mixed value sizes
+		LargeValueRatio: largeValueRatio,
+		LargeValueSize:  largeValueSize,
+		// Trie simulation depth
+		TrieAverageDepth:      trieAverageDepth,
+		TrieMaxDepth:          trieMaxDepth,
+		TrieDepthVariance:     variance,
+		TrieDepthDistribution: trieDepthDistribution,
+		// Trie node values
+		TrieNodeEncoding: trieNodeEncoding,
+		// Transaction execution workload parameters
+		NetworkType:              networkType,
+		TransactionMix:           transactionMix,
+		TxHotAccountProb:         txHotAccountProb,
+		TxStorageLocality:        txStorageLocality,
+		TxCacheHitRatio:          txCacheHitRatio,
+		TxAccountTrieDepth:       txAccountTrieDepth,
+		TxStorageTrieDepth:       txStorageTrieDepth,
+		TxReadWriteRatio:         txReadWriteRatio,
+		TxContractRatio:          txContractRatio,
+		TxStorageOpMultiplier:    txStorageOpMultiplier,
+		TxPerBlock:               txPerBlock,
+		GasTargetPerBlock:        gasTargetPerBlock,
+		TxSimpleTransferRatio:    txSimpleTransferRatio,
+		TxERC20TransferRatio:     txERC20TransferRatio,
+		TxUniswapSwapRatio:       txUniswapSwapRatio,
+		TxComplexDeFiRatio:       txComplexDeFiRatio,
+		TxContractDeployRatio:    txContractDeployRatio,
+		BlockTime:                blockTime,
+		// Block commit shape
+		CommitOpsMin:    commitOpsMin,
+		CommitOpsMax:    commitOpsMax,
+		CommitKeyPrefix: commitKeyPrefix,
+		CommitValueMin:  commitValueMin,
+		CommitValueMax:  commitValueMax,
+		// State commit batching
+		CommitInterval:          commitInterval,
+		CommitNodesMin:          commitNodesMin,
+		CommitNodesMax:          commitNodesMax,
+		CommitNodesDistribution: commitNodesDistribution,
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(runCmd)
+	// gen-dataset shares every run flag so its pairs match what run would write
+	defer genDatasetCmd.Flags().AddFlagSet(runCmd.Flags())
 
 	runCmd.Flags().IntVar(&keyCount, "key-count", 1000000, "Number of keys to use in the benchmark")
 	runCmd.Flags().Float64Var(&readRatio, "read-ratio", 0.7, "Read ratio (e.g., 0.7 = 70% reads)")
 	runCmd.Flags().IntVar(&valueSize, "value-size", 256, "Size of each value in bytes")
+	runCmd.Flags().Float64Var(&valueDupRatio, "value-dup-ratio", 0, "Fraction of written values (0-1) replaced by one of a small seeded pool of repeated values, modelling empty slots and zero balances to measure compression/dedup benefit")
+	runCmd.Flags().StringVar(&valueProfile, "value-profile", "", "Generate every written value with this profile instead of the workload's own values: random (incompressible), rlp (RLP lists of small integers and hashes), compressible (about 50% compressible) or dup-heavy (a few distinct values repeated); honours --value-size")
+	runCmd.Flags().BoolVar(&deterministicValues, "deterministic-values", false, "Derive every value from a hash of its key and --seed, so a key gets the same value from any worker in any order and every backend stores identical data (slower: seeds a generator per value)")
+	runCmd.Flags().IntVar(&valueAlign, "value-align", 0, "Pad every generated value up to a multiple of this many bytes, e.g. 4096 for page alignment (0 disables); the write phase reports the realized mean value size")
 	runCmd.Flags().Int64Var(&seed, "seed", 42, "Seed for deterministic key/value generation")
 	runCmd.Flags().StringVar(&dbPath, "db-path", "dbs/pebble/pebble-test-db", "Path to store database files (use dbs/{engine}/name pattern)")
+	runCmd.Flags().StringVar(&readDBPath, "read-db-path", "", "Run the read phase against the existing database at this path, opened read-only, instead of --db-path (e.g. write a fresh database and read from a golden dataset); empty reads --db-path")
 	runCmd.Flags().StringVar(&benchmarkID, "benchmark-id", "default", "Optional benchmark ID tag for logs")
 	runCmd.Flags().BoolVar(&writeEnabled, "write", false, "If true, write keys to DB before benchmarking")
-	runCmd.Flags().StringVar(&keysFile, "keys-file", "", "Path to binary file containing keys to read")
+	runCmd.Flags().StringVar(&keysFile, "keys-file", "", "Path to binary file containing keys to read, or operations captured by --capture-keys to replay")
+	runCmd.Flags().StringVar(&keysFormat, "keys-format", "binary", "Encoding of --keys-file or standard input: 'binary' ([uvarint length][key]), or one key per line in 'hex' (optional 0x prefix) or 'base64'; text lines are cut at the first comma so CSV columns work, and malformed lines are skipped and counted")
+	runCmd.Flags().BoolVar(&useExistingDB, "use-existing-db", false, "Open --db-path read-only and read --key-count keys sampled from its existing contents (skips the write phase)")
 	runCmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of concurrent workers for reads/writes")
+	runCmd.Flags().IntVar(&queueDepth, "queue-depth", 0, "Capacity of the worker job queue (0 for concurrency*64)")
+	runCmd.Flags().IntVar(&applyBatch, "apply-batch", 0, "Write through one batch shared by all workers and applied atomically with Pebble's Apply at the workload's block boundaries (transaction-execution, geth-schema, pos-accounts-realistic commits) or after this many writes; reports apply latency and batch sizes (0 writes with individual Sets)")
+	runCmd.Flags().IntVar(&generatorWorkers, "generator-workers", 1, "Goroutines generating write keys in parallel, each with its own seed, for workloads too expensive for one feeder to keep --concurrency workers busy. Keys arrive in nondeterministic order, and workloads whose keys ignore the seed (receipt-index) repeat keys")
+	runCmd.Flags().Float64Var(&targetOpsPerSec, "target-ops-per-sec", 0, "Pace the write and read phases to this many operations per second, reporting achieved throughput and latency at that load, timed from each operation's scheduled start (0 runs unthrottled)")
+	runCmd.Flags().BoolVar(&flushBetweenPhases, "flush-between-phases", false, "After the write phase, flush and wait for background compactions to settle before reads begin, logging the time to quiesce")
+	runCmd.Flags().Int64Var(&maxBytesWritten, "max-bytes-written", 0, "Stop the write phase once this many value bytes are written, reporting the bytes moved and MB/s; --key-count must generate at least that much (0 disables)")
+	runCmd.Flags().Int64Var(&maxBytesRead, "max-bytes-read", 0, "Stop the read phase once this many value bytes are read, reporting the bytes moved and MB/s (0 disables)")
+	runCmd.Flags().IntVar(&l0FilesTarget, "l0-files-target", 0, "Pebble: Stop the write phase as soon as L0 holds this many files and read immediately, benchmarking reads at that compaction debt (0 disables)")
+	runCmd.Flags().DurationVar(&quiesceTimeout, "quiesce-timeout", 0, "Give up waiting for background compactions to settle after this long with --flush-between-phases or --flush-wait-compaction (0 for 10m)")
+	runCmd.Flags().BoolVar(&flushWaitCompaction, "flush-wait-compaction", false, "Pebble: Make every Flush, including the one ending the write phase, also wait for pending compactions to drain so later reads run against a stable tree; logs the settle time and the running total")
+	runCmd.Flags().Int64Var(&shuffleSeed, "shuffle-seed", 0, "Seed the read key order from this instead of --seed, so the same keyset is read in a reproducible but different order; a sequential --read-order becomes shuffled (0 disables)")
+	runCmd.Flags().StringVar(&readOrder, "read-order", "sequential", "Read phase key order: 'sequential' (as written/loaded), 'random' (sampled with replacement) or 'shuffled' (each key once in random order)")
 	runCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+	runCmd.Flags().StringVar(&logLevel, "log-level", "info", "Log level: 'debug' (adds sampled per-operation details and configuration decisions), 'info', 'warn' (drops progress output, e.g. for CI) or 'error'")
 	runCmd.Flags().Int64Var(&blockCacheSize, "block-cache-size", 8<<20, "Block cache size in bytes (negative for disabled, default 8MB)")
+	runCmd.Flags().BoolVar(&syncWrites, "sync-writes", false, "Fsync the WAL on every write and report WAL append/fsync metrics after the write phase")
+	runCmd.Flags().StringVar(&hdrOutput, "hdr-output", "", "Write read/write latency histograms to this path in HdrHistogram log format (.hlog)")
+	runCmd.Flags().StringVar(&latencyCSV, "latency-csv", "", "Write every write/read phase operation to this CSV with latency_ns, key_len, value_len (bytes written or returned) and result columns")
+	runCmd.Flags().BoolVar(&summary, "summary", false, "Print an aligned summary table at the end of the run")
+	runCmd.Flags().BoolVar(&quiet, "quiet", false, "Print nothing but the final result as a single JSON line on stdout, the same object --results-file writes (errors still go to stderr), e.g. for piping into jq")
+	runCmd.Flags().BoolVar(&analyzeKeys, "analyze-keys", false, "Dry run: generate --key-count keys from the workload without opening a database and report their locality (shared prefix length between consecutive keys, distinct prefixes, coverage of the sorted key space) to tell sequential from scattered workloads")
+	runCmd.Flags().BoolVar(&estimate, "estimate", false, "Dry run: write and read back a few thousand keys in a scratch database next to --db-path, then estimate the write and read phase durations and final disk size for --key-count and exit; a database that outgrows memory slows down, so treat the time as a lower bound (requires --write)")
+	runCmd.Flags().Float64Var(&fillDisk, "fill-disk", 0, "Override --key-count to fill this fraction of the free disk space at --db-path (e.g. 0.8), estimated from a dry run of the workload's key and value sizes (requires --write)")
+	runCmd.Flags().IntVar(&populate, "populate", 0, "Write exactly N deterministic index-addressed keys instead of the workload's keys, then read --key-count keys following the workload's access pattern mapped onto them so every read hits (requires --write, 0 disables)")
+	runCmd.Flags().StringVar(&resultsDB, "results-db", "", "Append this run's result, git commit, timestamp and config to this SQLite database (see the history command)")
+	runCmd.Flags().StringVar(&resultsFile, "results-file", "", "Write this run's result as JSON to this file (see the diff command)")
+	runCmd.Flags().StringVar(&recordOps, "record-ops", "", "Append every Set/Get/Delete/iterate/compact/flush (op, hex key, value length) to this file, for replay with --replay-ops")
+	runCmd.Flags().StringVar(&replayOps, "replay-ops", "", "Replay an operation log written by --record-ops against a fresh database at --db-path instead of running the workload (requires --write)")
+	runCmd.Flags().DurationVar(&injectReadLatency, "inject-read-latency", 0, "Sleep this long before every read (Get and iterator seek) to model slower storage, e.g. 500us for network-attached volumes (0 disables)")
+	runCmd.Flags().DurationVar(&injectWriteLatency, "inject-write-latency", 0, "Sleep this long before every write (Set, Delete and batch apply) to model slower storage (0 disables)")
+	runCmd.Flags().DurationVar(&injectLatencyJitter, "inject-latency-jitter", 0, "Add a uniform random delay in [0, jitter) to every injected latency")
+	runCmd.Flags().StringVar(&captureKeys, "capture-keys", "", "Capture every written and read key with its operation type and value length to this binary keys file; passing it as --keys-file with --write replays the same writes and reads against any backend")
+	runCmd.Flags().StringVar(&cpuProfile, "cpuprofile", "", "Write a pprof CPU profile of the benchmark process to this path")
+	runCmd.Flags().StringVar(&memProfile, "memprofile", "", "Write a pprof heap profile of the benchmark process to this path at the end of the run")
+	runCmd.Flags().BoolVar(&verifyChecksums, "verify-checksums", false, "Checksum written and read key/value pairs and report whether the read phase returned exactly the written data")
+	runCmd.Flags().BoolVar(&failOnError, "fail-on-error", false, "Exit non-zero when a write or read phase's error rate exceeds --max-error-rate")
+	runCmd.Flags().Float64Var(&maxErrorRate, "max-error-rate", 0, "Fraction of failed operations tolerated per phase with --fail-on-error (0 fails on any error)")
+	runCmd.Flags().Float64Var(&p99BudgetMs, "p99-budget-ms", 0, "Exit non-zero when the write or read phase's p99 latency exceeds this many milliseconds (0 disables the check)")
+	runCmd.Flags().BoolVar(&measureGeneration, "measure-generation", false, "Time key/value generation separately from database I/O and report the split, and the p99 of generating one value against the p99 write latency")
+	runCmd.Flags().IntVar(&rangeQueries, "range-queries", 0, "Number of workload range queries to execute after the read phase (0 disables)")
+	runCmd.Flags().IntVar(&rangeLimit, "range-limit", 0, "Items each range query returns at most, overriding the limit the workload generates (0 keeps the workload's)")
+	runCmd.Flags().StringVar(&scanDirection, "scan-direction", "forward", "Range scan direction: 'forward', 'reverse', or 'both' to compare them")
+	runCmd.Flags().BoolVar(&freshnessProbe, "freshness-probe", false, "After the read phase, write a dedicated keyspace and read keys back after intervening writes to measure read latency by key age (requires --write)")
+	runCmd.Flags().IntVar(&freshnessKeys, "freshness-keys", 200000, "Number of keys written for the freshness phase")
+	runCmd.Flags().StringVar(&freshnessLags, "freshness-lags", "0,1000,10000,100000", "Comma-separated numbers of intervening writes before a key is read back")
+	runCmd.Flags().DurationVar(&freshnessDelay, "freshness-delay", 0, "Re-read all probe keys after this delay at the end of the freshness phase (0 disables)")
+	runCmd.Flags().BoolVar(&tombstoneScan, "tombstone-scan", false, "After the read phase, delete every other key of a dedicated keyspace and measure range scans before and after compaction (requires --write)")
+	runCmd.Flags().IntVar(&tombstoneKeys, "tombstone-keys", 100000, "Number of keys written for the tombstone scan phase")
+	runCmd.Flags().BoolVar(&mixed, "mixed", false, "After the read phase, run a read pool and a write pool concurrently, splitting --key-count operations by --read-ratio, and report per-pool throughput (requires --write)")
+	runCmd.Flags().IntVar(&readWorkers, "read-workers", 0, "Mixed phase: Number of read workers (0 uses --concurrency)")
+	runCmd.Flags().IntVar(&writeWorkers, "write-workers", 0, "Mixed phase: Number of write workers (0 uses --concurrency)")
+	runCmd.Flags().BoolVar(&contention, "contention", false, "After the read phase, run --concurrency writers whose keys collide at --collision-rate and report per-writer and aggregate throughput (requires --write)")
+	runCmd.Flags().IntVar(&contentionOps, "contention-ops", 100000, "Writes per writer in the contention phase")
+	runCmd.Flags().Float64Var(&collisionRate, "collision-rate", 0.1, "Probability a contention write targets the keys shared by all writers (0.0-1.0)")
+	runCmd.Flags().IntVar(&contentionHotKeys, "contention-hot-keys", 100, "Number of keys shared by all writers in the contention phase")
+	runCmd.Flags().IntVar(&snapshotReaders, "snapshot-readers", 0, "After the read phase, run this many goroutines that each open a snapshot, make --snapshot-reads reads from it and close it, --snapshots-per-reader times, reporting snapshots/sec and snapshot open and read latency; stresses Pebble's snapshot list and MDBX's reader table (see --mdbx-max-readers) (0 disables)")
+	runCmd.Flags().IntVar(&snapshotsPerReader, "snapshots-per-reader", 1000, "Snapshot phase: Snapshots each reader opens in turn")
+	runCmd.Flags().IntVar(&snapshotReads, "snapshot-reads", 8, "Snapshot phase: Reads from each snapshot before it is closed")
+	runCmd.Flags().DurationVar(&metricsInterval, "metrics-interval", 0, "Sample database metrics (cache hit ratio, L0 files, ...) at this interval and report the sampling cost (0 disables)")
+	runCmd.Flags().DurationVar(&spaceAmpInterval, "space-amp-interval", 0, "Sample space amplification (on-disk bytes / key and value bytes written) at this interval during the write phase and report its peak (0 disables)")
+	runCmd.Flags().StringVar(&fillCurve, "fill-curve", "", "Write the write phase's throughput per --fill-curve-bucket of key and value bytes written to this CSV (size_bucket, write_ops_per_sec, writes, elapsed_ms), showing how writes degrade as the database grows")
+	runCmd.Flags().Int64Var(&fillCurveBucket, "fill-curve-bucket", 1<<30, "Bytes of keys and values written per --fill-curve bucket")
+	runCmd.Flags().IntVar(&gomaxprocs, "gomaxprocs", 0, "Set GOMAXPROCS for the run (0 keeps the Go default of every CPU); the CPU count and GOMAXPROCS are recorded in the result")
+	runCmd.Flags().BoolVar(&lockOSThread, "lock-os-thread", false, "Pin every write and read worker goroutine to its own OS thread (helps MDBX, whose read transactions are thread-bound)")
+	runCmd.Flags().BoolVar(&sortKeys, "sort-keys", false, "Buffer the generated write keys in memory and write them in sorted order, the best case for bulk loading (reads keep the generated order)")
+	runCmd.Flags().BoolVar(&warmHotKeys, "warm-hot-keys", false, "Before the read phase, read every key of the workload's hot accounts and contracts to fill the cache, measuring the warm steady state of a node instead of a cold start")
+	runCmd.Flags().StringVar(&loadDataset, "load-dataset", "", "Write the key/value pairs of a gen-dataset file instead of generating them, so every backend loads identical data (requires --write; reads use the dataset's keys)")
+	runCmd.Flags().BoolVar(&pregenerateValues, "pregenerate-values", false, "Generate all values before the timed write loop (ring buffer of values for very large key counts)")
 	
 	// Database backend configuration flags
-	runCmd.Flags().StringVar(&databaseType, "database", "pebble", "Database backend: 'pebble', 'qmdb', or 'mdbx'")
+	runCmd.Flags().StringVar(&databaseType, "database", "pebble", "Database backend: 'pebble', 'qmdb', 'mdbx', or 'memory'")
 	runCmd.Flags().StringVar(&qmdbLibraryPath, "qmdb-library", "./lib/libqmdb.dylib", "Path to QMDB shared library")
+	runCmd.Flags().StringVar(&pebbleComparer, "pebble-comparer", "default", "Pebble: Key ordering, 'default' (bytewise) or 'blocknum' (prefix byte, then little-endian block number); a database must always be reopened with the comparer it was created with")
+	runCmd.Flags().BoolVar(&pebbleCopyValues, "copy-values", false, "Pebble: Copy each read value out of the block cache and close its closer inside Get, as MDBX and QMDB always do, so cross-backend read latencies include the same copy; reports the copy's share of read latency (Pebble reads are otherwise zero-copy)")
+	runCmd.Flags().BoolVar(&pebbleDisableWAL, "pebble-disable-wal", false, "Pebble: Disable the write-ahead log entirely to measure the memtable/compaction ceiling (unflushed writes are lost on crash)")
+	runCmd.Flags().StringVar(&keyPrefix, "key-prefix", "", "Store every key under this namespace prefix, e.g. 'mybench/', so writes, reads and range queries stay inside it and the run leaves other data in a shared database untouched")
+	runCmd.Flags().IntVar(&pebbleMaxOpenFiles, "pebble-max-open-files", 0, "Pebble: Limit on the files the database keeps open; warns when the process's open file limit (ulimit -n) is lower (0 uses Pebble's default of 1000)")
+	runCmd.Flags().IntVar(&pebbleTableCacheSize, "pebble-table-cache-size", 0, "Pebble: Number of sstables the table cache keeps open with their readers (0 derives it from the max open files)")
 	
 	// MDBX-specific configuration flags
 	runCmd.Flags().Int64Var(&mdbxMapSize, "mdbx-map-size", -1, "MDBX: Maximum map size in bytes (-1 for default)")
@@ -147,13 +557,30 @@ func init() {
 	runCmd.Flags().BoolVar(&mdbxNoReadahead, "mdbx-no-readahead", false, "MDBX: Disable readahead")
 	
 	// Workload configuration flags
-	runCmd.Flags().StringVar(&workloadType, "workload", "generic", "Workload type: generic, pos-blocks, pos-accounts, pos-state, pos-mixed, pos-accounts-realistic, pos-state-realistic, transaction-execution")
+	runCmd.Flags().StringVar(&workloadType, "workload", "generic", "Workload type: generic, pos-blocks, pos-accounts, pos-state, pos-mixed, pos-accounts-realistic, pos-state-realistic, transaction-execution, update, storage-trie, mixed-values, receipt-index, geth-schema, merkle-proof, storage-dump, reorg, account-existence")
+	runCmd.Flags().StringVar(&blend, "blend", "", "Weighted workload blend overriding --workload, e.g. 'pos-blocks:0.2,pos-accounts:0.5,pos-state:0.3' (weights must sum to 1.0)")
+	runCmd.Flags().StringVar(&readRatios, "read-ratios", "", "pos-mixed: Read probability per key prefix picking the --mixed phase reads, overriding the built-in ones, e.g. 'h=0.9,a=0.85,o=0.95' to model a specific node role")
 	runCmd.Flags().Float64Var(&recentBlockBias, "recent-block-bias", 0.8, "PoS: Probability of accessing recent blocks (0.0-1.0)")
 	runCmd.Flags().Float64Var(&hotAccountRatio, "hot-account-ratio", 0.2, "PoS: Ratio of hot accounts that get most access (0.0-1.0)")
+	runCmd.Flags().IntVar(&hotAccountCount, "hot-account-count", 0, "Exact number of hot accounts for pos-accounts, pos-accounts-realistic and transaction-execution (0 derives it from --account-count); size --block-cache-size against this working set")
 	runCmd.Flags().Float64Var(&stateLocality, "state-locality", 0.3, "PoS: Probability of accessing related state (0.0-1.0)")
 	runCmd.Flags().IntVar(&blockRange, "block-range", 100000, "PoS: Range of block numbers to simulate")
+	runCmd.Flags().StringVar(&blockKeyLayout, "block-key-layout", "be", "PoS blocks: Block number encoding in keys, 'be' (big-endian) or 'le' (little-endian, pair with --pebble-comparer blocknum)")
 	runCmd.Flags().IntVar(&accountCount, "account-count", 100000, "PoS: Number of unique accounts to simulate")
 	runCmd.Flags().Float64Var(&storageSlotRatio, "storage-slot-ratio", 5.0, "PoS: Average storage slots per account")
+	runCmd.Flags().IntVar(&storageTrieDepth, "storage-trie-depth", 0, "Storage trie: Depth of each contract's storage trie (0 for default, max 64)")
+	runCmd.Flags().IntVar(&trieAverageDepth, "trie-average-depth", 0, "pos-accounts-realistic/pos-state-realistic/storage-trie: Average state trie depth (0 for default 6)")
+	runCmd.Flags().IntVar(&trieMaxDepth, "trie-max-depth", 0, "pos-accounts-realistic/pos-state-realistic/storage-trie: Maximum state trie depth (0 for default 16)")
+	runCmd.Flags().IntVar(&trieDepthVariance, "trie-depth-variance", -1, "pos-accounts-realistic/storage-trie: Spread of the per-path depth around the average (-1 for default 2, 0 for a fixed depth)")
+	runCmd.Flags().StringVar(&trieDepthDistribution, "trie-depth-distribution", "uniform", "pos-accounts-realistic/storage-trie: Depth distribution around the average, 'uniform' (+/- variance) or 'normal' (standard deviation variance)")
+	runCmd.Flags().StringVar(&trieNodeEncoding, "trie-node-encoding", "", "Trie node values: 'rlp' for RLP-encoded branch, extension and leaf nodes, 'random' for random bytes of the same sizes (compresses differently), empty for each workload's default (rlp for pos-accounts, geth-schema and merkle-proof, random otherwise)")
+	runCmd.Flags().IntVar(&contractCount, "contract-count", 100, "Storage trie and storage dump: Number of contracts whose storage tries are traversed or whose storage is dumped")
+	runCmd.Flags().Float64Var(&largeValueRatio, "large-value-ratio", 0.1, "Mixed values: Fraction of keys holding large block body values, the rest hold 32-byte storage slots (0.0-1.0)")
+	runCmd.Flags().IntVar(&largeValueSize, "large-value-size", 16<<10, "Mixed values: Size of large values in bytes")
+	runCmd.Flags().IntVar(&keyspace, "keyspace", 100000, "Update: Number of unique keys populated before --key-count overwrites begin")
+	runCmd.Flags().IntVar(&reorgInterval, "reorg-interval", 100, "Reorg: Blocks appended between reorgs")
+	runCmd.Flags().IntVar(&reorgDepth, "reorg-depth", 2, "Reorg: Most recent blocks each reorg deletes and rewrites")
+	runCmd.Flags().Float64Var(&existenceHitRatio, "existence-hit-ratio", 0.5, "Account existence: Fraction of reads targeting written accounts, the rest check fresh addresses that miss (0.0-1.0)")
 	
 	// Transaction execution workload flags
 	runCmd.Flags().StringVar(&networkType, "network-type", "ethereum", "TX: Network type (ethereum, polygon, testnet, custom)")
@@ -165,6 +592,7 @@ func init() {
 	runCmd.Flags().IntVar(&txStorageTrieDepth, "tx-storage-trie-depth", -1, "TX: Storage trie depth (-1 for network default)")
 	runCmd.Flags().Float64Var(&txReadWriteRatio, "tx-read-write-ratio", -1, "TX: Read/write ratio (-1 for network default)")
 	runCmd.Flags().Float64Var(&txContractRatio, "tx-contract-ratio", -1, "TX: Contract ratio (0.0-1.0, -1 for network default)")
+	runCmd.Flags().Float64Var(&txStorageOpMultiplier, "storage-op-multiplier", 1, "TX: Scale every transaction's storage operations, and the storage trie operations they cause, by this factor (e.g. 3 for storage-heavy, 0.5 for account-heavy)")
 	runCmd.Flags().IntVar(&txPerBlock, "tx-per-block", 100, "TX: Transactions per block")
 	runCmd.Flags().Uint64Var(&gasTargetPerBlock, "gas-target-per-block", 15000000, "TX: Target gas per block")
 	runCmd.Flags().Float64Var(&txSimpleTransferRatio, "tx-simple-transfer-ratio", -1, "TX: Simple transfer ratio (0.0-1.0, -1 for mix default)")
@@ -172,4 +600,14 @@ func init() {
 	runCmd.Flags().Float64Var(&txUniswapSwapRatio, "tx-uniswap-swap-ratio", -1, "TX: Uniswap swap ratio (0.0-1.0, -1 for mix default)")
 	runCmd.Flags().Float64Var(&txComplexDeFiRatio, "tx-complex-defi-ratio", -1, "TX: Complex DeFi ratio (0.0-1.0, -1 for mix default)")
 	runCmd.Flags().Float64Var(&txContractDeployRatio, "tx-contract-deploy-ratio", -1, "TX: Contract deployment ratio (0.0-1.0, -1 for mix default)")
+	runCmd.Flags().DurationVar(&blockTime, "block-time", 0, "TX: Pace writes to one block per interval, e.g. 12s for Ethereum or 2s for Polygon, and report whether blocks keep up (0 writes as fast as possible)")
+	runCmd.Flags().IntVar(&commitOpsMin, "commit-ops-min", 5, "TX: Fewest operations written per block commit")
+	runCmd.Flags().IntVar(&commitOpsMax, "commit-ops-max", 14, "TX: Most operations written per block commit, raise to model chains with larger state-diff commits")
+	runCmd.Flags().StringVar(&commitKeyPrefix, "commit-key-prefix", "block:", "TX: Key prefix of block commit operations")
+	runCmd.Flags().IntVar(&commitValueMin, "commit-value-min", 500, "TX: Smallest block commit value in bytes")
+	runCmd.Flags().IntVar(&commitValueMax, "commit-value-max", 5499, "TX: Largest block commit value in bytes")
+	runCmd.Flags().IntVar(&commitInterval, "commit-interval", 0, "pos-accounts-realistic: Commit after every this many logical operations, modelling per-block commits (0 commits at random, 5% of operations)")
+	runCmd.Flags().IntVar(&commitNodesMin, "commit-nodes-min", 10, "pos-accounts-realistic: Fewest dirty trie nodes written per commit")
+	runCmd.Flags().IntVar(&commitNodesMax, "commit-nodes-max", 59, "pos-accounts-realistic: Most dirty trie nodes written per commit")
+	runCmd.Flags().StringVar(&commitNodesDistribution, "commit-nodes-distribution", "uniform", "pos-accounts-realistic: Dirty node count distribution between the min and max, 'uniform' or 'normal' (centred, standard deviation a sixth of the range)")
 }
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var (
+	scanStart string
+	scanEnd   string
+	scanLimit int
+)
+
+// scanCmd represents the scan command
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Time a range scan over an existing database",
+	Run: func(cmd *cobra.Command, args []string) {
+		start, err := hex.DecodeString(scanStart)
+		if err != nil {
+			log.Fatalf("Invalid --start: %v", err)
+		}
+		end, err := hex.DecodeString(scanEnd)
+		if err != nil {
+			log.Fatalf("Invalid --end: %v", err)
+		}
+
+		cfg := benchmark.ScanConfig{
+			DBPath:         dbPath,
+			DatabaseType:   databaseType,
+			BlockCacheSize: blockCacheSize,
+			LogFormat:      logFormat,
+			Limit:          scanLimit,
+			Direction:      benchmark.ScanDirection(scanDirection),
+			PebbleComparer: pebbleComparer,
+		}
+		if len(start) > 0 {
+			cfg.Start = start
+		}
+		if len(end) > 0 {
+			cfg.End = end
+		}
+
+		if err := benchmark.RunScan(cfg); err != nil {
+			log.Fatalf("Scan failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().StringVar(&dbPath, "db-path", "dbs/pebble/pebble-test-db", "Path to the existing database")
+	scanCmd.Flags().StringVar(&databaseType, "database", "pebble", "Database backend: 'pebble' or 'mdbx'")
+	scanCmd.Flags().Int64Var(&blockCacheSize, "block-cache-size", 8<<20, "Block cache size in bytes (negative for disabled, default 8MB)")
+	scanCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+	scanCmd.Flags().StringVar(&scanStart, "start", "", "Hex-encoded inclusive lower bound (empty for the first key)")
+	scanCmd.Flags().StringVar(&scanEnd, "end", "", "Hex-encoded exclusive upper bound (empty for past the last key)")
+	scanCmd.Flags().IntVar(&scanLimit, "limit", 0, "Stop after this many items (0 for no limit)")
+	scanCmd.Flags().StringVar(&pebbleComparer, "pebble-comparer", "default", "Pebble: Key ordering the database was created with, 'default' or 'blocknum'")
+	scanCmd.Flags().StringVar(&scanDirection, "scan-direction", "forward", "Scan direction: 'forward', 'reverse', or 'both' to compare them")
+}
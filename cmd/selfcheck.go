@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var selfCheckBackends []string
+
+// selfCheckCmd represents the selfcheck command
+var selfCheckCmd = &cobra.Command{
+	Use:   "selfcheck",
+	Short: "Write, read, delete and iterate a few keys on every backend in a temporary database and report PASS/FAIL/SKIP",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := benchmark.SelfCheckConfig{
+			Backends:        selfCheckBackends,
+			LogFormat:       logFormat,
+			QMDBLibraryPath: qmdbLibraryPath,
+			MDBXMapSize:     mdbxMapSize,
+		}
+
+		if err := benchmark.RunSelfCheck(cfg); err != nil {
+			log.Fatalf("Self-check failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selfCheckCmd)
+
+	selfCheckCmd.Flags().StringSliceVar(&selfCheckBackends, "backends", []string{"pebble", "mdbx", "qmdb", "memory"}, "Backends to check")
+	selfCheckCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+	selfCheckCmd.Flags().StringVar(&qmdbLibraryPath, "qmdb-library", "./lib/libqmdb.dylib", "Path to QMDB shared library")
+	selfCheckCmd.Flags().Int64Var(&mdbxMapSize, "mdbx-map-size", -1, "MDBX: Maximum map size in bytes (-1 for default)")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/tclemos/pebble-bench/benchmark"
+)
+
+var (
+	verifyPathA     string
+	verifyDatabaseA string
+	verifyPathB     string
+	verifyDatabaseB string
+)
+
+// verifyDBsCmd represents the verify-dbs command
+var verifyDBsCmd = &cobra.Command{
+	Use:   "verify-dbs",
+	Short: "Compare two existing databases key by key and report the first key or value that differs",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := benchmark.VerifyDBsConfig{
+			PathA:          verifyPathA,
+			DatabaseTypeA:  verifyDatabaseA,
+			PathB:          verifyPathB,
+			DatabaseTypeB:  verifyDatabaseB,
+			BlockCacheSize: blockCacheSize,
+			MDBXMapSize:    mdbxMapSize,
+			LogFormat:      logFormat,
+		}
+
+		if err := benchmark.RunVerifyDBs(cfg); err != nil {
+			log.Fatalf("Verify failed: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyDBsCmd)
+
+	verifyDBsCmd.Flags().StringVar(&verifyPathA, "db-path-a", "", "Path to the first database")
+	verifyDBsCmd.Flags().StringVar(&verifyDatabaseA, "database-a", "pebble", "Backend of the first database: 'pebble' or 'mdbx'")
+	verifyDBsCmd.Flags().StringVar(&verifyPathB, "db-path-b", "", "Path to the second database")
+	verifyDBsCmd.Flags().StringVar(&verifyDatabaseB, "database-b", "pebble", "Backend of the second database: 'pebble' or 'mdbx'")
+	verifyDBsCmd.Flags().Int64Var(&blockCacheSize, "block-cache-size", 8<<20, "Block cache size in bytes (negative for disabled, default 8MB)")
+	verifyDBsCmd.Flags().Int64Var(&mdbxMapSize, "mdbx-map-size", -1, "MDBX: Maximum map size in bytes (-1 for default)")
+	verifyDBsCmd.Flags().StringVar(&logFormat, "log-format", "console", "Log format: 'json' or 'console'")
+	verifyDBsCmd.MarkFlagRequired("db-path-a")
+	verifyDBsCmd.MarkFlagRequired("db-path-b")
+}